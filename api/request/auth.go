@@ -1,6 +1,12 @@
 package request
 
 // auth
+type LoginReq struct {
+	Forge string `form:"forge"` //代码托管平台,默认为github
+}
+
 type CallBackReq struct {
-	Code string `form:"code"`
+	Code  string `form:"code"`
+	Forge string `form:"forge"`
+	State string `form:"state"` //登录时携带的state,即代码托管平台的名称
 }
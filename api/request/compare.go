@@ -0,0 +1,7 @@
+package request
+
+type CompareUsers struct {
+	IDs     []int64  `form:"id"`      //用户的user_id,可以重复多次
+	Logins  []string `form:"login"`   //github的登录名,可以重复多次,和id合计2到5个
+	Summary bool     `form:"summary"` //是否生成llm的对比总结
+}
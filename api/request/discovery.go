@@ -0,0 +1,20 @@
+package request
+
+type CreateDiscovery struct {
+	Name         string `json:"name"`
+	Keyword      string `json:"keyword"`       //搜索关键词,可以为空
+	Location     string `json:"location"`      //例如 Berlin
+	Language     string `json:"language"`      //例如 Go
+	MinFollowers int    `json:"min_followers"` //粉丝数需要大于这个值
+	Limit        int    `json:"limit"`         //最多导入的用户数,不超过100
+}
+
+type GetDiscovery struct {
+	ID int64 `form:"id"`
+}
+
+type GetDiscoveryResults struct {
+	ID       int64 `form:"id"`
+	Page     int   `form:"page"`
+	PageSize int   `form:"page_size"`
+}
@@ -0,0 +1,8 @@
+package request
+
+type MatchJob struct {
+	JobDescription string  `json:"job_description"`
+	Nation         *string `json:"nation,omitempty"` //只匹配这个国家的用户,选择性参数
+	MinScore       float64 `json:"min_score"`        //评分需要大于等于这个值
+	Limit          int     `json:"limit"`            //最多返回的候选人数,默认20,不超过100
+}
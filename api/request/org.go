@@ -0,0 +1,11 @@
+package request
+
+type GetOrg struct {
+	Login string `form:"login"`
+}
+
+type GetOrgMembers struct {
+	Login    string `form:"login"`
+	Page     int    `form:"page" binding:"required,min=1"`
+	PageSize int    `form:"page_size" binding:"required,min=1,max=100"` //组织最多存储100个成员
+}
@@ -0,0 +1,11 @@
+package request
+
+type SimilarUsers struct {
+	UserId int64 `form:"user_id"` //不填时查找和自己相似的用户
+	Limit  int   `form:"limit"`   //最多返回的用户数,默认20,不超过100
+}
+
+type SemanticSearch struct {
+	Query string `form:"q"`     //自由文本的描述,例如 "熟悉分布式存储的Rust开发者"
+	Limit int    `form:"limit"` //最多返回的用户数,默认20,不超过100
+}
@@ -1,12 +1,42 @@
 package request
 
 type SearchUser struct {
-	Domain   string  `form:"domain"`
-	Nation   *string `form:"nation,omitempty"`
-	Page     int     `form:"page"`
-	PageSize int     `form:"page_size"`
+	Domain        string  `form:"domain"`
+	Nation        *string `form:"nation,omitempty"`
+	MinConfidence float64 `form:"min_confidence"` //领域和国籍的最低置信度
+	Page          int     `form:"page"`
+	PageSize      int     `form:"page_size"`
 }
 
 type GetUserInfo struct {
 	UserId int64 `form:"user_id"`
 }
+
+type GetPublicUser struct {
+	Login string `form:"login"`
+	Infer bool   `form:"infer"`
+}
+
+type GetEvaluation struct {
+	Refresh bool `form:"refresh"` //是否强制重新生成
+}
+
+type GetEvaluationHistory struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size"`
+}
+
+type GetLLMUsage struct {
+	Days     int `form:"days"` //统计最近多少天,默认7天
+	Page     int `form:"page"`
+	PageSize int `form:"page_size"`
+}
+
+type GetDeadJobs struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size"`
+}
+
+type RequeueJob struct {
+	ID int64 `form:"id"`
+}
@@ -1,21 +1,33 @@
 package response
 
-import "github.com/GitEval/GitEval-Backend/model"
+import (
+	"github.com/GitEval/GitEval-Backend/model"
+	"time"
+)
 
 type Success struct {
 	Data interface{} `json:"data"`
 	Msg  string      `json:"msg"`
 }
 type Err struct {
-	Err error `json:"error"`
+	Err  error  `json:"error"`
+	Code string `json:"code,omitempty"` //错误码,方便前端区分错误类型
 }
+
+// CodeLLMUnavailable llm服务暂时不可用,稍后重试即可
+const CodeLLMUnavailable = "LLM_UNAVAILABLE"
+
+// CodeAlreadyRunning 同一个用户相同的推断正在执行,等待执行结束之后再获取
+const CodeAlreadyRunning = "ALREADY_RUNNING"
+
 type CallBack struct {
 	Token string `json:"token"`
 }
 
 type User struct {
-	U      model.User `json:"user"`
-	Domain []string   `json:"domain"`
+	U         model.User       `json:"user"`
+	Domain    []model.Domain   `json:"domain"`
+	Interests []model.Interest `json:"interests"`
 }
 
 type Ranking struct {
@@ -23,16 +35,86 @@ type Ranking struct {
 }
 
 type EvaluationResp struct {
-	Evaluation string `json:"evaluation"`
+	Evaluation    string                 `json:"evaluation"` //叙述性的评价
+	ModelVersion  string                 `json:"model_version"`
+	PromptVersion string                 `json:"prompt_version"`
+	Scores        model.EvaluationScores `json:"scores"`
+	Strengths     []string               `json:"strengths"`
+	Weaknesses    []string               `json:"weaknesses"`
+	Evidence      []model.EvidenceLink   `json:"evidence"`
+	CreatedAt     time.Time              `json:"created_at"` //生成评价的时间
+}
+
+func NewEvaluationResp(e model.Evaluation) EvaluationResp {
+	return EvaluationResp{
+		Evaluation:    e.Evaluation,
+		ModelVersion:  e.ModelVersion,
+		PromptVersion: e.PromptVersion,
+		Scores:        e.Scores,
+		Strengths:     e.Strengths,
+		Weaknesses:    e.Weaknesses,
+		Evidence:      e.Evidence,
+		CreatedAt:     e.CreatedAt,
+	}
+}
+
+type EvaluationHistoryResp struct {
+	Evaluations []model.Evaluation `json:"evaluations"`
+}
+
+type MatchResp struct {
+	Requirements model.JobRequirements `json:"requirements"` //从职位描述中提取的要求
+	Candidates   []model.Candidate     `json:"candidates"`   //按照rank从高到低排序
+}
+
+type CompareResp struct {
+	Comparison model.Comparison `json:"comparison"`
+}
+
+type DeadJobsResp struct {
+	Jobs []model.Job `json:"jobs"` //按照最后一次失败的时间倒序
+}
+
+type SimilarResp struct {
+	Users []model.SimilarUser `json:"users"` //按照相似度从高到低排序
+}
+
+type LLMUsageResp struct {
+	Daily []model.LLMUsage `json:"daily"` //按天聚合,按日期倒序
+	Users []model.LLMUsage `json:"users"` //按用户聚合,按token用量倒序
 }
 
 type NationResp struct {
-	Nation string `json:"nation"`
+	Nation     string  `json:"nation"`
+	Confidence float32 `json:"confidence"`
+	Cached     bool    `json:"cached"` //输入没有变化,直接使用了缓存的推断结果
 }
 
 type DomainResp struct {
-	Domain []string `json:"domain"`
+	Domain []model.Domain `json:"domain"`
+	Cached bool           `json:"cached"` //输入没有变化,直接使用了缓存的推断结果
 }
 type SearchResp struct {
 	Users []model.User `json:"users"`
 }
+
+type OrgsResp struct {
+	Orgs []model.Organization `json:"orgs"`
+}
+
+type OrgMembersResp struct {
+	Users []model.User `json:"users"`
+}
+
+type DiscoveriesResp struct {
+	Queries []model.DiscoveryQuery `json:"queries"`
+}
+
+type DiscoveryResultsResp struct {
+	Query   model.DiscoveryQuery  `json:"query"`
+	Results []model.DiscoveryUser `json:"results"`
+}
+
+type TicketResp struct {
+	Ticket string `json:"ticket"`
+}
@@ -1,11 +1,13 @@
 package route
 
 import (
+	"context"
 	"github.com/GitEval/GitEval-Backend/conf"
 	"github.com/GitEval/GitEval-Backend/middleware"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
+	"log"
 	"time"
 )
 
@@ -15,19 +17,44 @@ var ProviderSet = wire.NewSet(
 )
 
 type App struct {
-	r *gin.Engine
-	c *conf.AppConf
+	r       *gin.Engine
+	c       *conf.AppConf
+	workers []Worker
 }
 
-func NewApp(r *gin.Engine, c *conf.AppConf) App {
+// Worker 随着app一起启动的后台任务
+type Worker interface {
+	Start(ctx context.Context)
+}
+type CrawlerWorker interface {
+	Worker
+}
+type InferenceWorker interface {
+	Worker
+}
+type EmbeddingWorker interface {
+	Worker
+}
+type QueueWorker interface {
+	Worker
+}
+type RefreshWorker interface {
+	Worker
+}
+
+func NewApp(r *gin.Engine, c *conf.AppConf, crawler CrawlerWorker, inference InferenceWorker, embedding EmbeddingWorker, queue QueueWorker, refresh RefreshWorker) App {
 	return App{
-		r: r,
-		c: c,
+		r:       r,
+		c:       c,
+		workers: []Worker{crawler, inference, embedding, queue, refresh},
 	}
 }
 
 // 启动
 func (a *App) Run() {
+	for _, w := range a.workers {
+		go w.Start(context.Background())
+	}
 	a.r.Run(a.c.Addr)
 }
 
@@ -40,15 +67,56 @@ type UserControllerProxy interface {
 	GetUser(ctx *gin.Context)
 	GetRanking(ctx *gin.Context)
 	GetEvaluation(ctx *gin.Context)
+	StreamEvaluation(ctx *gin.Context)
+	GetEvaluationHistory(ctx *gin.Context)
 	GetNation(ctx *gin.Context)
 	GetDomain(ctx *gin.Context)
 	SearchUser(ctx *gin.Context)
 	GetUserInfo(ctx *gin.Context)
 }
+type OrgControllerProxy interface {
+	GetOrgs(ctx *gin.Context)
+	GetOrgInfo(ctx *gin.Context)
+	GetOrgMembers(ctx *gin.Context)
+	RefreshOrg(ctx *gin.Context)
+}
+type PublicControllerProxy interface {
+	GetUser(ctx *gin.Context)
+}
+type MatchControllerProxy interface {
+	MatchJob(ctx *gin.Context)
+}
+type SimilarControllerProxy interface {
+	SimilarUsers(ctx *gin.Context)
+	SemanticSearch(ctx *gin.Context)
+}
+type CompareControllerProxy interface {
+	CompareUsers(ctx *gin.Context)
+}
+type SyncControllerProxy interface {
+	GetSyncStatus(ctx *gin.Context)
+	WatchSyncStatus(ctx *gin.Context)
+	CreateSyncTicket(ctx *gin.Context)
+}
+type AdminControllerProxy interface {
+	GetLLMUsage(ctx *gin.Context)
+	GetDeadJobs(ctx *gin.Context)
+	RequeueJob(ctx *gin.Context)
+}
+type DiscoveryControllerProxy interface {
+	CreateQuery(ctx *gin.Context)
+	RunQuery(ctx *gin.Context)
+	GetQueries(ctx *gin.Context)
+	GetResults(ctx *gin.Context)
+}
 
-func NewRouter(authController AuthControllerProxy, userController UserControllerProxy, m *middleware.Middleware) *gin.Engine {
+func NewRouter(authController AuthControllerProxy, userController UserControllerProxy, orgController OrgControllerProxy, publicController PublicControllerProxy, discoveryController DiscoveryControllerProxy, matchController MatchControllerProxy, similarController SimilarControllerProxy, compareController CompareControllerProxy, syncController SyncControllerProxy, adminController AdminControllerProxy, m *middleware.Middleware, app *conf.AppConf) *gin.Engine {
 
 	r := gin.New()
+	//默认信任所有代理,任何人都可以通过X-Forwarded-For伪造ip绕过限流,只信任配置的代理
+	if err := r.SetTrustedProxies(app.TrustedProxies); err != nil {
+		log.Fatalf("invalid trusted proxies: %v", err)
+	}
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 	// 添加 CORS 中间件
@@ -65,10 +133,50 @@ func NewRouter(authController AuthControllerProxy, userController UserController
 	userGroup.GET("/getInfo", m.AuthMiddleware(), userController.GetUser)
 	userGroup.GET("/getRank", m.AuthMiddleware(), userController.GetRanking)
 	userGroup.GET("/getEvaluation", m.AuthMiddleware(), userController.GetEvaluation)
+	userGroup.GET("/getEvaluation/stream", m.AuthMiddleware(), userController.StreamEvaluation)
+	userGroup.GET("/getEvaluationHistory", m.AuthMiddleware(), userController.GetEvaluationHistory)
 	userGroup.GET("/getNation", m.AuthMiddleware(), userController.GetNation)
 	userGroup.GET("/getDomain", m.AuthMiddleware(), userController.GetDomain)
 	userGroup.GET("/search", m.AuthMiddleware(), userController.SearchUser)
 	userGroup.GET("/getUserInfo", m.AuthMiddleware(), userController.GetUserInfo)
+	userGroup.GET("/compare", m.AuthMiddleware(), compareController.CompareUsers)
+	userGroup.GET("/syncStatus", m.AuthMiddleware(), syncController.GetSyncStatus)
+	userGroup.POST("/syncStatus/ticket", m.AuthMiddleware(), syncController.CreateSyncTicket)
+	//浏览器的websocket不能设置请求头,通过一次性的票据认证
+	userGroup.GET("/syncStatus/ws", syncController.WatchSyncStatus)
+
+	//组织服务
+	orgGroup := g.Group("/org")
+	orgGroup.GET("/list", m.AuthMiddleware(), orgController.GetOrgs)
+	orgGroup.GET("/getInfo", m.AuthMiddleware(), orgController.GetOrgInfo)
+	orgGroup.GET("/members", m.AuthMiddleware(), orgController.GetOrgMembers)
+	orgGroup.POST("/refresh", m.AuthMiddleware(), orgController.RefreshOrg)
+
+	//公开服务,不需要登录但是需要限流
+	publicGroup := g.Group("/public", m.RateLimitMiddleware())
+	publicGroup.GET("/user", publicController.GetUser)
+
+	//人才发现服务
+	discoveryGroup := g.Group("/discovery")
+	discoveryGroup.POST("/create", m.AuthMiddleware(), discoveryController.CreateQuery)
+	discoveryGroup.GET("/run", m.AuthMiddleware(), discoveryController.RunQuery)
+	discoveryGroup.GET("/list", m.AuthMiddleware(), discoveryController.GetQueries)
+	discoveryGroup.GET("/results", m.AuthMiddleware(), discoveryController.GetResults)
+
+	//职位匹配服务
+	matchGroup := g.Group("/match")
+	matchGroup.POST("/job", m.AuthMiddleware(), matchController.MatchJob)
+
+	//相似开发者和语义搜索
+	similarGroup := g.Group("/similar")
+	similarGroup.GET("/user", m.AuthMiddleware(), similarController.SimilarUsers)
+	similarGroup.GET("/search", m.AuthMiddleware(), similarController.SemanticSearch)
+
+	//管理服务
+	adminGroup := g.Group("/admin", m.AuthMiddleware(), m.AdminMiddleware())
+	adminGroup.GET("/llmUsage", adminController.GetLLMUsage)
+	adminGroup.GET("/deadJobs", adminController.GetDeadJobs)
+	adminGroup.POST("/requeueJob", adminController.RequeueJob)
 
 	return r
 }
@@ -0,0 +1,242 @@
+package client
+
+import (
+	"context"
+	"errors"
+	llmv1 "github.com/GitEval/GitEval-Backend/client/gen"
+	"github.com/GitEval/GitEval-Backend/conf"
+	"github.com/GitEval/GitEval-Backend/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 写入审计记录的超时时间
+const auditTimeout = 5 * time.Second
+
+type userIDKey struct{}
+
+// WithUserID 标记这次调用是由哪个用户触发的,会记录在审计日志中
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+func userIDFromContext(ctx context.Context) int64 {
+	userID, _ := ctx.Value(userIDKey{}).(int64)
+	return userID
+}
+
+type AuditDAOProxy interface {
+	CreateLLMAudit(ctx context.Context, audit *model.LLMAudit) error
+}
+
+// AuditClient 记录每次调用的输入大小,耗时,状态,响应和token用量
+// 包在 LLMClient 外层,所以记录的耗时包括了重试
+type AuditClient struct {
+	next    llmv1.LLMServiceClient
+	dao     AuditDAOProxy
+	backend string
+}
+
+// NewAuditClient 没有开启审计时直接返回 LLMClient
+func NewAuditClient(next *LLMClient, dao AuditDAOProxy, config *conf.LLMConfig) llmv1.LLMServiceClient {
+	if !config.Audit {
+		return next
+	}
+	return &AuditClient{next: next, dao: dao, backend: config.Backend}
+}
+
+func (a *AuditClient) GetEvaluation(ctx context.Context, in *llmv1.GetEvaluationRequest, opts ...grpc.CallOption) (*llmv1.GetEvaluationResponse, error) {
+	start := time.Now()
+	res, err := a.next.GetEvaluation(ctx, in, opts...)
+	a.record(ctx, "GetEvaluation", in, res, res.GetUsage(), start, err)
+	return res, err
+}
+
+func (a *AuditClient) GetArea(ctx context.Context, in *llmv1.GetAreaRequest, opts ...grpc.CallOption) (*llmv1.GetAreaResponse, error) {
+	start := time.Now()
+	res, err := a.next.GetArea(ctx, in, opts...)
+	a.record(ctx, "GetArea", in, res, res.GetUsage(), start, err)
+	return res, err
+}
+
+func (a *AuditClient) GetDomain(ctx context.Context, in *llmv1.GetDomainRequest, opts ...grpc.CallOption) (*llmv1.GetDomainResponse, error) {
+	start := time.Now()
+	res, err := a.next.GetDomain(ctx, in, opts...)
+	a.record(ctx, "GetDomain", in, res, res.GetUsage(), start, err)
+	return res, err
+}
+
+func (a *AuditClient) ExtractRequirements(ctx context.Context, in *llmv1.ExtractRequirementsRequest, opts ...grpc.CallOption) (*llmv1.ExtractRequirementsResponse, error) {
+	start := time.Now()
+	res, err := a.next.ExtractRequirements(ctx, in, opts...)
+	a.record(ctx, "ExtractRequirements", in, res, res.GetUsage(), start, err)
+	return res, err
+}
+
+func (a *AuditClient) BatchGetDomain(ctx context.Context, in *llmv1.BatchGetDomainRequest, opts ...grpc.CallOption) (*llmv1.BatchGetDomainResponse, error) {
+	start := time.Now()
+	res, err := a.next.BatchGetDomain(ctx, in, opts...)
+	usages := make([]*llmv1.Usage, 0, len(res.GetResponses()))
+	for _, r := range res.GetResponses() {
+		usages = append(usages, r.GetUsage())
+	}
+	a.record(ctx, "BatchGetDomain", in, res, sumUsage(usages), start, err)
+	return res, err
+}
+
+func (a *AuditClient) BatchGetArea(ctx context.Context, in *llmv1.BatchGetAreaRequest, opts ...grpc.CallOption) (*llmv1.BatchGetAreaResponse, error) {
+	start := time.Now()
+	res, err := a.next.BatchGetArea(ctx, in, opts...)
+	usages := make([]*llmv1.Usage, 0, len(res.GetResponses()))
+	for _, r := range res.GetResponses() {
+		usages = append(usages, r.GetUsage())
+	}
+	a.record(ctx, "BatchGetArea", in, res, sumUsage(usages), start, err)
+	return res, err
+}
+
+func (a *AuditClient) CompareUsers(ctx context.Context, in *llmv1.CompareUsersRequest, opts ...grpc.CallOption) (*llmv1.CompareUsersResponse, error) {
+	start := time.Now()
+	res, err := a.next.CompareUsers(ctx, in, opts...)
+	a.record(ctx, "CompareUsers", in, res, res.GetUsage(), start, err)
+	return res, err
+}
+
+// Embed 向量太大,只记录模型和用量
+func (a *AuditClient) Embed(ctx context.Context, in *llmv1.EmbedRequest, opts ...grpc.CallOption) (*llmv1.EmbedResponse, error) {
+	start := time.Now()
+	res, err := a.next.Embed(ctx, in, opts...)
+	a.record(ctx, "Embed", in, &llmv1.EmbedResponse{Model: res.GetModel(), Usage: res.GetUsage()}, res.GetUsage(), start, err)
+	return res, err
+}
+
+// StreamEvaluation 流结束时才记录,响应中的delta是完整的评价
+// 调用方不再读取流时(例如客户端断开)不会收到结束的消息,ctx取消时同样记录一次
+func (a *AuditClient) StreamEvaluation(ctx context.Context, in *llmv1.GetEvaluationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[llmv1.StreamEvaluationResponse], error) {
+	start := time.Now()
+	stream, err := a.next.StreamEvaluation(ctx, in, opts...)
+	if err != nil {
+		a.record(ctx, "StreamEvaluation", in, nil, nil, start, err)
+		return nil, err
+	}
+	s := &auditStream{ServerStreamingClient: stream, a: a, ctx: ctx, in: in, start: start}
+	//持有锁注册回调,ctx已经取消时回调要等stop赋值之后才能执行
+	s.mu.Lock()
+	s.stop = context.AfterFunc(ctx, func() {
+		s.finish(nil, ctx.Err())
+	})
+	s.mu.Unlock()
+	return s, nil
+}
+
+type auditStream struct {
+	grpc.ServerStreamingClient[llmv1.StreamEvaluationResponse]
+	a     *AuditClient
+	ctx   context.Context
+	in    *llmv1.GetEvaluationRequest
+	start time.Time
+	stop  func() bool
+	//ctx取消时的回调在另一个协程中执行
+	mu       sync.Mutex
+	delta    strings.Builder
+	recorded bool
+}
+
+func (s *auditStream) Recv() (*llmv1.StreamEvaluationResponse, error) {
+	res, err := s.ServerStreamingClient.Recv()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case err == io.EOF:
+		s.record(nil, nil)
+	case err != nil:
+		s.record(nil, err)
+	case res.Done:
+		s.delta.WriteString(res.Delta)
+		final := proto.Clone(res).(*llmv1.StreamEvaluationResponse)
+		final.Delta = s.delta.String()
+		s.record(final, nil)
+	default:
+		s.delta.WriteString(res.Delta)
+	}
+	return res, err
+}
+
+// finish 加锁之后记录
+func (s *auditStream) finish(res *llmv1.StreamEvaluationResponse, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.record(res, err)
+}
+
+// record 一个流只记录一次,调用时需要持有锁
+func (s *auditStream) record(res *llmv1.StreamEvaluationResponse, err error) {
+	if s.recorded {
+		return
+	}
+	s.recorded = true
+	s.stop()
+	if res == nil && err == nil {
+		err = status.Error(codes.Internal, "stream closed before done")
+	}
+	s.a.record(s.ctx, "StreamEvaluation", s.in, res, res.GetUsage(), s.start, err)
+}
+
+// record 异步写入审计记录,写入失败不影响调用方
+func (a *AuditClient) record(ctx context.Context, method string, in, out proto.Message, usage *llmv1.Usage, start time.Time, err error) {
+	audit := &model.LLMAudit{
+		UserID:           userIDFromContext(ctx),
+		Method:           method,
+		Backend:          a.backend,
+		InputSize:        proto.Size(in),
+		Latency:          time.Since(start).Milliseconds(),
+		Status:           auditCode(err).String(),
+		PromptTokens:     usage.GetPromptTokens(),
+		CompletionTokens: usage.GetCompletionTokens(),
+	}
+	if err != nil {
+		audit.Error = err.Error()
+	} else if b, err := protojson.Marshal(out); err == nil {
+		audit.Response = string(b)
+	}
+
+	go func() {
+		//不使用调用方的ctx,避免请求结束或者处于事务中影响写入
+		ctx, cancel := context.WithTimeout(context.Background(), auditTimeout)
+		defer cancel()
+		if err := a.dao.CreateLLMAudit(ctx, audit); err != nil {
+			log.Println("record llm audit failed:", err)
+		}
+	}()
+}
+
+// sumUsage 批量请求的用量是每个请求的用量之和
+func sumUsage(usages []*llmv1.Usage) *llmv1.Usage {
+	sum := &llmv1.Usage{}
+	for _, u := range usages {
+		sum.PromptTokens += u.GetPromptTokens()
+		sum.CompletionTokens += u.GetCompletionTokens()
+	}
+	return sum
+}
+
+// auditCode 熔断和重试之后的错误不再是grpc的status,需要单独处理
+func auditCode(err error) codes.Code {
+	switch {
+	case err == nil:
+		return codes.OK
+	case errors.Is(err, ErrLLMUnavailable):
+		return codes.Unavailable
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Code()
+	}
+	return status.Code(err)
+}
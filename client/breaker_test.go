@@ -0,0 +1,228 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GitEval/GitEval-Backend/conf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBreaker(t *testing.T) {
+	type step struct {
+		op   string // allow, success, failure 或者 cancel
+		want bool   // op 为 allow 时期望的结果
+	}
+	tests := []struct {
+		name      string
+		threshold int
+		cooldown  time.Duration
+		steps     []step
+	}{
+		{
+			name:      "closed below threshold",
+			threshold: 3,
+			cooldown:  time.Hour,
+			steps: []step{
+				{op: "failure"}, {op: "failure"}, {op: "allow", want: true},
+			},
+		},
+		{
+			name:      "opens at threshold",
+			threshold: 2,
+			cooldown:  time.Hour,
+			steps: []step{
+				{op: "failure"}, {op: "failure"}, {op: "allow", want: false},
+			},
+		},
+		{
+			name:      "success resets failures",
+			threshold: 2,
+			cooldown:  time.Hour,
+			steps: []step{
+				{op: "failure"}, {op: "success"}, {op: "failure"}, {op: "allow", want: true},
+			},
+		},
+		{
+			name:      "non-positive threshold is one",
+			threshold: 0,
+			cooldown:  time.Hour,
+			steps: []step{
+				{op: "allow", want: true}, {op: "failure"}, {op: "allow", want: false},
+			},
+		},
+		{
+			name:      "only one probe after cooldown",
+			threshold: 1,
+			cooldown:  0,
+			steps: []step{
+				{op: "failure"}, {op: "allow", want: true}, {op: "allow", want: false},
+			},
+		},
+		{
+			name:      "successful probe closes",
+			threshold: 1,
+			cooldown:  0,
+			steps: []step{
+				{op: "failure"}, {op: "allow", want: true}, {op: "success"},
+				{op: "allow", want: true}, {op: "allow", want: true},
+			},
+		},
+		{
+			name:      "failed probe opens again",
+			threshold: 1,
+			cooldown:  time.Hour,
+			steps: []step{
+				{op: "allow", want: true}, {op: "failure"}, {op: "allow", want: false},
+			},
+		},
+		{
+			name:      "cancelled probe lets another probe through",
+			threshold: 1,
+			cooldown:  0,
+			steps: []step{
+				{op: "failure"}, {op: "allow", want: true}, {op: "cancel"}, {op: "allow", want: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBreaker(tt.threshold, tt.cooldown)
+			for i, s := range tt.steps {
+				switch s.op {
+				case "allow":
+					if got := b.Allow(); got != s.want {
+						t.Fatalf("step %d: Allow() = %v, want %v", i, got, s.want)
+					}
+				case "success":
+					b.Success()
+				case "failure":
+					b.Failure()
+				case "cancel":
+					b.Cancel()
+				}
+			}
+		})
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	internal := status.Error(codes.Internal, "internal")
+	invalid := status.Error(codes.InvalidArgument, "invalid")
+
+	tests := []struct {
+		name        string
+		retries     int
+		errs        []error // 每次调用返回的错误,超出之后返回nil
+		wantCalls   int
+		wantErr     error
+		wantFailure bool // 是否计入熔断
+		minElapsed  time.Duration
+	}{
+		{
+			name:      "success",
+			retries:   2,
+			wantCalls: 1,
+		},
+		{
+			name:       "transient error is retried with backoff",
+			retries:    2,
+			errs:       []error{unavailable, unavailable},
+			wantCalls:  3,
+			minElapsed: retryBackoff + retryBackoff<<1,
+		},
+		{
+			name:        "gives up after retries",
+			retries:     1,
+			errs:        []error{unavailable, unavailable, unavailable},
+			wantCalls:   2,
+			wantErr:     ErrLLMUnavailable,
+			wantFailure: true,
+			minElapsed:  retryBackoff,
+		},
+		{
+			name:        "unhealthy but not transient is not retried",
+			retries:     2,
+			errs:        []error{internal},
+			wantCalls:   1,
+			wantErr:     ErrLLMUnavailable,
+			wantFailure: true,
+		},
+		{
+			name:      "caller error is neither retried nor counted",
+			retries:   2,
+			errs:      []error{invalid},
+			wantCalls: 1,
+			wantErr:   invalid,
+		},
+		{
+			name:        "negative retries calls once",
+			retries:     -1,
+			errs:        []error{unavailable},
+			wantCalls:   1,
+			wantErr:     ErrLLMUnavailable,
+			wantFailure: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &LLMClient{
+				cfg:     &conf.LLMConfig{Retries: tt.retries},
+				breaker: newBreaker(1, time.Hour),
+			}
+			calls := 0
+			start := time.Now()
+			_, err := invoke(context.Background(), c, 0, func(ctx context.Context) (int, error) {
+				calls++
+				if calls <= len(tt.errs) {
+					return 0, tt.errs[calls-1]
+				}
+				return calls, nil
+			})
+			elapsed := time.Since(start)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if elapsed < tt.minElapsed {
+				t.Errorf("elapsed = %v, want at least %v", elapsed, tt.minElapsed)
+			}
+			if open := !c.breaker.Allow(); open != tt.wantFailure {
+				t.Errorf("breaker open = %v, want %v", open, tt.wantFailure)
+			}
+		})
+	}
+}
+
+func TestInvokeCancelledDuringBackoff(t *testing.T) {
+	c := &LLMClient{
+		cfg:     &conf.LLMConfig{Retries: 3},
+		breaker: newBreaker(1, 0),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	_, err := invoke(ctx, c, 0, func(ctx context.Context) (int, error) {
+		calls++
+		time.AfterFunc(retryBackoff/10, cancel)
+		return 0, status.Error(codes.Unavailable, "unavailable")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want %v", err, context.Canceled)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+	// 取消不计入熔断
+	if !c.breaker.Allow() {
+		t.Error("breaker is open after cancellation")
+	}
+}
@@ -4,4 +4,5 @@ import "github.com/google/wire"
 
 var ProviderSet = wire.NewSet(
 	NewLLMClient,
+	NewAuditClient,
 )
@@ -96,8 +96,9 @@ type GetDomainRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Repos []*Repo `protobuf:"bytes,1,rep,name=repos,proto3" json:"repos,omitempty"` // 仓库列表
-	Bio   string  `protobuf:"bytes,2,opt,name=bio,proto3" json:"bio,omitempty"`     // 个人简介
+	Repos     []*Repo  `protobuf:"bytes,1,rep,name=repos,proto3" json:"repos,omitempty"`         // 仓库列表
+	Bio       string   `protobuf:"bytes,2,opt,name=bio,proto3" json:"bio,omitempty"`             // 个人简介
+	Interests []string `protobuf:"bytes,3,rep,name=interests,proto3" json:"interests,omitempty"` // star过的仓库聚合出的兴趣,只作为弱信号
 }
 
 func (x *GetDomainRequest) Reset() {
@@ -144,6 +145,13 @@ func (x *GetDomainRequest) GetBio() string {
 	return ""
 }
 
+func (x *GetDomainRequest) GetInterests() []string {
+	if x != nil {
+		return x.Interests
+	}
+	return nil
+}
+
 // 定义 Domain 消息
 type Domain struct {
 	state         protoimpl.MessageState
@@ -205,6 +213,7 @@ type GetDomainResponse struct {
 	unknownFields protoimpl.UnknownFields
 
 	Domains []*Domain `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"` // 响应消息内容
+	Usage   *Usage    `protobuf:"bytes,2,opt,name=usage,proto3" json:"usage,omitempty"`
 }
 
 func (x *GetDomainResponse) Reset() {
@@ -244,6 +253,13 @@ func (x *GetDomainResponse) GetDomains() []*Domain {
 	return nil
 }
 
+func (x *GetDomainResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
 // 定义 RepoInfo 消息
 type RepoInfo struct {
 	state         protoimpl.MessageState
@@ -494,29 +510,33 @@ func (x *GetEvaluationRequest) GetDomains() []string {
 	return nil
 }
 
-// 定义 EvaluationResponse 消息
-type GetEvaluationResponse struct {
+// 定义 DimensionScores 消息,各个维度的评分,范围0-100
+type DimensionScores struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Evaluation string `protobuf:"bytes,1,opt,name=evaluation,proto3" json:"evaluation,omitempty"`
+	CodeQuality   float32 `protobuf:"fixed32,1,opt,name=code_quality,json=codeQuality,proto3" json:"code_quality,omitempty"` // 代码质量
+	Activity      float32 `protobuf:"fixed32,2,opt,name=activity,proto3" json:"activity,omitempty"`                          // 活跃度
+	Collaboration float32 `protobuf:"fixed32,3,opt,name=collaboration,proto3" json:"collaboration,omitempty"`                // 协作
+	Influence     float32 `protobuf:"fixed32,4,opt,name=influence,proto3" json:"influence,omitempty"`                        // 影响力
+	Breadth       float32 `protobuf:"fixed32,5,opt,name=breadth,proto3" json:"breadth,omitempty"`                            // 技术广度
 }
 
-func (x *GetEvaluationResponse) Reset() {
-	*x = GetEvaluationResponse{}
+func (x *DimensionScores) Reset() {
+	*x = DimensionScores{}
 	mi := &file_llm_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetEvaluationResponse) String() string {
+func (x *DimensionScores) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetEvaluationResponse) ProtoMessage() {}
+func (*DimensionScores) ProtoMessage() {}
 
-func (x *GetEvaluationResponse) ProtoReflect() protoreflect.Message {
+func (x *DimensionScores) ProtoReflect() protoreflect.Message {
 	mi := &file_llm_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -528,45 +548,71 @@ func (x *GetEvaluationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetEvaluationResponse.ProtoReflect.Descriptor instead.
-func (*GetEvaluationResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DimensionScores.ProtoReflect.Descriptor instead.
+func (*DimensionScores) Descriptor() ([]byte, []int) {
 	return file_llm_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *GetEvaluationResponse) GetEvaluation() string {
+func (x *DimensionScores) GetCodeQuality() float32 {
 	if x != nil {
-		return x.Evaluation
+		return x.CodeQuality
 	}
-	return ""
+	return 0
 }
 
-// 定义 AreaRequest 消息
-type GetAreaRequest struct {
+func (x *DimensionScores) GetActivity() float32 {
+	if x != nil {
+		return x.Activity
+	}
+	return 0
+}
+
+func (x *DimensionScores) GetCollaboration() float32 {
+	if x != nil {
+		return x.Collaboration
+	}
+	return 0
+}
+
+func (x *DimensionScores) GetInfluence() float32 {
+	if x != nil {
+		return x.Influence
+	}
+	return 0
+}
+
+func (x *DimensionScores) GetBreadth() float32 {
+	if x != nil {
+		return x.Breadth
+	}
+	return 0
+}
+
+// 定义 Evidence 消息,评价所依据的仓库
+type Evidence struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Bio            string   `protobuf:"bytes,1,opt,name=bio,proto3" json:"bio,omitempty"` // 个人简介
-	Company        string   `protobuf:"bytes,2,opt,name=company,proto3" json:"company,omitempty"`
-	Location       string   `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
-	FollowerAreas  []string `protobuf:"bytes,4,rep,name=follower_areas,json=followerAreas,proto3" json:"follower_areas,omitempty"`    // 粉丝的地区
-	FollowingAreas []string `protobuf:"bytes,5,rep,name=following_areas,json=followingAreas,proto3" json:"following_areas,omitempty"` // 追随者的地区
+	Repo   string `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
+	Url    string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
 }
 
-func (x *GetAreaRequest) Reset() {
-	*x = GetAreaRequest{}
+func (x *Evidence) Reset() {
+	*x = Evidence{}
 	mi := &file_llm_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAreaRequest) String() string {
+func (x *Evidence) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAreaRequest) ProtoMessage() {}
+func (*Evidence) ProtoMessage() {}
 
-func (x *GetAreaRequest) ProtoReflect() protoreflect.Message {
+func (x *Evidence) ProtoReflect() protoreflect.Message {
 	mi := &file_llm_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -578,71 +624,220 @@ func (x *GetAreaRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAreaRequest.ProtoReflect.Descriptor instead.
-func (*GetAreaRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Evidence.ProtoReflect.Descriptor instead.
+func (*Evidence) Descriptor() ([]byte, []int) {
 	return file_llm_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *GetAreaRequest) GetBio() string {
+func (x *Evidence) GetRepo() string {
 	if x != nil {
-		return x.Bio
+		return x.Repo
 	}
 	return ""
 }
 
-func (x *GetAreaRequest) GetCompany() string {
+func (x *Evidence) GetUrl() string {
 	if x != nil {
-		return x.Company
+		return x.Url
 	}
 	return ""
 }
 
-func (x *GetAreaRequest) GetLocation() string {
+func (x *Evidence) GetReason() string {
 	if x != nil {
-		return x.Location
+		return x.Reason
 	}
 	return ""
 }
 
-func (x *GetAreaRequest) GetFollowerAreas() []string {
+// 定义 Usage 消息,本次调用消耗的token数,llm服务不支持统计时为空
+type Usage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PromptTokens     int32 `protobuf:"varint,1,opt,name=prompt_tokens,json=promptTokens,proto3" json:"prompt_tokens,omitempty"`
+	CompletionTokens int32 `protobuf:"varint,2,opt,name=completion_tokens,json=completionTokens,proto3" json:"completion_tokens,omitempty"`
+}
+
+func (x *Usage) Reset() {
+	*x = Usage{}
+	mi := &file_llm_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Usage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Usage) ProtoMessage() {}
+
+func (x *Usage) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[9]
 	if x != nil {
-		return x.FollowerAreas
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Usage.ProtoReflect.Descriptor instead.
+func (*Usage) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Usage) GetPromptTokens() int32 {
+	if x != nil {
+		return x.PromptTokens
+	}
+	return 0
+}
+
+func (x *Usage) GetCompletionTokens() int32 {
+	if x != nil {
+		return x.CompletionTokens
+	}
+	return 0
+}
+
+// 定义 EvaluationResponse 消息
+type GetEvaluationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Evaluation    string           `protobuf:"bytes,1,opt,name=evaluation,proto3" json:"evaluation,omitempty"`                            // 叙述性的评价
+	ModelVersion  string           `protobuf:"bytes,2,opt,name=model_version,json=modelVersion,proto3" json:"model_version,omitempty"`    // 生成评价使用的模型
+	PromptVersion string           `protobuf:"bytes,3,opt,name=prompt_version,json=promptVersion,proto3" json:"prompt_version,omitempty"` // 生成评价使用的prompt版本
+	Scores        *DimensionScores `protobuf:"bytes,4,opt,name=scores,proto3" json:"scores,omitempty"`
+	Strengths     []string         `protobuf:"bytes,5,rep,name=strengths,proto3" json:"strengths,omitempty"`   // 优势
+	Weaknesses    []string         `protobuf:"bytes,6,rep,name=weaknesses,proto3" json:"weaknesses,omitempty"` // 不足
+	Evidence      []*Evidence      `protobuf:"bytes,7,rep,name=evidence,proto3" json:"evidence,omitempty"`
+	Usage         *Usage           `protobuf:"bytes,8,opt,name=usage,proto3" json:"usage,omitempty"`
+}
+
+func (x *GetEvaluationResponse) Reset() {
+	*x = GetEvaluationResponse{}
+	mi := &file_llm_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEvaluationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEvaluationResponse) ProtoMessage() {}
+
+func (x *GetEvaluationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEvaluationResponse.ProtoReflect.Descriptor instead.
+func (*GetEvaluationResponse) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetEvaluationResponse) GetEvaluation() string {
+	if x != nil {
+		return x.Evaluation
+	}
+	return ""
+}
+
+func (x *GetEvaluationResponse) GetModelVersion() string {
+	if x != nil {
+		return x.ModelVersion
+	}
+	return ""
+}
+
+func (x *GetEvaluationResponse) GetPromptVersion() string {
+	if x != nil {
+		return x.PromptVersion
+	}
+	return ""
+}
+
+func (x *GetEvaluationResponse) GetScores() *DimensionScores {
+	if x != nil {
+		return x.Scores
 	}
 	return nil
 }
 
-func (x *GetAreaRequest) GetFollowingAreas() []string {
+func (x *GetEvaluationResponse) GetStrengths() []string {
 	if x != nil {
-		return x.FollowingAreas
+		return x.Strengths
 	}
 	return nil
 }
 
-// 定义 AreaResponse 消息
-type GetAreaResponse struct {
+func (x *GetEvaluationResponse) GetWeaknesses() []string {
+	if x != nil {
+		return x.Weaknesses
+	}
+	return nil
+}
+
+func (x *GetEvaluationResponse) GetEvidence() []*Evidence {
+	if x != nil {
+		return x.Evidence
+	}
+	return nil
+}
+
+func (x *GetEvaluationResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+// 定义 StreamEvaluationResponse 消息,流式返回评价的片段
+type StreamEvaluationResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Area       string  `protobuf:"bytes,1,opt,name=area,proto3" json:"area,omitempty"`
-	Confidence float32 `protobuf:"fixed32,2,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Delta         string           `protobuf:"bytes,1,opt,name=delta,proto3" json:"delta,omitempty"`                                      // 新生成的片段
+	Done          bool             `protobuf:"varint,2,opt,name=done,proto3" json:"done,omitempty"`                                       // 是否生成完毕
+	ModelVersion  string           `protobuf:"bytes,3,opt,name=model_version,json=modelVersion,proto3" json:"model_version,omitempty"`    // 生成完毕时返回
+	PromptVersion string           `protobuf:"bytes,4,opt,name=prompt_version,json=promptVersion,proto3" json:"prompt_version,omitempty"` // 生成完毕时返回
+	Scores        *DimensionScores `protobuf:"bytes,5,opt,name=scores,proto3" json:"scores,omitempty"`                                    // 生成完毕时返回
+	Strengths     []string         `protobuf:"bytes,6,rep,name=strengths,proto3" json:"strengths,omitempty"`                              // 生成完毕时返回
+	Weaknesses    []string         `protobuf:"bytes,7,rep,name=weaknesses,proto3" json:"weaknesses,omitempty"`                            // 生成完毕时返回
+	Evidence      []*Evidence      `protobuf:"bytes,8,rep,name=evidence,proto3" json:"evidence,omitempty"`                                // 生成完毕时返回
+	Usage         *Usage           `protobuf:"bytes,9,opt,name=usage,proto3" json:"usage,omitempty"`                                      // 生成完毕时返回
 }
 
-func (x *GetAreaResponse) Reset() {
-	*x = GetAreaResponse{}
-	mi := &file_llm_proto_msgTypes[9]
+func (x *StreamEvaluationResponse) Reset() {
+	*x = StreamEvaluationResponse{}
+	mi := &file_llm_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAreaResponse) String() string {
+func (x *StreamEvaluationResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAreaResponse) ProtoMessage() {}
+func (*StreamEvaluationResponse) ProtoMessage() {}
 
-func (x *GetAreaResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_llm_proto_msgTypes[9]
+func (x *StreamEvaluationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -653,23 +848,916 @@ func (x *GetAreaResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAreaResponse.ProtoReflect.Descriptor instead.
-func (*GetAreaResponse) Descriptor() ([]byte, []int) {
-	return file_llm_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use StreamEvaluationResponse.ProtoReflect.Descriptor instead.
+func (*StreamEvaluationResponse) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *GetAreaResponse) GetArea() string {
+func (x *StreamEvaluationResponse) GetDelta() string {
 	if x != nil {
-		return x.Area
+		return x.Delta
 	}
 	return ""
 }
 
-func (x *GetAreaResponse) GetConfidence() float32 {
+func (x *StreamEvaluationResponse) GetDone() bool {
 	if x != nil {
-		return x.Confidence
+		return x.Done
 	}
-	return 0
+	return false
+}
+
+func (x *StreamEvaluationResponse) GetModelVersion() string {
+	if x != nil {
+		return x.ModelVersion
+	}
+	return ""
+}
+
+func (x *StreamEvaluationResponse) GetPromptVersion() string {
+	if x != nil {
+		return x.PromptVersion
+	}
+	return ""
+}
+
+func (x *StreamEvaluationResponse) GetScores() *DimensionScores {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+func (x *StreamEvaluationResponse) GetStrengths() []string {
+	if x != nil {
+		return x.Strengths
+	}
+	return nil
+}
+
+func (x *StreamEvaluationResponse) GetWeaknesses() []string {
+	if x != nil {
+		return x.Weaknesses
+	}
+	return nil
+}
+
+func (x *StreamEvaluationResponse) GetEvidence() []*Evidence {
+	if x != nil {
+		return x.Evidence
+	}
+	return nil
+}
+
+func (x *StreamEvaluationResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+// 定义 AreaRequest 消息
+type GetAreaRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bio            string   `protobuf:"bytes,1,opt,name=bio,proto3" json:"bio,omitempty"` // 个人简介
+	Company        string   `protobuf:"bytes,2,opt,name=company,proto3" json:"company,omitempty"`
+	Location       string   `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	FollowerAreas  []string `protobuf:"bytes,4,rep,name=follower_areas,json=followerAreas,proto3" json:"follower_areas,omitempty"`    // 粉丝的地区
+	FollowingAreas []string `protobuf:"bytes,5,rep,name=following_areas,json=followingAreas,proto3" json:"following_areas,omitempty"` // 追随者的地区
+}
+
+func (x *GetAreaRequest) Reset() {
+	*x = GetAreaRequest{}
+	mi := &file_llm_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAreaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAreaRequest) ProtoMessage() {}
+
+func (x *GetAreaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAreaRequest.ProtoReflect.Descriptor instead.
+func (*GetAreaRequest) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetAreaRequest) GetBio() string {
+	if x != nil {
+		return x.Bio
+	}
+	return ""
+}
+
+func (x *GetAreaRequest) GetCompany() string {
+	if x != nil {
+		return x.Company
+	}
+	return ""
+}
+
+func (x *GetAreaRequest) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *GetAreaRequest) GetFollowerAreas() []string {
+	if x != nil {
+		return x.FollowerAreas
+	}
+	return nil
+}
+
+func (x *GetAreaRequest) GetFollowingAreas() []string {
+	if x != nil {
+		return x.FollowingAreas
+	}
+	return nil
+}
+
+// 定义 AreaResponse 消息
+type GetAreaResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Area       string  `protobuf:"bytes,1,opt,name=area,proto3" json:"area,omitempty"`
+	Confidence float32 `protobuf:"fixed32,2,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Usage      *Usage  `protobuf:"bytes,3,opt,name=usage,proto3" json:"usage,omitempty"`
+}
+
+func (x *GetAreaResponse) Reset() {
+	*x = GetAreaResponse{}
+	mi := &file_llm_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAreaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAreaResponse) ProtoMessage() {}
+
+func (x *GetAreaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAreaResponse.ProtoReflect.Descriptor instead.
+func (*GetAreaResponse) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetAreaResponse) GetArea() string {
+	if x != nil {
+		return x.Area
+	}
+	return ""
+}
+
+func (x *GetAreaResponse) GetConfidence() float32 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *GetAreaResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+// 定义 BatchGetDomainRequest 消息,批量推断多个用户的领域
+type BatchGetDomainRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Requests []*GetDomainRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+}
+
+func (x *BatchGetDomainRequest) Reset() {
+	*x = BatchGetDomainRequest{}
+	mi := &file_llm_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetDomainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetDomainRequest) ProtoMessage() {}
+
+func (x *BatchGetDomainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetDomainRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetDomainRequest) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchGetDomainRequest) GetRequests() []*GetDomainRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+// 定义 BatchGetDomainResponse 消息,和请求一一对应
+type BatchGetDomainResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Responses []*GetDomainResponse `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"`
+	Errors    []string             `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"` // 单个请求失败时的错误,成功时为空
+}
+
+func (x *BatchGetDomainResponse) Reset() {
+	*x = BatchGetDomainResponse{}
+	mi := &file_llm_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetDomainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetDomainResponse) ProtoMessage() {}
+
+func (x *BatchGetDomainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetDomainResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetDomainResponse) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BatchGetDomainResponse) GetResponses() []*GetDomainResponse {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+func (x *BatchGetDomainResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+// 定义 BatchGetAreaRequest 消息,批量推断多个用户的地区
+type BatchGetAreaRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Requests []*GetAreaRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+}
+
+func (x *BatchGetAreaRequest) Reset() {
+	*x = BatchGetAreaRequest{}
+	mi := &file_llm_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetAreaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetAreaRequest) ProtoMessage() {}
+
+func (x *BatchGetAreaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetAreaRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetAreaRequest) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BatchGetAreaRequest) GetRequests() []*GetAreaRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+// 定义 BatchGetAreaResponse 消息,和请求一一对应
+type BatchGetAreaResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Responses []*GetAreaResponse `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"`
+	Errors    []string           `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"` // 单个请求失败时的错误,成功时为空
+}
+
+func (x *BatchGetAreaResponse) Reset() {
+	*x = BatchGetAreaResponse{}
+	mi := &file_llm_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetAreaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetAreaResponse) ProtoMessage() {}
+
+func (x *BatchGetAreaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetAreaResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetAreaResponse) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BatchGetAreaResponse) GetResponses() []*GetAreaResponse {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+func (x *BatchGetAreaResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+// 定义 ExtractRequirementsRequest 消息,从职位描述中提取要求
+type ExtractRequirementsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobDescription string   `protobuf:"bytes,1,opt,name=job_description,json=jobDescription,proto3" json:"job_description,omitempty"`
+	KnownDomains   []string `protobuf:"bytes,2,rep,name=known_domains,json=knownDomains,proto3" json:"known_domains,omitempty"` // 系统中已有的领域,提取的领域尽量从中选择
+}
+
+func (x *ExtractRequirementsRequest) Reset() {
+	*x = ExtractRequirementsRequest{}
+	mi := &file_llm_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtractRequirementsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractRequirementsRequest) ProtoMessage() {}
+
+func (x *ExtractRequirementsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractRequirementsRequest.ProtoReflect.Descriptor instead.
+func (*ExtractRequirementsRequest) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ExtractRequirementsRequest) GetJobDescription() string {
+	if x != nil {
+		return x.JobDescription
+	}
+	return ""
+}
+
+func (x *ExtractRequirementsRequest) GetKnownDomains() []string {
+	if x != nil {
+		return x.KnownDomains
+	}
+	return nil
+}
+
+// 定义 ExtractRequirementsResponse 消息
+type ExtractRequirementsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Domains   []string `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`     // 技术领域
+	Languages []string `protobuf:"bytes,2,rep,name=languages,proto3" json:"languages,omitempty"` // 编程语言
+	Skills    []string `protobuf:"bytes,3,rep,name=skills,proto3" json:"skills,omitempty"`       // 框架,工具等技术栈
+	Usage     *Usage   `protobuf:"bytes,4,opt,name=usage,proto3" json:"usage,omitempty"`
+}
+
+func (x *ExtractRequirementsResponse) Reset() {
+	*x = ExtractRequirementsResponse{}
+	mi := &file_llm_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtractRequirementsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractRequirementsResponse) ProtoMessage() {}
+
+func (x *ExtractRequirementsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractRequirementsResponse.ProtoReflect.Descriptor instead.
+func (*ExtractRequirementsResponse) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ExtractRequirementsResponse) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *ExtractRequirementsResponse) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *ExtractRequirementsResponse) GetSkills() []string {
+	if x != nil {
+		return x.Skills
+	}
+	return nil
+}
+
+func (x *ExtractRequirementsResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+// 定义 EmbedRequest 消息,批量计算文本的向量
+type EmbedRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Texts []string `protobuf:"bytes,1,rep,name=texts,proto3" json:"texts,omitempty"`
+}
+
+func (x *EmbedRequest) Reset() {
+	*x = EmbedRequest{}
+	mi := &file_llm_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmbedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmbedRequest) ProtoMessage() {}
+
+func (x *EmbedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmbedRequest.ProtoReflect.Descriptor instead.
+func (*EmbedRequest) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *EmbedRequest) GetTexts() []string {
+	if x != nil {
+		return x.Texts
+	}
+	return nil
+}
+
+// 定义 Embedding 消息
+type Embedding struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values []float32 `protobuf:"fixed32,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *Embedding) Reset() {
+	*x = Embedding{}
+	mi := &file_llm_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Embedding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Embedding) ProtoMessage() {}
+
+func (x *Embedding) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Embedding.ProtoReflect.Descriptor instead.
+func (*Embedding) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Embedding) GetValues() []float32 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// 定义 EmbedResponse 消息,和请求的文本一一对应
+type EmbedResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Embeddings []*Embedding `protobuf:"bytes,1,rep,name=embeddings,proto3" json:"embeddings,omitempty"`
+	Model      string       `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"` // 计算向量使用的模型,不同模型的向量不能比较
+	Usage      *Usage       `protobuf:"bytes,3,opt,name=usage,proto3" json:"usage,omitempty"`
+}
+
+func (x *EmbedResponse) Reset() {
+	*x = EmbedResponse{}
+	mi := &file_llm_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmbedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmbedResponse) ProtoMessage() {}
+
+func (x *EmbedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmbedResponse.ProtoReflect.Descriptor instead.
+func (*EmbedResponse) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *EmbedResponse) GetEmbeddings() []*Embedding {
+	if x != nil {
+		return x.Embeddings
+	}
+	return nil
+}
+
+func (x *EmbedResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *EmbedResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+// 定义 CompareProfile 消息,对比中一个开发者的数据
+type CompareProfile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Login        string           `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
+	Bio          string           `protobuf:"bytes,2,opt,name=bio,proto3" json:"bio,omitempty"`
+	Score        float64          `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"` // 仓库的评分
+	Domains      []*Domain        `protobuf:"bytes,4,rep,name=domains,proto3" json:"domains,omitempty"`
+	Languages    []string         `protobuf:"bytes,5,rep,name=languages,proto3" json:"languages,omitempty"`
+	Scores       *DimensionScores `protobuf:"bytes,6,opt,name=scores,proto3" json:"scores,omitempty"` // 最新评价的各维度评分,没有评价时为空
+	Followers    int32            `protobuf:"varint,7,opt,name=followers,proto3" json:"followers,omitempty"`
+	PublicRepos  int32            `protobuf:"varint,8,opt,name=public_repos,json=publicRepos,proto3" json:"public_repos,omitempty"`
+	Commits      int32            `protobuf:"varint,9,opt,name=commits,proto3" json:"commits,omitempty"`                                // 近期的提交数
+	PullRequests int32            `protobuf:"varint,10,opt,name=pull_requests,json=pullRequests,proto3" json:"pull_requests,omitempty"` // 近期的pr数
+	Issues       int32            `protobuf:"varint,11,opt,name=issues,proto3" json:"issues,omitempty"`                                 // 近期的issue数
+}
+
+func (x *CompareProfile) Reset() {
+	*x = CompareProfile{}
+	mi := &file_llm_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareProfile) ProtoMessage() {}
+
+func (x *CompareProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareProfile.ProtoReflect.Descriptor instead.
+func (*CompareProfile) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CompareProfile) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *CompareProfile) GetBio() string {
+	if x != nil {
+		return x.Bio
+	}
+	return ""
+}
+
+func (x *CompareProfile) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *CompareProfile) GetDomains() []*Domain {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *CompareProfile) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *CompareProfile) GetScores() *DimensionScores {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+func (x *CompareProfile) GetFollowers() int32 {
+	if x != nil {
+		return x.Followers
+	}
+	return 0
+}
+
+func (x *CompareProfile) GetPublicRepos() int32 {
+	if x != nil {
+		return x.PublicRepos
+	}
+	return 0
+}
+
+func (x *CompareProfile) GetCommits() int32 {
+	if x != nil {
+		return x.Commits
+	}
+	return 0
+}
+
+func (x *CompareProfile) GetPullRequests() int32 {
+	if x != nil {
+		return x.PullRequests
+	}
+	return 0
+}
+
+func (x *CompareProfile) GetIssues() int32 {
+	if x != nil {
+		return x.Issues
+	}
+	return 0
+}
+
+// 定义 CompareUsersRequest 消息,对比多个开发者
+type CompareUsersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Users []*CompareProfile `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+}
+
+func (x *CompareUsersRequest) Reset() {
+	*x = CompareUsersRequest{}
+	mi := &file_llm_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareUsersRequest) ProtoMessage() {}
+
+func (x *CompareUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareUsersRequest.ProtoReflect.Descriptor instead.
+func (*CompareUsersRequest) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CompareUsersRequest) GetUsers() []*CompareProfile {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+// 定义 CompareUsersResponse 消息
+type CompareUsersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Summary string `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"` // 对比的总结
+	Usage   *Usage `protobuf:"bytes,2,opt,name=usage,proto3" json:"usage,omitempty"`
+}
+
+func (x *CompareUsersResponse) Reset() {
+	*x = CompareUsersResponse{}
+	mi := &file_llm_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareUsersResponse) ProtoMessage() {}
+
+func (x *CompareUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareUsersResponse.ProtoReflect.Descriptor instead.
+func (*CompareUsersResponse) Descriptor() ([]byte, []int) {
+	return file_llm_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CompareUsersResponse) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *CompareUsersResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
 }
 
 var File_llm_proto protoreflect.FileDescriptor
@@ -682,19 +1770,23 @@ var file_llm_proto_rawDesc = []byte{
 	0x61, 0x64, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65,
 	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65,
 	0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x22, 0x45, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x44,
+	0x52, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x22, 0x63, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x44,
 	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x05,
 	0x72, 0x65, 0x70, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x6c, 0x6c,
 	0x6d, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x52, 0x05, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x12, 0x10, 0x0a,
-	0x03, 0x62, 0x69, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x62, 0x69, 0x6f, 0x22,
-	0x40, 0x0a, 0x06, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x6d,
-	0x61, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69,
-	0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63,
-	0x65, 0x22, 0x3a, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x44, 0x6f,
-	0x6d, 0x61, 0x69, 0x6e, 0x52, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x22, 0xd8, 0x01,
+	0x03, 0x62, 0x69, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x62, 0x69, 0x6f, 0x12,
+	0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x73, 0x22, 0x40, 0x0a,
+	0x06, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12,
+	0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x02, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x22,
+	0x5c, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x44, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x52, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x75,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x6c, 0x6c, 0x6d,
+	0x2e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x22, 0xd8, 0x01,
 	0x0a, 0x08, 0x52, 0x65, 0x70, 0x6f, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
 	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20,
 	0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
@@ -735,40 +1827,209 @@ var file_llm_proto_rawDesc = []byte{
 	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x55, 0x73,
 	0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x0a, 0x75, 0x73, 0x65, 0x72, 0x45, 0x76, 0x65,
 	0x6e, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x07,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x22, 0x37, 0x0a,
-	0x15, 0x47, 0x65, 0x74, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x76, 0x61, 0x6c, 0x75, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x76, 0x61, 0x6c,
-	0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xa8, 0x01, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x41, 0x72,
-	0x65, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x69, 0x6f,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x62, 0x69, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x63,
-	0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f,
-	0x6d, 0x70, 0x61, 0x6e, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x61, 0x72,
-	0x65, 0x61, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x66, 0x6f, 0x6c, 0x6c, 0x6f,
-	0x77, 0x65, 0x72, 0x41, 0x72, 0x65, 0x61, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x66, 0x6f, 0x6c, 0x6c,
-	0x6f, 0x77, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x72, 0x65, 0x61, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x0e, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x69, 0x6e, 0x67, 0x41, 0x72, 0x65, 0x61,
-	0x73, 0x22, 0x45, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x65, 0x61, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x65, 0x61, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x66,
-	0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0a, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x32, 0xc6, 0x01, 0x0a, 0x0a, 0x4c, 0x4c, 0x4d,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x46, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x45, 0x76,
-	0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x47,
-	0x65, 0x74, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x76, 0x61,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x22, 0xae, 0x01,
+	0x0a, 0x0f, 0x44, 0x69, 0x6d, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x73, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x64, 0x65, 0x5f, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0b, 0x63, 0x6f, 0x64, 0x65, 0x51, 0x75, 0x61,
+	0x6c, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x08, 0x61, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79,
+	0x12, 0x24, 0x0a, 0x0d, 0x63, 0x6f, 0x6c, 0x6c, 0x61, 0x62, 0x6f, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0d, 0x63, 0x6f, 0x6c, 0x6c, 0x61, 0x62, 0x6f,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x69, 0x6e, 0x66, 0x6c, 0x75,
+	0x65, 0x6e, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x72, 0x65, 0x61, 0x64, 0x74, 0x68, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x02, 0x52, 0x07, 0x62, 0x72, 0x65, 0x61, 0x64, 0x74, 0x68, 0x22, 0x48,
+	0x0a, 0x08, 0x45, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x65,
+	0x70, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x65, 0x70, 0x6f, 0x12, 0x10,
+	0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c,
+	0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x59, 0x0a, 0x05, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x72, 0x6f, 0x6d, 0x70, 0x74, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x6d, 0x70, 0x74,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x10, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x73, 0x22, 0xbc, 0x02, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x45, 0x76, 0x61, 0x6c, 0x75,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a,
+	0x0a, 0x65, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x65, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a,
+	0x0d, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x72, 0x6f, 0x6d, 0x70, 0x74, 0x5f, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x6d,
+	0x70, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x06, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e,
+	0x44, 0x69, 0x6d, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x52,
+	0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x74, 0x72, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x72, 0x65,
+	0x6e, 0x67, 0x74, 0x68, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x77, 0x65, 0x61, 0x6b, 0x6e, 0x65, 0x73,
+	0x73, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x65, 0x61, 0x6b, 0x6e,
+	0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x29, 0x0a, 0x08, 0x65, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x63,
+	0x65, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x45, 0x76,
+	0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x08, 0x65, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65,
+	0x12, 0x20, 0x0a, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0a, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x75, 0x73, 0x61,
+	0x67, 0x65, 0x22, 0xc9, 0x02, 0x0a, 0x18, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45, 0x76, 0x61,
 	0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x34, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x12, 0x13, 0x2e, 0x6c, 0x6c, 0x6d,
-	0x2e, 0x47, 0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x14, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61,
-	0x69, 0x6e, 0x12, 0x15, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61,
-	0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e,
-	0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x42, 0x0c, 0x5a, 0x0a, 0x2e, 0x2e, 0x2f, 0x67, 0x65, 0x6e, 0x3b, 0x6c, 0x6c, 0x6d, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x14, 0x0a, 0x05, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x64, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x6f, 0x64,
+	0x65, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x25,
+	0x0a, 0x0e, 0x70, 0x72, 0x6f, 0x6d, 0x70, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x6d, 0x70, 0x74, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x44, 0x69, 0x6d, 0x65,
+	0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x52, 0x06, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x73,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68,
+	0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x77, 0x65, 0x61, 0x6b, 0x6e, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18,
+	0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x65, 0x61, 0x6b, 0x6e, 0x65, 0x73, 0x73, 0x65,
+	0x73, 0x12, 0x29, 0x0a, 0x08, 0x65, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x08, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x45, 0x76, 0x69, 0x64, 0x65, 0x6e,
+	0x63, 0x65, 0x52, 0x08, 0x65, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x20, 0x0a, 0x05,
+	0x75, 0x73, 0x61, 0x67, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x6c, 0x6c,
+	0x6d, 0x2e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x22, 0xa8,
+	0x01, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x69, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x62, 0x69, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x79, 0x12, 0x1a, 0x0a,
+	0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x66, 0x6f, 0x6c,
+	0x6c, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x61, 0x72, 0x65, 0x61, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0d, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x41, 0x72, 0x65, 0x61, 0x73,
+	0x12, 0x27, 0x0a, 0x0f, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x72,
+	0x65, 0x61, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x66, 0x6f, 0x6c, 0x6c, 0x6f,
+	0x77, 0x69, 0x6e, 0x67, 0x41, 0x72, 0x65, 0x61, 0x73, 0x22, 0x67, 0x0a, 0x0f, 0x47, 0x65, 0x74,
+	0x41, 0x72, 0x65, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x61, 0x72, 0x65, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x65, 0x61,
+	0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x02, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65,
+	0x12, 0x20, 0x0a, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0a, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x75, 0x73, 0x61,
+	0x67, 0x65, 0x22, 0x4a, 0x0a, 0x15, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x44, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x08, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x6c, 0x6c, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0x66,
+	0x0a, 0x16, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6c, 0x6c,
+	0x6d, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0x46, 0x0a, 0x13, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47,
+	0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a,
+	0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0x62,
+	0x0a, 0x14, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e,
+	0x47, 0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52,
+	0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x73, 0x22, 0x6a, 0x0a, 0x1a, 0x45, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x69, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x27, 0x0a, 0x0f, 0x6a, 0x6f, 0x62, 0x5f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x6a, 0x6f, 0x62, 0x44, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x6b, 0x6e, 0x6f,
+	0x77, 0x6e, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0c, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x22, 0x8f,
+	0x01, 0x0a, 0x1b, 0x45, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x61, 0x6e, 0x67,
+	0x75, 0x61, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x61, 0x6e,
+	0x67, 0x75, 0x61, 0x67, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6b, 0x69, 0x6c, 0x6c, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6b, 0x69, 0x6c, 0x6c, 0x73, 0x12, 0x20,
+	0x0a, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
+	0x6c, 0x6c, 0x6d, 0x2e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65,
+	0x22, 0x24, 0x0a, 0x0c, 0x45, 0x6d, 0x62, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x65, 0x78, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x05, 0x74, 0x65, 0x78, 0x74, 0x73, 0x22, 0x23, 0x0a, 0x09, 0x45, 0x6d, 0x62, 0x65, 0x64, 0x64,
+	0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x02, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x77, 0x0a, 0x0d, 0x45,
+	0x6d, 0x62, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x0a,
+	0x65, 0x6d, 0x62, 0x65, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x45, 0x6d, 0x62, 0x65, 0x64, 0x64, 0x69, 0x6e, 0x67,
+	0x52, 0x0a, 0x65, 0x6d, 0x62, 0x65, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64,
+	0x65, 0x6c, 0x12, 0x20, 0x0a, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0a, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x75,
+	0x73, 0x61, 0x67, 0x65, 0x22, 0xd9, 0x02, 0x0a, 0x0e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x65,
+	0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x10, 0x0a,
+	0x03, 0x62, 0x69, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x62, 0x69, 0x6f, 0x12,
+	0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05,
+	0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x25, 0x0a, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x44, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x52, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x12, 0x1c, 0x0a, 0x09,
+	0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x09, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x06, 0x73, 0x63,
+	0x6f, 0x72, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6c, 0x6c, 0x6d,
+	0x2e, 0x44, 0x69, 0x6d, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x73,
+	0x52, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x6f, 0x6c, 0x6c,
+	0x6f, 0x77, 0x65, 0x72, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x66, 0x6f, 0x6c,
+	0x6c, 0x6f, 0x77, 0x65, 0x72, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x70, 0x75,
+	0x62, 0x6c, 0x69, 0x63, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d,
+	0x69, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x75, 0x6c, 0x6c, 0x5f, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x70, 0x75, 0x6c, 0x6c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x73, 0x73, 0x75,
+	0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x69, 0x73, 0x73, 0x75, 0x65, 0x73,
+	0x22, 0x40, 0x0a, 0x13, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x65, 0x55, 0x73, 0x65, 0x72, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x43, 0x6f, 0x6d,
+	0x70, 0x61, 0x72, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x05, 0x75, 0x73, 0x65,
+	0x72, 0x73, 0x22, 0x52, 0x0a, 0x14, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x65, 0x55, 0x73, 0x65,
+	0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75,
+	0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x75, 0x6d,
+	0x6d, 0x61, 0x72, 0x79, 0x12, 0x20, 0x0a, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52,
+	0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x32, 0xf5, 0x04, 0x0a, 0x0a, 0x4c, 0x4c, 0x4d, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x46, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x45, 0x76, 0x61, 0x6c,
+	0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x47, 0x65, 0x74,
+	0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1a, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x76, 0x61, 0x6c, 0x75,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a,
+	0x07, 0x47, 0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x12, 0x13, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x47,
+	0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e,
+	0x6c, 0x6c, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x12, 0x15, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x47, 0x65,
+	0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4e, 0x0a, 0x10, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x19, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x76, 0x61,
+	0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d,
+	0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45, 0x76, 0x61, 0x6c, 0x75,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12,
+	0x49, 0x0a, 0x0e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x12, 0x1a, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74,
+	0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e,
+	0x6c, 0x6c, 0x6d, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0c, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x12, 0x18, 0x2e, 0x6c, 0x6c, 0x6d,
+	0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x47, 0x65, 0x74, 0x41, 0x72, 0x65, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x58, 0x0a, 0x13, 0x45, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1f, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x45, 0x78, 0x74,
+	0x72, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x45, 0x78,
+	0x74, 0x72, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x45, 0x6d, 0x62,
+	0x65, 0x64, 0x12, 0x11, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x45, 0x6d, 0x62, 0x65, 0x64, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x45, 0x6d, 0x62, 0x65,
+	0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0c, 0x43, 0x6f, 0x6d,
+	0x70, 0x61, 0x72, 0x65, 0x55, 0x73, 0x65, 0x72, 0x73, 0x12, 0x18, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e,
+	0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x65, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6c, 0x6c, 0x6d, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72,
+	0x65, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x0c,
+	0x5a, 0x0a, 0x2e, 0x2e, 0x2f, 0x67, 0x65, 0x6e, 0x3b, 0x6c, 0x6c, 0x6d, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -783,35 +2044,82 @@ func file_llm_proto_rawDescGZIP() []byte {
 	return file_llm_proto_rawDescData
 }
 
-var file_llm_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_llm_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_llm_proto_goTypes = []any{
-	(*Repo)(nil),                  // 0: llm.Repo
-	(*GetDomainRequest)(nil),      // 1: llm.GetDomainRequest
-	(*Domain)(nil),                // 2: llm.Domain
-	(*GetDomainResponse)(nil),     // 3: llm.GetDomainResponse
-	(*RepoInfo)(nil),              // 4: llm.RepoInfo
-	(*UserEvent)(nil),             // 5: llm.UserEvent
-	(*GetEvaluationRequest)(nil),  // 6: llm.GetEvaluationRequest
-	(*GetEvaluationResponse)(nil), // 7: llm.GetEvaluationResponse
-	(*GetAreaRequest)(nil),        // 8: llm.GetAreaRequest
-	(*GetAreaResponse)(nil),       // 9: llm.GetAreaResponse
+	(*Repo)(nil),                        // 0: llm.Repo
+	(*GetDomainRequest)(nil),            // 1: llm.GetDomainRequest
+	(*Domain)(nil),                      // 2: llm.Domain
+	(*GetDomainResponse)(nil),           // 3: llm.GetDomainResponse
+	(*RepoInfo)(nil),                    // 4: llm.RepoInfo
+	(*UserEvent)(nil),                   // 5: llm.UserEvent
+	(*GetEvaluationRequest)(nil),        // 6: llm.GetEvaluationRequest
+	(*DimensionScores)(nil),             // 7: llm.DimensionScores
+	(*Evidence)(nil),                    // 8: llm.Evidence
+	(*Usage)(nil),                       // 9: llm.Usage
+	(*GetEvaluationResponse)(nil),       // 10: llm.GetEvaluationResponse
+	(*StreamEvaluationResponse)(nil),    // 11: llm.StreamEvaluationResponse
+	(*GetAreaRequest)(nil),              // 12: llm.GetAreaRequest
+	(*GetAreaResponse)(nil),             // 13: llm.GetAreaResponse
+	(*BatchGetDomainRequest)(nil),       // 14: llm.BatchGetDomainRequest
+	(*BatchGetDomainResponse)(nil),      // 15: llm.BatchGetDomainResponse
+	(*BatchGetAreaRequest)(nil),         // 16: llm.BatchGetAreaRequest
+	(*BatchGetAreaResponse)(nil),        // 17: llm.BatchGetAreaResponse
+	(*ExtractRequirementsRequest)(nil),  // 18: llm.ExtractRequirementsRequest
+	(*ExtractRequirementsResponse)(nil), // 19: llm.ExtractRequirementsResponse
+	(*EmbedRequest)(nil),                // 20: llm.EmbedRequest
+	(*Embedding)(nil),                   // 21: llm.Embedding
+	(*EmbedResponse)(nil),               // 22: llm.EmbedResponse
+	(*CompareProfile)(nil),              // 23: llm.CompareProfile
+	(*CompareUsersRequest)(nil),         // 24: llm.CompareUsersRequest
+	(*CompareUsersResponse)(nil),        // 25: llm.CompareUsersResponse
 }
 var file_llm_proto_depIdxs = []int32{
-	0, // 0: llm.GetDomainRequest.repos:type_name -> llm.Repo
-	2, // 1: llm.GetDomainResponse.domains:type_name -> llm.Domain
-	4, // 2: llm.UserEvent.repo:type_name -> llm.RepoInfo
-	5, // 3: llm.GetEvaluationRequest.user_events:type_name -> llm.UserEvent
-	6, // 4: llm.LLMService.GetEvaluation:input_type -> llm.GetEvaluationRequest
-	8, // 5: llm.LLMService.GetArea:input_type -> llm.GetAreaRequest
-	1, // 6: llm.LLMService.GetDomain:input_type -> llm.GetDomainRequest
-	7, // 7: llm.LLMService.GetEvaluation:output_type -> llm.GetEvaluationResponse
-	9, // 8: llm.LLMService.GetArea:output_type -> llm.GetAreaResponse
-	3, // 9: llm.LLMService.GetDomain:output_type -> llm.GetDomainResponse
-	7, // [7:10] is the sub-list for method output_type
-	4, // [4:7] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	0,  // 0: llm.GetDomainRequest.repos:type_name -> llm.Repo
+	2,  // 1: llm.GetDomainResponse.domains:type_name -> llm.Domain
+	9,  // 2: llm.GetDomainResponse.usage:type_name -> llm.Usage
+	4,  // 3: llm.UserEvent.repo:type_name -> llm.RepoInfo
+	5,  // 4: llm.GetEvaluationRequest.user_events:type_name -> llm.UserEvent
+	7,  // 5: llm.GetEvaluationResponse.scores:type_name -> llm.DimensionScores
+	8,  // 6: llm.GetEvaluationResponse.evidence:type_name -> llm.Evidence
+	9,  // 7: llm.GetEvaluationResponse.usage:type_name -> llm.Usage
+	7,  // 8: llm.StreamEvaluationResponse.scores:type_name -> llm.DimensionScores
+	8,  // 9: llm.StreamEvaluationResponse.evidence:type_name -> llm.Evidence
+	9,  // 10: llm.StreamEvaluationResponse.usage:type_name -> llm.Usage
+	9,  // 11: llm.GetAreaResponse.usage:type_name -> llm.Usage
+	1,  // 12: llm.BatchGetDomainRequest.requests:type_name -> llm.GetDomainRequest
+	3,  // 13: llm.BatchGetDomainResponse.responses:type_name -> llm.GetDomainResponse
+	12, // 14: llm.BatchGetAreaRequest.requests:type_name -> llm.GetAreaRequest
+	13, // 15: llm.BatchGetAreaResponse.responses:type_name -> llm.GetAreaResponse
+	9,  // 16: llm.ExtractRequirementsResponse.usage:type_name -> llm.Usage
+	21, // 17: llm.EmbedResponse.embeddings:type_name -> llm.Embedding
+	9,  // 18: llm.EmbedResponse.usage:type_name -> llm.Usage
+	2,  // 19: llm.CompareProfile.domains:type_name -> llm.Domain
+	7,  // 20: llm.CompareProfile.scores:type_name -> llm.DimensionScores
+	23, // 21: llm.CompareUsersRequest.users:type_name -> llm.CompareProfile
+	9,  // 22: llm.CompareUsersResponse.usage:type_name -> llm.Usage
+	6,  // 23: llm.LLMService.GetEvaluation:input_type -> llm.GetEvaluationRequest
+	12, // 24: llm.LLMService.GetArea:input_type -> llm.GetAreaRequest
+	1,  // 25: llm.LLMService.GetDomain:input_type -> llm.GetDomainRequest
+	6,  // 26: llm.LLMService.StreamEvaluation:input_type -> llm.GetEvaluationRequest
+	14, // 27: llm.LLMService.BatchGetDomain:input_type -> llm.BatchGetDomainRequest
+	16, // 28: llm.LLMService.BatchGetArea:input_type -> llm.BatchGetAreaRequest
+	18, // 29: llm.LLMService.ExtractRequirements:input_type -> llm.ExtractRequirementsRequest
+	20, // 30: llm.LLMService.Embed:input_type -> llm.EmbedRequest
+	24, // 31: llm.LLMService.CompareUsers:input_type -> llm.CompareUsersRequest
+	10, // 32: llm.LLMService.GetEvaluation:output_type -> llm.GetEvaluationResponse
+	13, // 33: llm.LLMService.GetArea:output_type -> llm.GetAreaResponse
+	3,  // 34: llm.LLMService.GetDomain:output_type -> llm.GetDomainResponse
+	11, // 35: llm.LLMService.StreamEvaluation:output_type -> llm.StreamEvaluationResponse
+	15, // 36: llm.LLMService.BatchGetDomain:output_type -> llm.BatchGetDomainResponse
+	17, // 37: llm.LLMService.BatchGetArea:output_type -> llm.BatchGetAreaResponse
+	19, // 38: llm.LLMService.ExtractRequirements:output_type -> llm.ExtractRequirementsResponse
+	22, // 39: llm.LLMService.Embed:output_type -> llm.EmbedResponse
+	25, // 40: llm.LLMService.CompareUsers:output_type -> llm.CompareUsersResponse
+	32, // [32:41] is the sub-list for method output_type
+	23, // [23:32] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_llm_proto_init() }
@@ -825,7 +2133,7 @@ func file_llm_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_llm_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
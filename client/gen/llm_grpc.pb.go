@@ -19,9 +19,15 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	LLMService_GetEvaluation_FullMethodName = "/llm.LLMService/GetEvaluation"
-	LLMService_GetArea_FullMethodName       = "/llm.LLMService/GetArea"
-	LLMService_GetDomain_FullMethodName     = "/llm.LLMService/GetDomain"
+	LLMService_GetEvaluation_FullMethodName       = "/llm.LLMService/GetEvaluation"
+	LLMService_GetArea_FullMethodName             = "/llm.LLMService/GetArea"
+	LLMService_GetDomain_FullMethodName           = "/llm.LLMService/GetDomain"
+	LLMService_StreamEvaluation_FullMethodName    = "/llm.LLMService/StreamEvaluation"
+	LLMService_BatchGetDomain_FullMethodName      = "/llm.LLMService/BatchGetDomain"
+	LLMService_BatchGetArea_FullMethodName        = "/llm.LLMService/BatchGetArea"
+	LLMService_ExtractRequirements_FullMethodName = "/llm.LLMService/ExtractRequirements"
+	LLMService_Embed_FullMethodName               = "/llm.LLMService/Embed"
+	LLMService_CompareUsers_FullMethodName        = "/llm.LLMService/CompareUsers"
 )
 
 // LLMServiceClient is the client API for LLMService service.
@@ -33,6 +39,12 @@ type LLMServiceClient interface {
 	GetEvaluation(ctx context.Context, in *GetEvaluationRequest, opts ...grpc.CallOption) (*GetEvaluationResponse, error)
 	GetArea(ctx context.Context, in *GetAreaRequest, opts ...grpc.CallOption) (*GetAreaResponse, error)
 	GetDomain(ctx context.Context, in *GetDomainRequest, opts ...grpc.CallOption) (*GetDomainResponse, error)
+	StreamEvaluation(ctx context.Context, in *GetEvaluationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamEvaluationResponse], error)
+	BatchGetDomain(ctx context.Context, in *BatchGetDomainRequest, opts ...grpc.CallOption) (*BatchGetDomainResponse, error)
+	BatchGetArea(ctx context.Context, in *BatchGetAreaRequest, opts ...grpc.CallOption) (*BatchGetAreaResponse, error)
+	ExtractRequirements(ctx context.Context, in *ExtractRequirementsRequest, opts ...grpc.CallOption) (*ExtractRequirementsResponse, error)
+	Embed(ctx context.Context, in *EmbedRequest, opts ...grpc.CallOption) (*EmbedResponse, error)
+	CompareUsers(ctx context.Context, in *CompareUsersRequest, opts ...grpc.CallOption) (*CompareUsersResponse, error)
 }
 
 type lLMServiceClient struct {
@@ -73,6 +85,75 @@ func (c *lLMServiceClient) GetDomain(ctx context.Context, in *GetDomainRequest,
 	return out, nil
 }
 
+func (c *lLMServiceClient) StreamEvaluation(ctx context.Context, in *GetEvaluationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamEvaluationResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LLMService_ServiceDesc.Streams[0], LLMService_StreamEvaluation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetEvaluationRequest, StreamEvaluationResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LLMService_StreamEvaluationClient = grpc.ServerStreamingClient[StreamEvaluationResponse]
+
+func (c *lLMServiceClient) BatchGetDomain(ctx context.Context, in *BatchGetDomainRequest, opts ...grpc.CallOption) (*BatchGetDomainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetDomainResponse)
+	err := c.cc.Invoke(ctx, LLMService_BatchGetDomain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMServiceClient) BatchGetArea(ctx context.Context, in *BatchGetAreaRequest, opts ...grpc.CallOption) (*BatchGetAreaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetAreaResponse)
+	err := c.cc.Invoke(ctx, LLMService_BatchGetArea_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMServiceClient) ExtractRequirements(ctx context.Context, in *ExtractRequirementsRequest, opts ...grpc.CallOption) (*ExtractRequirementsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExtractRequirementsResponse)
+	err := c.cc.Invoke(ctx, LLMService_ExtractRequirements_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMServiceClient) Embed(ctx context.Context, in *EmbedRequest, opts ...grpc.CallOption) (*EmbedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmbedResponse)
+	err := c.cc.Invoke(ctx, LLMService_Embed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMServiceClient) CompareUsers(ctx context.Context, in *CompareUsersRequest, opts ...grpc.CallOption) (*CompareUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompareUsersResponse)
+	err := c.cc.Invoke(ctx, LLMService_CompareUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LLMServiceServer is the server API for LLMService service.
 // All implementations must embed UnimplementedLLMServiceServer
 // for forward compatibility.
@@ -82,6 +163,12 @@ type LLMServiceServer interface {
 	GetEvaluation(context.Context, *GetEvaluationRequest) (*GetEvaluationResponse, error)
 	GetArea(context.Context, *GetAreaRequest) (*GetAreaResponse, error)
 	GetDomain(context.Context, *GetDomainRequest) (*GetDomainResponse, error)
+	StreamEvaluation(*GetEvaluationRequest, grpc.ServerStreamingServer[StreamEvaluationResponse]) error
+	BatchGetDomain(context.Context, *BatchGetDomainRequest) (*BatchGetDomainResponse, error)
+	BatchGetArea(context.Context, *BatchGetAreaRequest) (*BatchGetAreaResponse, error)
+	ExtractRequirements(context.Context, *ExtractRequirementsRequest) (*ExtractRequirementsResponse, error)
+	Embed(context.Context, *EmbedRequest) (*EmbedResponse, error)
+	CompareUsers(context.Context, *CompareUsersRequest) (*CompareUsersResponse, error)
 	mustEmbedUnimplementedLLMServiceServer()
 }
 
@@ -101,6 +188,24 @@ func (UnimplementedLLMServiceServer) GetArea(context.Context, *GetAreaRequest) (
 func (UnimplementedLLMServiceServer) GetDomain(context.Context, *GetDomainRequest) (*GetDomainResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDomain not implemented")
 }
+func (UnimplementedLLMServiceServer) StreamEvaluation(*GetEvaluationRequest, grpc.ServerStreamingServer[StreamEvaluationResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvaluation not implemented")
+}
+func (UnimplementedLLMServiceServer) BatchGetDomain(context.Context, *BatchGetDomainRequest) (*BatchGetDomainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetDomain not implemented")
+}
+func (UnimplementedLLMServiceServer) BatchGetArea(context.Context, *BatchGetAreaRequest) (*BatchGetAreaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetArea not implemented")
+}
+func (UnimplementedLLMServiceServer) ExtractRequirements(context.Context, *ExtractRequirementsRequest) (*ExtractRequirementsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtractRequirements not implemented")
+}
+func (UnimplementedLLMServiceServer) Embed(context.Context, *EmbedRequest) (*EmbedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Embed not implemented")
+}
+func (UnimplementedLLMServiceServer) CompareUsers(context.Context, *CompareUsersRequest) (*CompareUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareUsers not implemented")
+}
 func (UnimplementedLLMServiceServer) mustEmbedUnimplementedLLMServiceServer() {}
 func (UnimplementedLLMServiceServer) testEmbeddedByValue()                    {}
 
@@ -176,6 +281,107 @@ func _LLMService_GetDomain_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LLMService_StreamEvaluation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetEvaluationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LLMServiceServer).StreamEvaluation(m, &grpc.GenericServerStream[GetEvaluationRequest, StreamEvaluationResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LLMService_StreamEvaluationServer = grpc.ServerStreamingServer[StreamEvaluationResponse]
+
+func _LLMService_BatchGetDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).BatchGetDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_BatchGetDomain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).BatchGetDomain(ctx, req.(*BatchGetDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMService_BatchGetArea_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetAreaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).BatchGetArea(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_BatchGetArea_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).BatchGetArea(ctx, req.(*BatchGetAreaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMService_ExtractRequirements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtractRequirementsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).ExtractRequirements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_ExtractRequirements_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).ExtractRequirements(ctx, req.(*ExtractRequirementsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMService_Embed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmbedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).Embed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_Embed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).Embed(ctx, req.(*EmbedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMService_CompareUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).CompareUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_CompareUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).CompareUsers(ctx, req.(*CompareUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LLMService_ServiceDesc is the grpc.ServiceDesc for LLMService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -195,7 +401,33 @@ var LLMService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetDomain",
 			Handler:    _LLMService_GetDomain_Handler,
 		},
+		{
+			MethodName: "BatchGetDomain",
+			Handler:    _LLMService_BatchGetDomain_Handler,
+		},
+		{
+			MethodName: "BatchGetArea",
+			Handler:    _LLMService_BatchGetArea_Handler,
+		},
+		{
+			MethodName: "ExtractRequirements",
+			Handler:    _LLMService_ExtractRequirements_Handler,
+		},
+		{
+			MethodName: "Embed",
+			Handler:    _LLMService_Embed_Handler,
+		},
+		{
+			MethodName: "CompareUsers",
+			Handler:    _LLMService_CompareUsers_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvaluation",
+			Handler:       _LLMService_StreamEvaluation_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "llm.proto",
 }
@@ -1,20 +1,222 @@
 package client
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	llmv1 "github.com/GitEval/GitEval-Backend/client/gen"
+	"github.com/GitEval/GitEval-Backend/client/openai"
 	"github.com/GitEval/GitEval-Backend/conf"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"io"
 	"log"
+	"time"
 )
 
-func NewLLMClient(config *conf.LLMConfig) llmv1.LLMServiceClient {
-	// 建立 gRPC 连接
-	conn, err := grpc.Dial(config.Addr, grpc.WithInsecure(), grpc.WithBlock())
+// ErrLLMUnavailable llm服务不可用,熔断中或者重试之后依然失败
+var ErrLLMUnavailable = errors.New("llm service is unavailable")
+
+// 重试的退避时间,每次翻倍
+const retryBackoff = 200 * time.Millisecond
+
+// 可以选择的llm后端
+const (
+	BackendGRPC   = "grpc"
+	BackendOpenAI = "openai"
+)
+
+// CacheVersion 推断结果缓存的版本,后端,模型或者prompt变化之后不再命中之前的缓存
+// grpc服务的模型和prompt在后端无法得知,只能区分服务地址,修改之后需要配置inference.cacheVersion
+func CacheVersion(config *conf.LLMConfig) string {
+	if config.Backend == BackendOpenAI {
+		return BackendOpenAI + ":" + config.OpenAI.Model + ":" + openai.PromptVersion
+	}
+	return BackendGRPC + ":" + config.Addr
+}
+
+func NewLLMClient(config *conf.LLMConfig) *LLMClient {
+	c := &LLMClient{
+		cfg:     config,
+		breaker: newBreaker(config.FailureThreshold, time.Duration(config.Cooldown)*time.Second),
+	}
+
+	if config.Backend == BackendOpenAI {
+		c.next = openai.NewClient(config.OpenAI)
+		return c
+	}
+
+	// 不阻塞地建立 gRPC 连接,llm服务没有启动也不影响后端启动,调用时才会真正连接
+	conn, err := grpc.Dial(config.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
-		log.Fatalf("could not connect to server: %v", err)
+		log.Printf("could not connect to llm server: %v", err)
+		c.dialErr = err
+		return c
 	}
 
-	// 创建并返回 gRPC 客户端
-	client := llmv1.NewLLMServiceClient(conn)
-	return client
+	c.next = llmv1.NewLLMServiceClient(conn)
+	return c
+}
+
+// LLMClient 对 gRPC 客户端的包装,增加了超时,重试和熔断
+type LLMClient struct {
+	next    llmv1.LLMServiceClient
+	dialErr error
+	cfg     *conf.LLMConfig
+	breaker *breaker
+}
+
+func (c *LLMClient) GetEvaluation(ctx context.Context, in *llmv1.GetEvaluationRequest, opts ...grpc.CallOption) (*llmv1.GetEvaluationResponse, error) {
+	return invoke(ctx, c, c.cfg.EvaluationTimeout, func(ctx context.Context) (*llmv1.GetEvaluationResponse, error) {
+		return c.next.GetEvaluation(ctx, in, opts...)
+	})
+}
+
+func (c *LLMClient) GetArea(ctx context.Context, in *llmv1.GetAreaRequest, opts ...grpc.CallOption) (*llmv1.GetAreaResponse, error) {
+	return invoke(ctx, c, c.cfg.Timeout, func(ctx context.Context) (*llmv1.GetAreaResponse, error) {
+		return c.next.GetArea(ctx, in, opts...)
+	})
+}
+
+func (c *LLMClient) GetDomain(ctx context.Context, in *llmv1.GetDomainRequest, opts ...grpc.CallOption) (*llmv1.GetDomainResponse, error) {
+	return invoke(ctx, c, c.cfg.Timeout, func(ctx context.Context) (*llmv1.GetDomainResponse, error) {
+		return c.next.GetDomain(ctx, in, opts...)
+	})
+}
+
+func (c *LLMClient) ExtractRequirements(ctx context.Context, in *llmv1.ExtractRequirementsRequest, opts ...grpc.CallOption) (*llmv1.ExtractRequirementsResponse, error) {
+	return invoke(ctx, c, c.cfg.Timeout, func(ctx context.Context) (*llmv1.ExtractRequirementsResponse, error) {
+		return c.next.ExtractRequirements(ctx, in, opts...)
+	})
+}
+
+func (c *LLMClient) Embed(ctx context.Context, in *llmv1.EmbedRequest, opts ...grpc.CallOption) (*llmv1.EmbedResponse, error) {
+	return invoke(ctx, c, c.cfg.Timeout, func(ctx context.Context) (*llmv1.EmbedResponse, error) {
+		return c.next.Embed(ctx, in, opts...)
+	})
+}
+
+// CompareUsers 和生成评价一样比较耗时,使用评价的超时
+func (c *LLMClient) CompareUsers(ctx context.Context, in *llmv1.CompareUsersRequest, opts ...grpc.CallOption) (*llmv1.CompareUsersResponse, error) {
+	return invoke(ctx, c, c.cfg.EvaluationTimeout, func(ctx context.Context) (*llmv1.CompareUsersResponse, error) {
+		return c.next.CompareUsers(ctx, in, opts...)
+	})
+}
+
+// BatchGetDomain 批量推断需要的时间更长,使用单独的超时
+func (c *LLMClient) BatchGetDomain(ctx context.Context, in *llmv1.BatchGetDomainRequest, opts ...grpc.CallOption) (*llmv1.BatchGetDomainResponse, error) {
+	return invoke(ctx, c, c.cfg.BatchTimeout, func(ctx context.Context) (*llmv1.BatchGetDomainResponse, error) {
+		return c.next.BatchGetDomain(ctx, in, opts...)
+	})
+}
+
+func (c *LLMClient) BatchGetArea(ctx context.Context, in *llmv1.BatchGetAreaRequest, opts ...grpc.CallOption) (*llmv1.BatchGetAreaResponse, error) {
+	return invoke(ctx, c, c.cfg.BatchTimeout, func(ctx context.Context) (*llmv1.BatchGetAreaResponse, error) {
+		return c.next.BatchGetArea(ctx, in, opts...)
+	})
+}
+
+// StreamEvaluation 流式生成评价,已经开始输出之后无法重试,所以只在建立流的时候重试
+// 流的生命周期由调用方的ctx控制,不设置超时
+func (c *LLMClient) StreamEvaluation(ctx context.Context, in *llmv1.GetEvaluationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[llmv1.StreamEvaluationResponse], error) {
+	stream, err := invoke(ctx, c, 0, func(ctx context.Context) (grpc.ServerStreamingClient[llmv1.StreamEvaluationResponse], error) {
+		return c.next.StreamEvaluation(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &evaluationStream{ServerStreamingClient: stream, c: c}, nil
+}
+
+// evaluationStream 接收过程中出错同样计入熔断
+type evaluationStream struct {
+	grpc.ServerStreamingClient[llmv1.StreamEvaluationResponse]
+	c *LLMClient
+}
+
+func (s *evaluationStream) Recv() (*llmv1.StreamEvaluationResponse, error) {
+	res, err := s.ServerStreamingClient.Recv()
+	if err == nil || err == io.EOF || s.Context().Err() != nil {
+		return res, err
+	}
+	if isUnhealthy(err) {
+		s.c.breaker.Failure()
+		return nil, fmt.Errorf("%w: %v", ErrLLMUnavailable, err)
+	}
+	return nil, err
+}
+
+// invoke 带超时,重试和熔断地调用一次rpc
+func invoke[T any](ctx context.Context, c *LLMClient, timeout int, call func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if c.dialErr != nil {
+		return zero, fmt.Errorf("%w: %v", ErrLLMUnavailable, c.dialErr)
+	}
+	if !c.breaker.Allow() {
+		return zero, fmt.Errorf("%w: circuit breaker is open", ErrLLMUnavailable)
+	}
+
+	var err error
+	for attempt := 0; attempt <= max(c.cfg.Retries, 0); attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				c.breaker.Cancel()
+				return zero, ctx.Err()
+			case <-time.After(retryBackoff << (attempt - 1)):
+			}
+		}
+
+		var res T
+		res, err = callWithTimeout(ctx, timeout, call)
+		if err == nil {
+			c.breaker.Success()
+			return res, nil
+		}
+		//调用方取消了请求,不算作llm服务的问题
+		if ctx.Err() != nil {
+			c.breaker.Cancel()
+			return zero, err
+		}
+		if !isTransient(err) {
+			break
+		}
+	}
+
+	if !isUnhealthy(err) {
+		//参数错误之类的问题说明服务本身是正常的
+		c.breaker.Success()
+		return zero, err
+	}
+	c.breaker.Failure()
+	return zero, fmt.Errorf("%w: %v", ErrLLMUnavailable, err)
+}
+
+func callWithTimeout[T any](ctx context.Context, timeout int, call func(ctx context.Context) (T, error)) (T, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+	}
+	return call(ctx)
+}
+
+// isTransient 可以重试的错误
+func isTransient(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+	return false
+}
+
+// isUnhealthy 说明llm服务不健康的错误,会计入熔断
+func isUnhealthy(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal, codes.Unknown:
+		return true
+	}
+	return false
 }
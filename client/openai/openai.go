@@ -0,0 +1,318 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	llmv1 "github.com/GitEval/GitEval-Backend/client/gen"
+	"github.com/GitEval/GitEval-Backend/conf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Client 使用兼容openai的chat completions接口实现 llmv1.LLMServiceClient
+// prompt和输出的解析都在这里完成,不再需要单独的llm服务
+// 返回的错误都是grpc的status,这样外层的重试和熔断可以正常工作
+type Client struct {
+	cfg  conf.OpenAIConfig
+	http *http.Client
+}
+
+var _ llmv1.LLMServiceClient = (*Client)(nil)
+
+func NewClient(cfg conf.OpenAIConfig) *Client {
+	return &Client{
+		cfg: cfg,
+		//超时由调用方的ctx控制
+		http: &http.Client{},
+	}
+}
+
+func (c *Client) GetArea(ctx context.Context, in *llmv1.GetAreaRequest, _ ...grpc.CallOption) (*llmv1.GetAreaResponse, error) {
+	var res areaResult
+	meta, err := c.complete(ctx, "area.tmpl", in, "area", areaSchema, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &llmv1.GetAreaResponse{Area: res.Area, Confidence: res.Confidence, Usage: meta.Usage.toProto()}, nil
+}
+
+func (c *Client) GetDomain(ctx context.Context, in *llmv1.GetDomainRequest, _ ...grpc.CallOption) (*llmv1.GetDomainResponse, error) {
+	var res domainResult
+	meta, err := c.complete(ctx, "domain.tmpl", in, "domain", domainSchema, &res)
+	if err != nil {
+		return nil, err
+	}
+	resp := &llmv1.GetDomainResponse{Usage: meta.Usage.toProto()}
+	for _, d := range res.Domains {
+		resp.Domains = append(resp.Domains, &llmv1.Domain{Domain: d.Domain, Confidence: d.Confidence})
+	}
+	return resp, nil
+}
+
+func (c *Client) GetEvaluation(ctx context.Context, in *llmv1.GetEvaluationRequest, _ ...grpc.CallOption) (*llmv1.GetEvaluationResponse, error) {
+	var res evaluationResult
+	meta, err := c.complete(ctx, "evaluation.tmpl", evaluationData{GetEvaluationRequest: in, Structured: true}, "evaluation", evaluationSchema, &res)
+	if err != nil {
+		return nil, err
+	}
+	resp := res.toResponse(meta.Model)
+	resp.Usage = meta.Usage.toProto()
+	return resp, nil
+}
+
+func (c *Client) ExtractRequirements(ctx context.Context, in *llmv1.ExtractRequirementsRequest, _ ...grpc.CallOption) (*llmv1.ExtractRequirementsResponse, error) {
+	var res requirementsResult
+	meta, err := c.complete(ctx, "requirements.tmpl", in, "requirements", requirementsSchema, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &llmv1.ExtractRequirementsResponse{
+		Domains:   res.Domains,
+		Languages: res.Languages,
+		Skills:    res.Skills,
+		Usage:     meta.Usage.toProto(),
+	}, nil
+}
+
+func (c *Client) CompareUsers(ctx context.Context, in *llmv1.CompareUsersRequest, _ ...grpc.CallOption) (*llmv1.CompareUsersResponse, error) {
+	var res compareResult
+	meta, err := c.complete(ctx, "compare.tmpl", in, "compare", compareSchema, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &llmv1.CompareUsersResponse{Summary: res.Summary, Usage: meta.Usage.toProto()}, nil
+}
+
+// BatchGetDomain 接口不支持批量,逐个请求
+// 服务不可用时整体失败,其他错误只记录在对应的位置
+func (c *Client) BatchGetDomain(ctx context.Context, in *llmv1.BatchGetDomainRequest, _ ...grpc.CallOption) (*llmv1.BatchGetDomainResponse, error) {
+	resp := &llmv1.BatchGetDomainResponse{
+		Responses: make([]*llmv1.GetDomainResponse, len(in.Requests)),
+		Errors:    make([]string, len(in.Requests)),
+	}
+	for i, req := range in.Requests {
+		res, err := c.GetDomain(ctx, req)
+		if err != nil {
+			if isFatal(err) {
+				return nil, err
+			}
+			res, resp.Errors[i] = &llmv1.GetDomainResponse{}, err.Error()
+		}
+		resp.Responses[i] = res
+	}
+	return resp, nil
+}
+
+// BatchGetArea 接口不支持批量,逐个请求
+func (c *Client) BatchGetArea(ctx context.Context, in *llmv1.BatchGetAreaRequest, _ ...grpc.CallOption) (*llmv1.BatchGetAreaResponse, error) {
+	resp := &llmv1.BatchGetAreaResponse{
+		Responses: make([]*llmv1.GetAreaResponse, len(in.Requests)),
+		Errors:    make([]string, len(in.Requests)),
+	}
+	for i, req := range in.Requests {
+		res, err := c.GetArea(ctx, req)
+		if err != nil {
+			if isFatal(err) {
+				return nil, err
+			}
+			res, resp.Errors[i] = &llmv1.GetAreaResponse{}, err.Error()
+		}
+		resp.Responses[i] = res
+	}
+	return resp, nil
+}
+
+// Embed 使用embeddings接口,没有配置embeddingModel时不可用
+func (c *Client) Embed(ctx context.Context, in *llmv1.EmbedRequest, _ ...grpc.CallOption) (*llmv1.EmbedResponse, error) {
+	if c.cfg.EmbeddingModel == "" {
+		return nil, status.Error(codes.Unimplemented, "openai: embeddingModel is not configured")
+	}
+	if len(in.Texts) == 0 {
+		return &llmv1.EmbedResponse{Model: c.cfg.EmbeddingModel}, nil
+	}
+	body, err := c.post(ctx, "/embeddings", embeddingRequest{Model: c.cfg.EmbeddingModel, Input: in.Texts})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var res embeddingResponse
+	if err := json.NewDecoder(body).Decode(&res); err != nil {
+		return nil, contextError(ctx, err, codes.Internal)
+	}
+	if len(res.Data) != len(in.Texts) {
+		return nil, status.Errorf(codes.Internal, "openai: got %d embeddings for %d texts", len(res.Data), len(in.Texts))
+	}
+	resp := &llmv1.EmbedResponse{
+		Embeddings: make([]*llmv1.Embedding, len(res.Data)),
+		Model:      modelName(res.Model, c.cfg.EmbeddingModel),
+		Usage:      res.Usage.toProto(),
+	}
+	//返回的顺序不一定和输入一致,按照index放回去
+	for _, d := range res.Data {
+		if d.Index < 0 || d.Index >= len(res.Data) || resp.Embeddings[d.Index] != nil {
+			return nil, status.Errorf(codes.Internal, "openai: invalid embedding index %d", d.Index)
+		}
+		resp.Embeddings[d.Index] = &llmv1.Embedding{Values: d.Embedding}
+	}
+	return resp, nil
+}
+
+// StreamEvaluation 先流式生成叙述性的评价,结束之后再生成结构化的评价
+func (c *Client) StreamEvaluation(ctx context.Context, in *llmv1.GetEvaluationRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[llmv1.StreamEvaluationResponse], error) {
+	prompt, err := render("evaluation.tmpl", evaluationData{GetEvaluationRequest: in})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	body, err := c.post(ctx, "/chat/completions", chatRequest{
+		Model:       c.cfg.Model,
+		Messages:    c.messages(prompt),
+		Temperature: c.cfg.Temperature,
+		Stream:      true,
+		//不支持的服务端会忽略这个参数,此时没有用量
+		StreamOptions: &streamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newEvaluationStream(ctx, c, in, body), nil
+}
+
+// completion 一次请求的元信息
+type completion struct {
+	Model string //实际使用的模型
+	Usage *usage
+}
+
+// complete 渲染prompt,要求按照schema输出json并解析
+func (c *Client) complete(ctx context.Context, tmpl string, data any, name string, schema map[string]any, v any) (completion, error) {
+	prompt, err := render(tmpl, data)
+	if err != nil {
+		return completion{}, status.Error(codes.Internal, err.Error())
+	}
+	body, err := c.post(ctx, "/chat/completions", chatRequest{
+		Model:       c.cfg.Model,
+		Messages:    c.messages(prompt),
+		Temperature: c.cfg.Temperature,
+		ResponseFormat: &responseFormat{
+			Type:       "json_schema",
+			JSONSchema: &jsonSchema{Name: name, Schema: schema, Strict: true},
+		},
+	})
+	if err != nil {
+		return completion{}, err
+	}
+	defer body.Close()
+
+	var res chatResponse
+	if err := json.NewDecoder(body).Decode(&res); err != nil {
+		return completion{}, contextError(ctx, err, codes.Internal)
+	}
+	if len(res.Choices) == 0 {
+		return completion{}, status.Error(codes.Internal, "openai: empty choices")
+	}
+	if err := json.Unmarshal([]byte(stripCodeFence(res.Choices[0].Message.Content)), v); err != nil {
+		return completion{}, status.Errorf(codes.Internal, "openai: invalid %s output: %v", name, err)
+	}
+	return completion{Model: modelName(res.Model, c.cfg.Model), Usage: res.Usage}, nil
+}
+
+func (c *Client) messages(prompt string) []message {
+	system, _ := render("system.tmpl", nil)
+	return []message{
+		{Role: "system", Content: system},
+		{Role: "user", Content: prompt},
+	}
+}
+
+// post 发送请求,返回响应体,非200的状态码转换成对应的grpc错误
+func (c *Client) post(ctx context.Context, path string, req any) (io.ReadCloser, error) {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.cfg.BaseURL, "/")+path, bytes.NewReader(b))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.cfg.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	}
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, contextError(ctx, err, codes.Unavailable)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, status.Errorf(httpCode(resp.StatusCode), "openai: %s: %s", resp.Status, msg)
+	}
+	return resp.Body, nil
+}
+
+// httpCode http状态码对应的grpc错误码
+func httpCode(code int) codes.Code {
+	switch {
+	case code == http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case code == http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case code == http.StatusForbidden:
+		return codes.PermissionDenied
+	case code == http.StatusNotFound:
+		return codes.NotFound
+	case code == http.StatusRequestTimeout || code == http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case code >= 500:
+		return codes.Unavailable
+	default:
+		return codes.InvalidArgument
+	}
+}
+
+// contextError ctx超时或者取消时返回对应的错误,否则使用给定的错误码
+func contextError(ctx context.Context, err error, code codes.Code) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(code, fmt.Sprintf("openai: %v", err))
+}
+
+// isFatal 服务本身的问题,继续请求剩下的也会失败
+func isFatal(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Canceled, codes.Unauthenticated, codes.PermissionDenied:
+		return true
+	}
+	return false
+}
+
+// stripCodeFence 有的模型不支持json_schema,会把json包在代码块里
+func stripCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```json")
+	s = strings.TrimPrefix(s, "```")
+	return strings.TrimSpace(strings.TrimSuffix(s, "```"))
+}
+
+func modelName(actual, configured string) string {
+	if actual != "" {
+		return actual
+	}
+	return configured
+}
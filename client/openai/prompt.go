@@ -0,0 +1,208 @@
+package openai
+
+import (
+	"bytes"
+	"embed"
+	llmv1 "github.com/GitEval/GitEval-Backend/client/gen"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// PromptVersion prompt修改之后需要更新,会随评价一起保存
+const PromptVersion = "openai-v1"
+
+//go:embed prompts/*.tmpl
+var promptFS embed.FS
+
+var prompts = template.Must(template.New("prompts").Funcs(template.FuncMap{
+	"join": func(s []string) string {
+		if len(s) == 0 {
+			return "unknown"
+		}
+		return strings.Join(s, ", ")
+	},
+	"truncate": truncate,
+}).ParseFS(promptFS, "prompts/*.tmpl"))
+
+// evaluationData 生成评价的模板参数
+type evaluationData struct {
+	*llmv1.GetEvaluationRequest
+	Narrative  string //流式生成的叙述性评价,生成结构化评价时需要保持一致
+	Structured bool   //是否需要结构化的输出
+}
+
+func render(name string, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := prompts.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// truncate 按照字符截断,避免README过长
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "..."
+}
+
+// 输出的json schema,需要服务端支持 response_format 的 json_schema
+var (
+	areaSchema = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"area":       map[string]any{"type": "string"},
+			"confidence": map[string]any{"type": "number", "minimum": 0, "maximum": 1},
+		},
+		"required":             []string{"area", "confidence"},
+		"additionalProperties": false,
+	}
+
+	domainSchema = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"domains": map[string]any{
+				"type":     "array",
+				"maxItems": 3,
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"domain":     map[string]any{"type": "string"},
+						"confidence": map[string]any{"type": "number", "minimum": 0, "maximum": 1},
+					},
+					"required":             []string{"domain", "confidence"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"required":             []string{"domains"},
+		"additionalProperties": false,
+	}
+
+	stringsSchema = map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+
+	requirementsSchema = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"domains":   stringsSchema,
+			"languages": stringsSchema,
+			"skills":    stringsSchema,
+		},
+		"required":             []string{"domains", "languages", "skills"},
+		"additionalProperties": false,
+	}
+
+	compareSchema = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"summary": map[string]any{"type": "string"},
+		},
+		"required":             []string{"summary"},
+		"additionalProperties": false,
+	}
+
+	scoreSchema = map[string]any{"type": "number", "minimum": 0, "maximum": 100}
+
+	evaluationSchema = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"evaluation": map[string]any{"type": "string"},
+			"scores": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"code_quality":  scoreSchema,
+					"activity":      scoreSchema,
+					"collaboration": scoreSchema,
+					"influence":     scoreSchema,
+					"breadth":       scoreSchema,
+				},
+				"required":             []string{"code_quality", "activity", "collaboration", "influence", "breadth"},
+				"additionalProperties": false,
+			},
+			"strengths":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"weaknesses": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"evidence": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"repo":   map[string]any{"type": "string"},
+						"url":    map[string]any{"type": "string"},
+						"reason": map[string]any{"type": "string"},
+					},
+					"required":             []string{"repo", "url", "reason"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"required":             []string{"evaluation", "scores", "strengths", "weaknesses", "evidence"},
+		"additionalProperties": false,
+	}
+)
+
+// 按照schema解析的结果
+type (
+	areaResult struct {
+		Area       string  `json:"area"`
+		Confidence float32 `json:"confidence"`
+	}
+
+	requirementsResult struct {
+		Domains   []string `json:"domains"`
+		Languages []string `json:"languages"`
+		Skills    []string `json:"skills"`
+	}
+
+	compareResult struct {
+		Summary string `json:"summary"`
+	}
+
+	domainResult struct {
+		Domains []struct {
+			Domain     string  `json:"domain"`
+			Confidence float32 `json:"confidence"`
+		} `json:"domains"`
+	}
+
+	evaluationResult struct {
+		Evaluation string `json:"evaluation"`
+		Scores     struct {
+			CodeQuality   float32 `json:"code_quality"`
+			Activity      float32 `json:"activity"`
+			Collaboration float32 `json:"collaboration"`
+			Influence     float32 `json:"influence"`
+			Breadth       float32 `json:"breadth"`
+		} `json:"scores"`
+		Strengths  []string `json:"strengths"`
+		Weaknesses []string `json:"weaknesses"`
+		Evidence   []struct {
+			Repo   string `json:"repo"`
+			URL    string `json:"url"`
+			Reason string `json:"reason"`
+		} `json:"evidence"`
+	}
+)
+
+func (r evaluationResult) toResponse(model string) *llmv1.GetEvaluationResponse {
+	res := &llmv1.GetEvaluationResponse{
+		Evaluation:    r.Evaluation,
+		ModelVersion:  model,
+		PromptVersion: PromptVersion,
+		Scores: &llmv1.DimensionScores{
+			CodeQuality:   r.Scores.CodeQuality,
+			Activity:      r.Scores.Activity,
+			Collaboration: r.Scores.Collaboration,
+			Influence:     r.Scores.Influence,
+			Breadth:       r.Scores.Breadth,
+		},
+		Strengths:  r.Strengths,
+		Weaknesses: r.Weaknesses,
+	}
+	for _, e := range r.Evidence {
+		res.Evidence = append(res.Evidence, &llmv1.Evidence{Repo: e.Repo, Url: e.URL, Reason: e.Reason})
+	}
+	return res
+}
@@ -0,0 +1,114 @@
+package openai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	llmv1 "github.com/GitEval/GitEval-Backend/client/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"strings"
+)
+
+// evaluationStream 把chat completions的SSE流转换成 grpc.ServerStreamingClient
+// 叙述性的评价结束之后,再请求一次结构化的评价作为最后一条消息返回
+type evaluationStream struct {
+	ctx       context.Context
+	c         *Client
+	in        *llmv1.GetEvaluationRequest
+	body      io.ReadCloser
+	scanner   *bufio.Scanner
+	narrative strings.Builder
+	model     string
+	usage     *usage //叙述部分的用量,在最后一个片段中返回
+	finished  bool
+}
+
+func newEvaluationStream(ctx context.Context, c *Client, in *llmv1.GetEvaluationRequest, body io.ReadCloser) *evaluationStream {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return &evaluationStream{ctx: ctx, c: c, in: in, body: body, scanner: scanner}
+}
+
+func (s *evaluationStream) Recv() (*llmv1.StreamEvaluationResponse, error) {
+	if s.finished {
+		return nil, io.EOF
+	}
+	for s.scanner.Scan() {
+		line := strings.TrimSpace(s.scanner.Text())
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk chatChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			s.body.Close()
+			return nil, status.Errorf(codes.Internal, "openai: invalid stream chunk: %v", err)
+		}
+		if chunk.Model != "" {
+			s.model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			s.usage = chunk.Usage
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		s.narrative.WriteString(chunk.Choices[0].Delta.Content)
+		return &llmv1.StreamEvaluationResponse{Delta: chunk.Choices[0].Delta.Content}, nil
+	}
+	s.body.Close()
+	if err := s.scanner.Err(); err != nil {
+		return nil, contextError(s.ctx, err, codes.Unavailable)
+	}
+	if err := s.ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	return s.finish()
+}
+
+// finish 生成结构化的评价,作为最后一条消息
+func (s *evaluationStream) finish() (*llmv1.StreamEvaluationResponse, error) {
+	s.finished = true
+	var res evaluationResult
+	meta, err := s.c.complete(s.ctx, "evaluation.tmpl", evaluationData{
+		GetEvaluationRequest: s.in,
+		Narrative:            s.narrative.String(),
+		Structured:           true,
+	}, "evaluation", evaluationSchema, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	structured := res.toResponse(modelName(s.model, meta.Model))
+	return &llmv1.StreamEvaluationResponse{
+		Done:          true,
+		ModelVersion:  structured.ModelVersion,
+		PromptVersion: structured.PromptVersion,
+		Scores:        structured.Scores,
+		Strengths:     structured.Strengths,
+		Weaknesses:    structured.Weaknesses,
+		Evidence:      structured.Evidence,
+		Usage:         s.usage.add(meta.Usage).toProto(),
+	}, nil
+}
+
+func (s *evaluationStream) Header() (metadata.MD, error) { return nil, nil }
+func (s *evaluationStream) Trailer() metadata.MD         { return nil }
+func (s *evaluationStream) CloseSend() error             { return nil }
+func (s *evaluationStream) Context() context.Context     { return s.ctx }
+func (s *evaluationStream) SendMsg(any) error {
+	return errors.New("openai: SendMsg is not supported")
+}
+func (s *evaluationStream) RecvMsg(any) error {
+	return errors.New("openai: RecvMsg is not supported")
+}
@@ -0,0 +1,90 @@
+package openai
+
+import llmv1 "github.com/GitEval/GitEval-Backend/client/gen"
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type jsonSchema struct {
+	Name   string         `json:"name"`
+	Schema map[string]any `json:"schema"`
+	Strict bool           `json:"strict"`
+}
+
+type responseFormat struct {
+	Type       string      `json:"type"`
+	JSONSchema *jsonSchema `json:"json_schema,omitempty"`
+}
+
+type chatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []message       `json:"messages"`
+	Temperature    float64         `json:"temperature"`
+	Stream         bool            `json:"stream,omitempty"`
+	StreamOptions  *streamOptions  `json:"stream_options,omitempty"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+// streamOptions 要求在流的最后返回token的用量
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type usage struct {
+	PromptTokens     int32 `json:"prompt_tokens"`
+	CompletionTokens int32 `json:"completion_tokens"`
+}
+
+type chatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message message `json:"message"`
+	} `json:"choices"`
+	Usage *usage `json:"usage"`
+}
+
+// chatChunk 流式返回的片段
+type chatChunk struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Delta        message `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *usage `json:"usage"`
+}
+
+type embeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embeddingResponse embeddings接口只有prompt_tokens
+type embeddingResponse struct {
+	Model string `json:"model"`
+	Data  []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Usage *usage `json:"usage"`
+}
+
+// toProto 服务端没有返回用量时为nil
+func (u *usage) toProto() *llmv1.Usage {
+	if u == nil {
+		return nil
+	}
+	return &llmv1.Usage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens}
+}
+
+// add 合并两次请求的用量,都没有用量时为nil
+func (u *usage) add(o *usage) *usage {
+	if u == nil {
+		return o
+	}
+	if o == nil {
+		return u
+	}
+	return &usage{PromptTokens: u.PromptTokens + o.PromptTokens, CompletionTokens: u.CompletionTokens + o.CompletionTokens}
+}
@@ -8,14 +8,27 @@ var ProviderSet = wire.NewSet(
 	NewVipperSetting,
 	NewAppConf,
 	NewGitHubConfig,
+	NewGitLabConfig,
+	NewGiteaConfig,
 	NewDataConfig,
 	NewLLMConfig,
 	NewJWTConfig,
 	NewCacheConfig,
+	NewPublicConfig,
+	NewCrawlerConfig,
+	NewInferenceConfig,
+	NewAdminConfig,
+	NewBatchInferenceConfig,
+	NewEmbeddingConfig,
+	NewQueueConfig,
+	NewRefreshConfig,
+	NewLockConfig,
 )
 
 type AppConf struct {
-	Addr string `yaml:"addr"`
+	Addr           string   `yaml:"addr"`
+	AllowedOrigins []string `yaml:"allowedOrigins"` //允许建立websocket连接的来源,为空时只允许和请求的Host相同的来源
+	TrustedProxies []string `yaml:"trustedProxies"` //信任的反向代理的ip或者网段,只有来自这些代理的X-Forwarded-For才会被使用,为空时直接使用连接的ip
 	//其他配置也可以加到这个里面
 }
 
@@ -23,6 +36,23 @@ type AppConf struct {
 type GitHubConfig struct {
 	ClientID     string `yaml:"clientID"`
 	ClientSecret string `yaml:"clientSecret"`
+	Token        string `yaml:"token"` //服务端访问github使用的token,不填的话使用无认证的客户端
+}
+
+// GitLabConfig 自建或者官方的gitlab,没有配置clientID时不启用
+type GitLabConfig struct {
+	BaseURL      string `yaml:"baseURL"` //例如 https://gitlab.com
+	ClientID     string `yaml:"clientID"`
+	ClientSecret string `yaml:"clientSecret"`
+	RedirectURL  string `yaml:"redirectURL"` //需要和gitlab应用中配置的回调地址一致
+}
+
+// GiteaConfig 自建或者官方的gitea,没有配置clientID时不启用
+type GiteaConfig struct {
+	BaseURL      string `yaml:"baseURL"` //例如 https://gitea.com
+	ClientID     string `yaml:"clientID"`
+	ClientSecret string `yaml:"clientSecret"`
+	RedirectURL  string `yaml:"redirectURL"` //需要和gitea应用中配置的回调地址一致
 }
 type DataConfig struct {
 	Addr string `yaml:"addr"`
@@ -30,7 +60,30 @@ type DataConfig struct {
 
 // 配置结构体
 type LLMConfig struct {
-	Addr string `yaml:"addr"`
+	Backend           string       `yaml:"backend"`           //grpc或者openai,默认grpc
+	Addr              string       `yaml:"addr"`              //grpc服务的地址
+	Timeout           int          `yaml:"timeout"`           //单次调用的超时时间,单位秒
+	EvaluationTimeout int          `yaml:"evaluationTimeout"` //生成评价比较耗时,单独设置超时时间,单位秒
+	BatchTimeout      int          `yaml:"batchTimeout"`      //批量推断的超时时间,单位秒
+	Retries           int          `yaml:"retries"`           //遇到暂时性错误时的重试次数
+	FailureThreshold  int          `yaml:"failureThreshold"`  //连续失败多少次后熔断
+	Cooldown          int          `yaml:"cooldown"`          //熔断后多久尝试恢复,单位秒
+	Audit             bool         `yaml:"audit"`             //是否记录每次调用的审计日志
+	OpenAI            OpenAIConfig `yaml:"openai"`
+}
+
+// OpenAIConfig 兼容openai的chat completions接口,例如本地的llama.cpp或者vLLM
+type OpenAIConfig struct {
+	BaseURL        string  `yaml:"baseURL"` //例如 http://localhost:8000/v1
+	APIKey         string  `yaml:"apiKey"`
+	Model          string  `yaml:"model"`
+	Temperature    float64 `yaml:"temperature"`
+	EmbeddingModel string  `yaml:"embeddingModel"` //计算向量的模型,不填时不能使用相似度搜索
+}
+
+// AdminConfig 管理员可以访问 /admin 下的接口
+type AdminConfig struct {
+	Users []int64 `yaml:"users"` //管理员的用户id
 }
 
 type JWTConfig struct {
@@ -42,6 +95,81 @@ type CacheConf struct {
 	Password string `yaml:"password"`
 }
 
+// PublicConfig 无需登录的公开接口的配置
+type PublicConfig struct {
+	Limit        int `yaml:"limit"`        //每个ip在窗口期内最多的请求数
+	Window       int `yaml:"window"`       //限流的窗口期,单位秒
+	CacheTimeout int `yaml:"cacheTimeout"` //公开查询的用户的缓存时间,单位分钟
+}
+
+// InferenceConfig llm推断结果的置信度阈值,在写入时生效
+type InferenceConfig struct {
+	NationThreshold float64 `yaml:"nationThreshold"` //国籍的置信度低于这个值时设为N/A
+	DomainThreshold float64 `yaml:"domainThreshold"` //领域的置信度低于这个值时不存储
+	CacheTTL        int     `yaml:"cacheTTL"`        //相同输入的推断结果的缓存时间,单位小时,0表示不缓存
+	ReadmeTokens    int     `yaml:"readmeTokens"`    //每个README清洗之后最多保留的token数,0表示不截断
+	CacheVersion    string  `yaml:"cacheVersion"`    //修改之后之前缓存的推断结果全部失效,例如grpc服务更换了模型或者prompt
+}
+
+// BatchInferenceConfig 后台批量推断缺少国籍和领域的用户
+type BatchInferenceConfig struct {
+	Enable       bool `yaml:"enable"`
+	BatchSize    int  `yaml:"batchSize"`    //每次批量请求llm的用户数
+	Budget       int  `yaml:"budget"`       //每次运行最多推断的用户数
+	MinRemaining int  `yaml:"minRemaining"` //github剩余额度低于这个值时停止
+	Interval     int  `yaml:"interval"`     //运行间隔,单位分钟
+}
+
+// EmbeddingConfig 用户画像的向量和相似度搜索
+type EmbeddingConfig struct {
+	Index        string `yaml:"index"`        //向量索引的实现,目前只有bruteforce
+	ReadmeTokens int    `yaml:"readmeTokens"` //画像中每个README摘要最多保留的token数
+	MaxRepos     int    `yaml:"maxRepos"`     //画像中最多包含的仓库数,按照提交数选取
+	BatchSize    int    `yaml:"batchSize"`    //每次请求llm计算的画像数
+	Backfill     bool   `yaml:"backfill"`     //是否在后台为已经有领域但是没有向量的用户计算向量
+	Budget       int    `yaml:"budget"`       //每次补全最多计算的用户数
+	Interval     int    `yaml:"interval"`     //补全的运行间隔,单位分钟
+}
+
+// QueueConfig 持久化的后台任务队列
+type QueueConfig struct {
+	Concurrency  int `yaml:"concurrency"`  //同时执行任务的协程数
+	PollInterval int `yaml:"pollInterval"` //没有任务时的轮询间隔,单位毫秒
+	MaxAttempts  int `yaml:"maxAttempts"`  //最多执行的次数,超过之后放入死信
+	Backoff      int `yaml:"backoff"`      //第一次重试的等待时间,之后每次翻倍,单位秒
+	MaxBackoff   int `yaml:"maxBackoff"`   //重试的最长等待时间,单位秒
+	Lease        int `yaml:"lease"`        //单个任务的超时时间,超过之后可以被其他协程重新领取,单位秒
+	Retention    int `yaml:"retention"`    //已经完成的任务保留的时间,单位小时
+}
+
+// RefreshConfig 定时刷新存储时间最久的用户,排行榜和搜索中最近出现的用户优先
+type RefreshConfig struct {
+	Enable       bool `yaml:"enable"`
+	StaleAfter   int  `yaml:"staleAfter"`   //距离上次同步超过这个时间的用户需要刷新,单位小时
+	ActiveWindow int  `yaml:"activeWindow"` //最近这段时间出现在排行榜或者搜索结果中的用户优先刷新,单位小时
+	BatchSize    int  `yaml:"batchSize"`    //每次从数据库读取的用户数
+	Budget       int  `yaml:"budget"`       //每次运行最多消耗的github请求数,包括之后重新推断领域时获取仓库的请求
+	MinRemaining int  `yaml:"minRemaining"` //github剩余额度低于这个值时停止
+	Interval     int  `yaml:"interval"`     //运行间隔,单位分钟
+}
+
+// LockConfig 同一个用户的同步和推断同时只能有一个在执行
+type LockConfig struct {
+	Expire int `yaml:"expire"` //分布式锁的过期时间,持有期间会自动续期,持有锁的实例退出之后锁会在过期之后释放,单位秒
+}
+
+// CrawlerConfig 关注关系图爬虫的配置
+type CrawlerConfig struct {
+	Enable       bool     `yaml:"enable"`
+	Seeds        []string `yaml:"seeds"`        //种子用户的登录名
+	Depth        int      `yaml:"depth"`        //从种子用户开始的最大深度
+	MaxUsers     int      `yaml:"maxUsers"`     //每次运行最多存储的用户数
+	FanOut       int      `yaml:"fanOut"`       //每个用户最多展开的following和followers数
+	Budget       int      `yaml:"budget"`       //每次运行最多消耗的github请求数
+	MinRemaining int      `yaml:"minRemaining"` //github剩余额度低于这个值时停止
+	Interval     int      `yaml:"interval"`     //运行间隔,单位分钟
+}
+
 func NewAppConf(s *VipperSetting) *AppConf {
 	var appconf = &AppConf{}
 	s.ReadSection("app", appconf)
@@ -52,6 +180,16 @@ func NewGitHubConfig(s *VipperSetting) *GitHubConfig {
 	s.ReadSection("github", GitHubConf)
 	return GitHubConf
 }
+func NewGitLabConfig(s *VipperSetting) *GitLabConfig {
+	var gitLabConf = &GitLabConfig{BaseURL: "https://gitlab.com"}
+	s.ReadSection("gitlab", gitLabConf)
+	return gitLabConf
+}
+func NewGiteaConfig(s *VipperSetting) *GiteaConfig {
+	var giteaConf = &GiteaConfig{BaseURL: "https://gitea.com"}
+	s.ReadSection("gitea", giteaConf)
+	return giteaConf
+}
 func NewDataConfig(s *VipperSetting) *DataConfig {
 	var dataConfig = &DataConfig{}
 	s.ReadSection("data", dataConfig)
@@ -59,10 +197,28 @@ func NewDataConfig(s *VipperSetting) *DataConfig {
 }
 
 func NewLLMConfig(s *VipperSetting) *LLMConfig {
-	var llmConfig = &LLMConfig{}
+	var llmConfig = &LLMConfig{
+		Backend:           "grpc",
+		Timeout:           20,
+		EvaluationTimeout: 60,
+		BatchTimeout:      300,
+		Retries:           2,
+		FailureThreshold:  5,
+		Cooldown:          30,
+		Audit:             true,
+		OpenAI: OpenAIConfig{
+			BaseURL:     "http://localhost:8000/v1",
+			Temperature: 0.2,
+		},
+	}
 	s.ReadSection("llm", llmConfig)
 	return llmConfig
 }
+func NewAdminConfig(s *VipperSetting) *AdminConfig {
+	var adminConf = &AdminConfig{}
+	s.ReadSection("admin", adminConf)
+	return adminConf
+}
 func NewJWTConfig(s *VipperSetting) *JWTConfig {
 	var jwtConf = &JWTConfig{}
 	s.ReadSection("jwt", jwtConf)
@@ -74,3 +230,115 @@ func NewCacheConfig(s *VipperSetting) *CacheConf {
 	s.ReadSection("cache", cacheConf)
 	return cacheConf
 }
+
+func NewPublicConfig(s *VipperSetting) *PublicConfig {
+	var publicConf = &PublicConfig{
+		Limit:        30,
+		Window:       60,
+		CacheTimeout: 60,
+	}
+	s.ReadSection("public", publicConf)
+	return publicConf
+}
+
+func NewInferenceConfig(s *VipperSetting) *InferenceConfig {
+	var inferenceConf = &InferenceConfig{
+		NationThreshold: 0.5,
+		DomainThreshold: 0.6,
+		CacheTTL:        7 * 24,
+		ReadmeTokens:    256,
+	}
+	s.ReadSection("inference", inferenceConf)
+	return inferenceConf
+}
+
+func NewCrawlerConfig(s *VipperSetting) *CrawlerConfig {
+	var crawlerConf = &CrawlerConfig{
+		Depth:        2,
+		MaxUsers:     500,
+		FanOut:       50,
+		Budget:       3000,
+		MinRemaining: 500,
+		Interval:     24 * 60,
+	}
+	s.ReadSection("crawler", crawlerConf)
+	atLeast(1, &crawlerConf.Interval)
+	return crawlerConf
+}
+
+func NewBatchInferenceConfig(s *VipperSetting) *BatchInferenceConfig {
+	var batchConf = &BatchInferenceConfig{
+		BatchSize:    20,
+		Budget:       200,
+		MinRemaining: 1000,
+		Interval:     60,
+	}
+	s.ReadSection("batchInference", batchConf)
+	atLeast(1, &batchConf.Interval, &batchConf.BatchSize)
+	return batchConf
+}
+
+func NewEmbeddingConfig(s *VipperSetting) *EmbeddingConfig {
+	var embeddingConf = &EmbeddingConfig{
+		Index:        "bruteforce",
+		ReadmeTokens: 64,
+		MaxRepos:     5,
+		BatchSize:    32,
+		Budget:       500,
+		Interval:     60,
+	}
+	s.ReadSection("embedding", embeddingConf)
+	atLeast(1, &embeddingConf.Interval, &embeddingConf.BatchSize)
+	return embeddingConf
+}
+
+func NewRefreshConfig(s *VipperSetting) *RefreshConfig {
+	var refreshConf = &RefreshConfig{
+		StaleAfter:   7 * 24,
+		ActiveWindow: 24,
+		BatchSize:    50,
+		Budget:       2000,
+		MinRemaining: 1000,
+		Interval:     60,
+	}
+	s.ReadSection("refresh", refreshConf)
+	atLeast(1, &refreshConf.Interval, &refreshConf.BatchSize)
+	return refreshConf
+}
+
+func NewLockConfig(s *VipperSetting) *LockConfig {
+	var lockConf = &LockConfig{
+		Expire: 10 * 60,
+	}
+	s.ReadSection("lock", lockConf)
+	//续期的间隔是过期时间的三分之一,太短时续期过于频繁
+	atLeast(30, &lockConf.Expire)
+	return lockConf
+}
+
+func NewQueueConfig(s *VipperSetting) *QueueConfig {
+	var queueConf = &QueueConfig{
+		Concurrency:  4,
+		PollInterval: 1000,
+		MaxAttempts:  5,
+		Backoff:      10,
+		MaxBackoff:   30 * 60,
+		Lease:        10 * 60,
+		Retention:    7 * 24,
+	}
+	s.ReadSection("queue", queueConf)
+	atLeast(1, &queueConf.Concurrency, &queueConf.PollInterval, &queueConf.MaxAttempts, &queueConf.Backoff, &queueConf.MaxBackoff, &queueConf.Retention)
+	//任务需要在租约内完成,太短时任务还没有结束就会被其他协程重新领取
+	atLeast(60, &queueConf.Lease)
+	return queueConf
+}
+
+// atLeast 把读取到的配置限制在最小值以上
+// 后台任务的间隔,批量大小之类的配置不是正数时 time.NewTicker 会panic,或者任务永远不会结束
+func atLeast(lower int, values ...*int) {
+	for _, v := range values {
+		if *v < lower {
+			*v = lower
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/api/request"
+	"github.com/GitEval/GitEval-Backend/api/response"
+	"github.com/GitEval/GitEval-Backend/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type AdminServiceProxy interface {
+	GetLLMUsage(ctx context.Context, days, page, pageSize int) ([]model.LLMUsage, []model.LLMUsage, error)
+	GetDeadJobs(ctx context.Context, page, pageSize int) ([]model.Job, error)
+	RequeueJob(ctx context.Context, id int64) (bool, error)
+}
+
+type AdminController struct {
+	adminService AdminServiceProxy
+}
+
+func NewAdminController(adminService AdminServiceProxy) *AdminController {
+	return &AdminController{adminService: adminService}
+}
+
+// GetLLMUsage 获取llm的用量
+// @Summary 按天和按用户聚合llm的调用次数,失败次数,token用量和平均耗时
+// @Description 只有配置中的管理员可以访问
+// @Tags Admin
+// @Param days query int false "统计最近多少天,默认7天"
+// @Param page query int true "按用户聚合的分页参数表示这是第几页"
+// @Param page_size query int true "每页返回的用户数量"
+// @Produce json
+// @Success 200 {object} response.Success{data=response.LLMUsageResp} "获取成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Failure 403 {object} response.Err "不是管理员"
+// @Router /api/v1/admin/llmUsage [get]
+func (c *AdminController) GetLLMUsage(ctx *gin.Context) {
+	var req request.GetLLMUsage
+	if err := ctx.ShouldBindQuery(&req); err != nil || req.Days < 0 || req.Page <= 0 || req.PageSize <= 0 {
+		ctx.JSON(http.StatusBadRequest, response.Err{Err: errors.New("page and page_size must be positive")})
+		return
+	}
+	if req.Days == 0 {
+		req.Days = 7
+	}
+
+	daily, users, err := c.adminService.GetLLMUsage(ctx, req.Days, req.Page, req.PageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{Err: fmt.Errorf("GetLLMUsage: %w", err)})
+		return
+	}
+	ctx.JSON(http.StatusOK, response.Success{Data: response.LLMUsageResp{Daily: daily, Users: users}, Msg: "success"})
+}
+
+// GetDeadJobs 获取死信中的任务
+// @Summary 分页获取超过重试次数或者遇到不可重试错误的后台任务
+// @Description 只有配置中的管理员可以访问
+// @Tags Admin
+// @Param page query int true "分页参数表示这是第几页"
+// @Param page_size query int true "每页返回的任务数量"
+// @Produce json
+// @Success 200 {object} response.Success{data=response.DeadJobsResp} "获取成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Failure 403 {object} response.Err "不是管理员"
+// @Router /api/v1/admin/deadJobs [get]
+func (c *AdminController) GetDeadJobs(ctx *gin.Context) {
+	var req request.GetDeadJobs
+	if err := ctx.ShouldBindQuery(&req); err != nil || req.Page <= 0 || req.PageSize <= 0 {
+		ctx.JSON(http.StatusBadRequest, response.Err{Err: errors.New("page and page_size must be positive")})
+		return
+	}
+
+	jobs, err := c.adminService.GetDeadJobs(ctx, req.Page, req.PageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{Err: fmt.Errorf("GetDeadJobs: %w", err)})
+		return
+	}
+	ctx.JSON(http.StatusOK, response.Success{Data: response.DeadJobsResp{Jobs: jobs}, Msg: "success"})
+}
+
+// RequeueJob 重新执行死信中的任务
+// @Summary 把死信中的任务放回队列,重试次数清零
+// @Description 只有配置中的管理员可以访问
+// @Tags Admin
+// @Param id query int true "任务的id"
+// @Produce json
+// @Success 200 {object} response.Success "已经放回队列"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Failure 403 {object} response.Err "不是管理员"
+// @Failure 404 {object} response.Err "任务不存在或者不在死信中"
+// @Router /api/v1/admin/requeueJob [post]
+func (c *AdminController) RequeueJob(ctx *gin.Context) {
+	var req request.RequeueJob
+	if err := ctx.ShouldBindQuery(&req); err != nil || req.ID <= 0 {
+		ctx.JSON(http.StatusBadRequest, response.Err{Err: errors.New("id must be positive")})
+		return
+	}
+
+	ok, err := c.adminService.RequeueJob(ctx, req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{Err: fmt.Errorf("RequeueJob: %w", err)})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusNotFound, response.Err{Err: errors.New("job is not dead")})
+		return
+	}
+	ctx.JSON(http.StatusOK, response.Success{Msg: "success"})
+}
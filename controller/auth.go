@@ -9,8 +9,8 @@ import (
 )
 
 type AuthServiceProxy interface {
-	Login(ctx context.Context) (url string, err error)
-	CallBack(ctx context.Context, code string) (userId int64, err error)
+	Login(ctx context.Context, forge string) (url string, err error)
+	CallBack(ctx context.Context, forge string, code string) (userId int64, err error)
 }
 
 type GenerateJWTer interface {
@@ -31,16 +31,23 @@ func NewAuthController(authService AuthServiceProxy, jwt GenerateJWTer) *AuthCon
 }
 
 // Login 用户登录
-// @Summary github用户登录授权接口
-// @Description github用户登录授权接口,会自动重定向到github的授权接口上
+// @Summary 用户登录授权接口
+// @Description 用户登录授权接口,会自动重定向到对应代码托管平台的授权接口上
 // @Tags Auth
+// @Param forge query string false "代码托管平台,github,gitlab或者gitea,默认为github"
 // @Produce json
 // @Success 200 {object} response.Success "登录成功"
 // @Failure 400 {object} response.Err "请求参数错误"
 // @Failure 500 {object} response.Err "内部错误"
 // @Router /api/v1/auth/login [get]
 func (c *AuthController) Login(ctx *gin.Context) {
-	url, err := c.authService.Login(ctx)
+	var req request.LoginReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{Err: err})
+		return
+	}
+
+	url, err := c.authService.Login(ctx, req.Forge)
 	if err != nil {
 		// 处理错误，比如返回一个错误页面或重定向到错误页面
 		ctx.JSON(http.StatusInternalServerError, response.Err{Err: err})
@@ -56,7 +63,9 @@ func (c *AuthController) Login(ctx *gin.Context) {
 // @Summary 使用code进行最终登录
 // @Description 使用code进行最终登录同时异步用来初始化这个用户,会返回一个token
 // @Tags Auth
-// @Param code query string true "github重定向的code"
+// @Param code query string true "代码托管平台重定向的code"
+// @Param forge query string false "代码托管平台,为空时使用state,默认为github"
+// @Param state query string false "登录时携带的state,即代码托管平台的名称"
 // @Produce json
 // @Success 200 {object} response.Success{data=response.CallBack} "初始化成功!"
 // @Failure 400 {object} response.Err "请求参数错误"
@@ -71,8 +80,13 @@ func (c *AuthController) CallBack(ctx *gin.Context) {
 		return
 	}
 
-	userid, err := c.authService.CallBack(ctx, req.Code)
+	forge := req.Forge
+	if forge == "" {
+		forge = req.State
+	}
+	userid, err := c.authService.CallBack(ctx, forge, req.Code)
 	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{Err: err})
 		return
 	}
 
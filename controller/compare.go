@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/api/request"
+	"github.com/GitEval/GitEval-Backend/api/response"
+	"github.com/GitEval/GitEval-Backend/model"
+	"github.com/GitEval/GitEval-Backend/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type CompareServiceProxy interface {
+	CompareUsers(ctx context.Context, requester int64, ids []int64, logins []string, summary bool) (model.Comparison, error)
+}
+
+type CompareController struct {
+	compareService CompareServiceProxy
+}
+
+func NewCompareController(compareService CompareServiceProxy) *CompareController {
+	return &CompareController{compareService: compareService}
+}
+
+// CompareUsers 并排对比多个开发者
+// @Summary 对比2到5个开发者的评分,领域,语言,活动和排行榜中的位置
+// @Description 领域和语言是所有人的并集,每个人的置信度和权重与之一一对应;summary为true时生成llm的对比总结,失败时其他数据仍然返回
+// @Tags User
+// @Param id query []int false "用户的user_id,可以重复多次" collectionFormat(multi)
+// @Param login query []string false "github的登录名,可以重复多次" collectionFormat(multi)
+// @Param summary query bool false "是否生成对比总结"
+// @Produce json
+// @Success 200 {object} response.Success{data=response.CompareResp} "对比成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Failure 404 {object} response.Err "用户不存在"
+// @Router /api/v1/user/compare [get]
+func (c *CompareController) CompareUsers(ctx *gin.Context) {
+	UserID, err := getUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("auth: %w", err),
+		})
+		return
+	}
+
+	var req request.CompareUsers
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("invalid request: %w", err),
+		})
+		return
+	}
+	if n := len(req.IDs) + len(req.Logins); n < service.MinCompareUsers || n > service.MaxCompareUsers {
+		ctx.JSON(http.StatusBadRequest, response.Err{Err: service.ErrCompareUsers})
+		return
+	}
+
+	comparison, err := c.compareService.CompareUsers(ctx, UserID, req.IDs, req.Logins, req.Summary)
+	switch {
+	case errors.Is(err, service.ErrCompareUsers):
+		ctx.JSON(http.StatusBadRequest, response.Err{Err: err})
+		return
+	case errors.Is(err, service.ErrUserNotFound):
+		ctx.JSON(http.StatusNotFound, response.Err{Err: err})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, response.Err{
+			Err: fmt.Errorf("CompareUsers: %w", err),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, response.Success{Data: response.CompareResp{Comparison: comparison}, Msg: "success"})
+}
@@ -5,4 +5,12 @@ import "github.com/google/wire"
 var ProviderSet = wire.NewSet(
 	NewUserController,
 	NewAuthController,
+	NewOrgController,
+	NewPublicController,
+	NewDiscoveryController,
+	NewAdminController,
+	NewMatchController,
+	NewSimilarController,
+	NewCompareController,
+	NewSyncController,
 )
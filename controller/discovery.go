@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/api/request"
+	"github.com/GitEval/GitEval-Backend/api/response"
+	"github.com/GitEval/GitEval-Backend/model"
+	"github.com/GitEval/GitEval-Backend/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type DiscoveryServiceProxy interface {
+	CreateQuery(ctx context.Context, query model.DiscoveryQuery) (model.DiscoveryQuery, error)
+	RunQuery(ctx context.Context, userId int64, id int64) (model.DiscoveryQuery, error)
+	GetQueries(ctx context.Context, userId int64) ([]model.DiscoveryQuery, error)
+	GetQuery(ctx context.Context, userId int64, id int64) (model.DiscoveryQuery, error)
+	GetResults(ctx context.Context, userId int64, id int64, page int, pageSize int) ([]model.DiscoveryUser, error)
+}
+
+type DiscoveryController struct {
+	discoveryService DiscoveryServiceProxy
+}
+
+func NewDiscoveryController(discoveryService DiscoveryServiceProxy) *DiscoveryController {
+	return &DiscoveryController{discoveryService: discoveryService}
+}
+
+// CreateQuery 创建人才发现查询
+// @Summary 创建并运行一个人才发现查询
+// @Description 基于github的用户搜索,例如 "Go developers in Berlin with >100 followers",匹配的用户会被导入,评分并异步推断国籍和领域
+// @Tags Discovery
+// @Accept json
+// @Param body body request.CreateDiscovery true "查询条件"
+// @Produce json
+// @Success 200 {object} response.Success{data=model.DiscoveryQuery} "创建成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Router /api/v1/discovery/create [post]
+func (c *DiscoveryController) CreateQuery(ctx *gin.Context) {
+	UserID, err := getUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("auth: %w", err),
+		})
+		return
+	}
+
+	var req request.CreateDiscovery
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("invalid request: %w", err),
+		})
+		return
+	}
+
+	query, err := c.discoveryService.CreateQuery(ctx, model.DiscoveryQuery{
+		CreatorID:    UserID,
+		Name:         req.Name,
+		Keyword:      req.Keyword,
+		Location:     req.Location,
+		Language:     req.Language,
+		MinFollowers: req.MinFollowers,
+		Limit:        req.Limit,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{
+			Err: fmt.Errorf("CreateQuery: %w", err),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Success{Data: query, Msg: "success"})
+	return
+}
+
+// RunQuery 重新运行人才发现查询
+// @Summary 重新运行一个已经保存的人才发现查询
+// @Tags Discovery
+// @Param id query int true "查询的id"
+// @Produce json
+// @Success 200 {object} response.Success{data=model.DiscoveryQuery} "运行成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Failure 404 {object} response.Err "查询不存在"
+// @Router /api/v1/discovery/run [get]
+func (c *DiscoveryController) RunQuery(ctx *gin.Context) {
+	UserID, err := getUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("auth: %w", err),
+		})
+		return
+	}
+
+	var req request.GetDiscovery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: err,
+		})
+		return
+	}
+
+	query, err := c.discoveryService.RunQuery(ctx, UserID, req.ID)
+	if errors.Is(err, service.ErrQueryNotFound) {
+		ctx.JSON(http.StatusNotFound, response.Err{Err: err})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{
+			Err: fmt.Errorf("RunQuery: %w", err),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Success{Data: query, Msg: "success"})
+	return
+}
+
+// GetQueries 获取当前用户保存的人才发现查询
+// @Summary 获取当前用户保存的人才发现查询以及导入状态
+// @Tags Discovery
+// @Produce json
+// @Success 200 {object} response.Success{data=response.DiscoveriesResp} "获取成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Router /api/v1/discovery/list [get]
+func (c *DiscoveryController) GetQueries(ctx *gin.Context) {
+	UserID, err := getUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("auth: %w", err),
+		})
+		return
+	}
+
+	queries, err := c.discoveryService.GetQueries(ctx, UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{
+			Err: fmt.Errorf("GetQueries: %w", err),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Success{Data: response.DiscoveriesResp{Queries: queries}, Msg: "success"})
+	return
+}
+
+// GetResults 获取人才发现查询的结果
+// @Summary 获取人才发现查询的导入状态和匹配的用户
+// @Tags Discovery
+// @Param id query int true "查询的id"
+// @Param page query int true "分页参数表示这是第几页"
+// @Param page_size query int true "每页返回的用户数量"
+// @Produce json
+// @Success 200 {object} response.Success{data=response.DiscoveryResultsResp} "获取成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Failure 404 {object} response.Err "查询不存在"
+// @Router /api/v1/discovery/results [get]
+func (c *DiscoveryController) GetResults(ctx *gin.Context) {
+	UserID, err := getUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("auth: %w", err),
+		})
+		return
+	}
+
+	var req request.GetDiscoveryResults
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: err,
+		})
+		return
+	}
+
+	query, err := c.discoveryService.GetQuery(ctx, UserID, req.ID)
+	if errors.Is(err, service.ErrQueryNotFound) {
+		ctx.JSON(http.StatusNotFound, response.Err{Err: err})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{
+			Err: fmt.Errorf("GetQuery: %w", err),
+		})
+		return
+	}
+
+	results, err := c.discoveryService.GetResults(ctx, UserID, req.ID, req.Page, req.PageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{
+			Err: fmt.Errorf("GetResults: %w", err),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Success{Data: response.DiscoveryResultsResp{Query: query, Results: results}, Msg: "success"})
+	return
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/api/request"
+	"github.com/GitEval/GitEval-Backend/api/response"
+	"github.com/GitEval/GitEval-Backend/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultMatchLimit = 20
+	maxMatchLimit     = 100
+)
+
+type MatchServiceProxy interface {
+	MatchJob(ctx context.Context, userId int64, description string, nation *string, minScore float64, limit int) (model.JobRequirements, []model.Candidate, error)
+}
+
+type MatchController struct {
+	matchService MatchServiceProxy
+}
+
+func NewMatchController(matchService MatchServiceProxy) *MatchController {
+	return &MatchController{matchService: matchService}
+}
+
+// MatchJob 根据职位描述匹配候选人
+// @Summary 从职位描述中提取要求的领域,语言和技术栈,返回排序之后的候选人以及匹配的原因
+// @Description 按照和用户的领域,语言以及技术栈的重合度结合评分排序
+// @Tags Match
+// @Accept json
+// @Param body body request.MatchJob true "职位描述和筛选条件"
+// @Produce json
+// @Success 200 {object} response.Success{data=response.MatchResp} "匹配成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Failure 503 {object} response.Err "llm服务暂时不可用"
+// @Router /api/v1/match/job [post]
+func (c *MatchController) MatchJob(ctx *gin.Context) {
+	UserID, err := getUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("auth: %w", err),
+		})
+		return
+	}
+
+	var req request.MatchJob
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("invalid request: %w", err),
+		})
+		return
+	}
+	if strings.TrimSpace(req.JobDescription) == "" {
+		ctx.JSON(http.StatusBadRequest, response.Err{Err: errors.New("job_description is required")})
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultMatchLimit
+	}
+	req.Limit = min(req.Limit, maxMatchLimit)
+
+	requirements, candidates, err := c.matchService.MatchJob(ctx, UserID, req.JobDescription, req.Nation, req.MinScore, req.Limit)
+	if err != nil {
+		writeLLMErr(ctx, fmt.Errorf("MatchJob: %w", err))
+		return
+	}
+	ctx.JSON(http.StatusOK, response.Success{Data: response.MatchResp{Requirements: requirements, Candidates: candidates}, Msg: "success"})
+}
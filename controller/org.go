@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/api/request"
+	"github.com/GitEval/GitEval-Backend/api/response"
+	"github.com/GitEval/GitEval-Backend/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type OrgServiceProxy interface {
+	RefreshOrganization(ctx context.Context, login string, userId int64) (model.Organization, error)
+	GetUserOrganizations(ctx context.Context, userId int64) ([]model.Organization, error)
+	GetOrgProfile(ctx context.Context, login string) (model.OrgProfile, error)
+	GetOrgMembers(ctx context.Context, login string, page int, pageSize int) ([]model.User, error)
+}
+
+type OrgController struct {
+	orgService OrgServiceProxy
+}
+
+func NewOrgController(orgService OrgServiceProxy) *OrgController {
+	return &OrgController{orgService: orgService}
+}
+
+// GetOrgs 获取当前用户所属的组织
+// @Summary 获取当前用户所属的组织
+// @Tags Org
+// @Produce json
+// @Success 200 {object} response.Success{data=response.OrgsResp} "获取成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Router /api/v1/org/list [get]
+func (c *OrgController) GetOrgs(ctx *gin.Context) {
+	UserID, err := getUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("auth: %w", err),
+		})
+		return
+	}
+
+	orgs, err := c.orgService.GetUserOrganizations(ctx, UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{
+			Err: fmt.Errorf("GetUserOrganizations: %w", err),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Success{Data: response.OrgsResp{Orgs: orgs}, Msg: "success"})
+	return
+}
+
+// GetOrgInfo 获取组织的画像
+// @Summary 根据组织的登录名获取组织的画像
+// @Description 包括组织的基本信息,成员数,star最多的仓库以及成员的领域分布
+// @Tags Org
+// @Param login query string true "组织的登录名"
+// @Produce json
+// @Success 200 {object} response.Success{data=model.OrgProfile} "获取成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Router /api/v1/org/getInfo [get]
+func (c *OrgController) GetOrgInfo(ctx *gin.Context) {
+	var req request.GetOrg
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: err,
+		})
+		return
+	}
+
+	profile, err := c.orgService.GetOrgProfile(ctx, req.Login)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{
+			Err: fmt.Errorf("GetOrgProfile: %w", err),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Success{Data: profile, Msg: "success"})
+	return
+}
+
+// GetOrgMembers 获取组织的成员
+// @Summary 根据组织的登录名获取组织成员以及他们的评分
+// @Tags Org
+// @Param login query string true "组织的登录名"
+// @Param page query int true "分页参数表示这是第几页,从1开始"
+// @Param page_size query int true "每页返回的用户数量,最多100"
+// @Produce json
+// @Success 200 {object} response.Success{data=response.OrgMembersResp} "获取成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Router /api/v1/org/members [get]
+func (c *OrgController) GetOrgMembers(ctx *gin.Context) {
+	var req request.GetOrgMembers
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: err,
+		})
+		return
+	}
+
+	users, err := c.orgService.GetOrgMembers(ctx, req.Login, req.Page, req.PageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{
+			Err: fmt.Errorf("GetOrgMembers: %w", err),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Success{Data: response.OrgMembersResp{Users: users}, Msg: "success"})
+	return
+}
+
+// RefreshOrg 重新拉取组织的信息
+// @Summary 重新拉取组织的信息
+// @Description 从github拉取组织的信息,公开成员和仓库,成员会同时计算评分,耗时较长,组织的成员替换为拉取到的公开成员
+// @Tags Org
+// @Param login query string true "组织的登录名"
+// @Produce json
+// @Success 200 {object} response.Success{data=model.Organization} "拉取成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Router /api/v1/org/refresh [post]
+func (c *OrgController) RefreshOrg(ctx *gin.Context) {
+	UserID, err := getUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("auth: %w", err),
+		})
+		return
+	}
+
+	var req request.GetOrg
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: err,
+		})
+		return
+	}
+
+	org, err := c.orgService.RefreshOrganization(ctx, req.Login, UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{
+			Err: fmt.Errorf("RefreshOrganization: %w", err),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Success{Data: org, Msg: "success"})
+	return
+}
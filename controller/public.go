@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/api/request"
+	"github.com/GitEval/GitEval-Backend/api/response"
+	"github.com/GitEval/GitEval-Backend/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type PublicServiceProxy interface {
+	GetPublicUser(ctx context.Context, login string, infer bool) (model.User, error)
+	GetDomains(ctx context.Context, userId int64) []model.Domain
+	GetInterests(ctx context.Context, userId int64) []model.Interest
+}
+
+type PublicController struct {
+	publicService PublicServiceProxy
+}
+
+func NewPublicController(publicService PublicServiceProxy) *PublicController {
+	return &PublicController{publicService: publicService}
+}
+
+// GetUser 无需登录获取任意github用户的信息
+// @Summary 根据github登录名获取用户信息和评分
+// @Description 不需要登录,按照ip限流,查询过的用户会缓存一段时间,infer为true时会异步推断用户的国籍和领域
+// @Tags Public
+// @Param login query string true "github登录名"
+// @Param infer query bool false "是否推断国籍和领域"
+// @Produce json
+// @Success 200 {object} response.Success{data=response.User} "获取成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Failure 429 {object} response.Err "请求过于频繁"
+// @Failure 503 {object} response.Err "限流服务暂时不可用"
+// @Router /api/v1/public/user [get]
+func (c *PublicController) GetUser(ctx *gin.Context) {
+	var req request.GetPublicUser
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("invalid request: %w", err),
+		})
+		return
+	}
+	if req.Login == "" {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: errors.New("login is required"),
+		})
+		return
+	}
+
+	user, err := c.publicService.GetPublicUser(ctx, req.Login, req.Infer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{
+			Err: fmt.Errorf("GetPublicUser: %w", err),
+		})
+		return
+	}
+
+	domain := c.publicService.GetDomains(ctx, user.ID)
+	interests := c.publicService.GetInterests(ctx, user.ID)
+	ctx.JSON(http.StatusOK, response.Success{
+		Data: response.User{
+			U:         user,
+			Domain:    domain,
+			Interests: interests,
+		},
+		Msg: "success",
+	})
+	return
+}
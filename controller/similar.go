@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/api/request"
+	"github.com/GitEval/GitEval-Backend/api/response"
+	"github.com/GitEval/GitEval-Backend/model"
+	"github.com/GitEval/GitEval-Backend/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultSimilarLimit = 20
+	maxSimilarLimit     = 100
+)
+
+type SimilarServiceProxy interface {
+	SimilarTo(ctx context.Context, requester, userId int64, limit int) ([]model.SimilarUser, error)
+	SemanticSearch(ctx context.Context, requester int64, query string, limit int) ([]model.SimilarUser, error)
+}
+
+type SimilarController struct {
+	similarService SimilarServiceProxy
+}
+
+func NewSimilarController(similarService SimilarServiceProxy) *SimilarController {
+	return &SimilarController{similarService: similarService}
+}
+
+// SimilarUsers 查找相似的开发者
+// @Summary 按照画像向量的余弦相似度查找和指定用户相似的开发者
+// @Description 画像由简介,领域,语言和README摘要组成,用户还没有向量时使用存储的信息计算
+// @Tags Similar
+// @Param user_id query int false "用户的user_id,不填时为自己"
+// @Param limit query int false "最多返回的用户数,默认20,不超过100"
+// @Produce json
+// @Success 200 {object} response.Success{data=response.SimilarResp} "查找成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Failure 404 {object} response.Err "用户还没有简介和领域"
+// @Failure 503 {object} response.Err "llm服务暂时不可用"
+// @Router /api/v1/similar/user [get]
+func (c *SimilarController) SimilarUsers(ctx *gin.Context) {
+	UserID, err := getUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("auth: %w", err),
+		})
+		return
+	}
+
+	var req request.SimilarUsers
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("invalid request: %w", err),
+		})
+		return
+	}
+	if req.UserId == 0 {
+		req.UserId = UserID
+	}
+
+	users, err := c.similarService.SimilarTo(ctx, UserID, req.UserId, similarLimit(req.Limit))
+	if errors.Is(err, service.ErrNoProfile) {
+		ctx.JSON(http.StatusNotFound, response.Err{Err: err})
+		return
+	}
+	if err != nil {
+		writeLLMErr(ctx, fmt.Errorf("SimilarTo: %w", err))
+		return
+	}
+	ctx.JSON(http.StatusOK, response.Success{Data: response.SimilarResp{Users: users}, Msg: "success"})
+}
+
+// SemanticSearch 语义搜索开发者
+// @Summary 按照自由文本的描述搜索画像最接近的开发者
+// @Tags Similar
+// @Param q query string true "自由文本的描述"
+// @Param limit query int false "最多返回的用户数,默认20,不超过100"
+// @Produce json
+// @Success 200 {object} response.Success{data=response.SimilarResp} "搜索成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Failure 503 {object} response.Err "llm服务暂时不可用"
+// @Router /api/v1/similar/search [get]
+func (c *SimilarController) SemanticSearch(ctx *gin.Context) {
+	UserID, err := getUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("auth: %w", err),
+		})
+		return
+	}
+
+	var req request.SemanticSearch
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("invalid request: %w", err),
+		})
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		ctx.JSON(http.StatusBadRequest, response.Err{Err: errors.New("q is required")})
+		return
+	}
+
+	users, err := c.similarService.SemanticSearch(ctx, UserID, req.Query, similarLimit(req.Limit))
+	if err != nil {
+		writeLLMErr(ctx, fmt.Errorf("SemanticSearch: %w", err))
+		return
+	}
+	ctx.JSON(http.StatusOK, response.Success{Data: response.SimilarResp{Users: users}, Msg: "success"})
+}
+
+func similarLimit(limit int) int {
+	if limit <= 0 {
+		return defaultSimilarLimit
+	}
+	return min(limit, maxSimilarLimit)
+}
@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/api/response"
+	"github.com/GitEval/GitEval-Backend/conf"
+	"github.com/GitEval/GitEval-Backend/model"
+	"github.com/GitEval/GitEval-Backend/service"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+type SyncServiceProxy interface {
+	GetSyncStatus(ctx context.Context, userId int64) (model.SyncStatus, error)
+	WatchSyncStatus(ctx context.Context, userId int64, onChange func(status model.SyncStatus) error) error
+	CreateTicket(ctx context.Context, userId int64) (string, error)
+	RedeemTicket(ctx context.Context, ticket string) (int64, error)
+}
+
+type SyncController struct {
+	syncService SyncServiceProxy
+	app         *conf.AppConf
+}
+
+func NewSyncController(syncService SyncServiceProxy, app *conf.AppConf) *SyncController {
+	return &SyncController{syncService: syncService, app: app}
+}
+
+// GetSyncStatus 获取登录之后初始化的进度
+// @Summary 获取自己的关注关系,领域和国籍是否已经同步完成
+// @Description state依次为pending,fetching_graph,scoring,inferring_domain,inferring_nation,done,任何阶段最终失败时为failed;error不为空且没有失败时表示正在重试
+// @Tags User
+// @Produce json
+// @Success 200 {object} response.Success{data=model.SyncStatus} "获取成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Failure 404 {object} response.Err "最近没有同步过"
+// @Router /api/v1/user/syncStatus [get]
+func (c *SyncController) GetSyncStatus(ctx *gin.Context) {
+	UserID, err := getUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("auth: %w", err),
+		})
+		return
+	}
+
+	status, err := c.syncService.GetSyncStatus(ctx, UserID)
+	if errors.Is(err, service.ErrNoSyncStatus) {
+		ctx.JSON(http.StatusNotFound, response.Err{Err: err})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{
+			Err: fmt.Errorf("GetSyncStatus: %w", err),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, response.Success{
+		Data: status,
+		Msg:  "success",
+	})
+}
+
+// CreateSyncTicket 获取建立websocket连接使用的票据
+// @Summary 获取一次性的websocket票据
+// @Description 浏览器的websocket不能设置请求头,先通过这个接口获取票据,再通过syncStatus/ws?ticket=建立连接;票据30秒内有效,只能使用一次
+// @Tags User
+// @Produce json
+// @Success 200 {object} response.Success{data=response.TicketResp} "获取成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Router /api/v1/user/syncStatus/ticket [post]
+func (c *SyncController) CreateSyncTicket(ctx *gin.Context) {
+	UserID, err := getUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("auth: %w", err),
+		})
+		return
+	}
+
+	ticket, err := c.syncService.CreateTicket(ctx, UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{
+			Err: fmt.Errorf("CreateTicket: %w", err),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, response.Success{
+		Data: response.TicketResp{Ticket: ticket},
+		Msg:  "success",
+	})
+}
+
+// WatchSyncStatus 通过websocket推送初始化进度的变化
+// @Summary 建立websocket连接,推送同步状态的每次变化
+// @Description 通过syncStatus/ticket获取的一次性票据认证,只接受配置中允许的来源;连接建立后先推送当前状态,每条消息和syncStatus接口的响应相同,同步结束或者出错时服务端关闭连接
+// @Tags User
+// @Param ticket query string true "一次性票据"
+// @Success 101 {object} response.Success{data=model.SyncStatus} "切换为websocket"
+// @Failure 401 {object} response.Err "票据无效或者已经使用过"
+// @Router /api/v1/user/syncStatus/ws [get]
+func (c *SyncController) WatchSyncStatus(ctx *gin.Context) {
+	UserID, err := c.syncService.RedeemTicket(ctx, ctx.Query("ticket"))
+	if errors.Is(err, service.ErrInvalidTicket) {
+		ctx.JSON(http.StatusUnauthorized, response.Err{Err: err})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{
+			Err: fmt.Errorf("RedeemTicket: %w", err),
+		})
+		return
+	}
+
+	server := websocket.Server{Handshake: c.checkOrigin, Handler: func(conn *websocket.Conn) {
+		defer conn.Close()
+		watchCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		//连接升级之后请求的ctx不再反映客户端的状态,通过读取判断客户端是否断开
+		go func() {
+			defer cancel()
+			var msg string
+			for websocket.Message.Receive(conn, &msg) == nil {
+			}
+		}()
+
+		err := c.syncService.WatchSyncStatus(watchCtx, UserID, func(status model.SyncStatus) error {
+			return websocket.JSON.Send(conn, response.Success{Data: status, Msg: "success"})
+		})
+		if err != nil && watchCtx.Err() == nil {
+			log.Println("watch sync status failed:", err)
+			websocket.JSON.Send(conn, response.Err{Err: err})
+		}
+	}}
+	server.ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+// checkOrigin 只接受配置中允许的来源,没有配置时只接受和请求的Host相同的来源
+func (c *SyncController) checkOrigin(_ *websocket.Config, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if len(c.app.AllowedOrigins) > 0 {
+		if slices.Contains(c.app.AllowedOrigins, origin) {
+			return nil
+		}
+		return fmt.Errorf("origin %s is not allowed", origin)
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host != req.Host {
+		return fmt.Errorf("origin %s is not allowed", origin)
+	}
+	return nil
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"github.com/GitEval/GitEval-Backend/api/request"
 	"github.com/GitEval/GitEval-Backend/api/response"
+	"github.com/GitEval/GitEval-Backend/client"
 	"github.com/GitEval/GitEval-Backend/model"
+	"github.com/GitEval/GitEval-Backend/service"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
@@ -14,11 +16,14 @@ import (
 type UserServiceProxy interface {
 	GetUserById(ctx context.Context, id int64) (model.User, error)
 	GetLeaderboard(ctx context.Context, userId int64) ([]model.Leaderboard, error)
-	GetDomains(ctx context.Context, userId int64) []string
-	GetEvaluation(ctx context.Context, userId int64) (string, error)
-	GetNationByUserId(ctx context.Context, userId int64) (string, error)
-	GetDomainByUserId(ctx context.Context, userId int64) ([]string, error)
-	SearchUser(ctx context.Context, nation *string, domain string, page int, pageSize int) ([]model.User, error)
+	GetDomains(ctx context.Context, userId int64) []model.Domain
+	GetInterests(ctx context.Context, userId int64) []model.Interest
+	GetEvaluation(ctx context.Context, userId int64, refresh bool) (model.Evaluation, error)
+	StreamEvaluation(ctx context.Context, userId int64, refresh bool, onDelta func(delta string) error) (model.Evaluation, error)
+	GetEvaluationHistory(ctx context.Context, userId int64, page, pageSize int) ([]model.Evaluation, error)
+	GetNationByUserId(ctx context.Context, userId int64) (string, float32, bool, error)
+	GetDomainByUserId(ctx context.Context, userId int64) ([]model.Domain, bool, error)
+	SearchUser(ctx context.Context, nation *string, domain string, minConfidence float64, page int, pageSize int) ([]model.User, error)
 }
 type UserController struct {
 	userService UserServiceProxy
@@ -53,10 +58,12 @@ func (c *UserController) GetUser(ctx *gin.Context) {
 	}
 
 	domain := c.userService.GetDomains(ctx, UserID)
+	interests := c.userService.GetInterests(ctx, UserID)
 	ctx.JSON(http.StatusOK, response.Success{
 		Data: response.User{
-			U:      user,
-			Domain: domain,
+			U:         user,
+			Domain:    domain,
+			Interests: interests,
 		},
 		Msg: "success",
 	})
@@ -93,10 +100,14 @@ func (c *UserController) GetRanking(ctx *gin.Context) {
 
 // GetEvaluation 获取用户评价
 // @Summary 根据userid获取用户评价
+// @Description 默认返回最新的评价,生成评价的输入发生变化或者refresh为true时重新生成,登录过期时直接返回最新的评价
 // @Tags User
+// @Param refresh query bool false "是否强制重新生成"
 // @Produce json
 // @Success 200 {object} response.Success{data=response.EvaluationResp} "登录成功"
 // @Failure 400 {object} response.Err "请求参数错误"
+// @Failure 409 {object} response.Err "正在推断,稍后再试"
+// @Failure 503 {object} response.Err "llm服务暂时不可用"
 // @Router /api/v1/user/getEvaluation [get]
 func (c *UserController) GetEvaluation(ctx *gin.Context) {
 	UserID, err := getUserID(ctx)
@@ -107,17 +118,118 @@ func (c *UserController) GetEvaluation(ctx *gin.Context) {
 		return
 	}
 
-	evaluation, err := c.userService.GetEvaluation(ctx, UserID)
+	var req request.GetEvaluation
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{Err: err})
+		return
+	}
+
+	evaluation, err := c.userService.GetEvaluation(ctx, UserID, req.Refresh)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.Err{Err: fmt.Errorf("GetEvaluation: %w", err)})
+		writeLLMErr(ctx, fmt.Errorf("GetEvaluation: %w", err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.Success{Data: response.EvaluationResp{Evaluation: evaluation}, Msg: "success"})
+	ctx.JSON(http.StatusOK, response.Success{Data: response.NewEvaluationResp(evaluation), Msg: "success"})
 	return
 
 }
 
+// StreamEvaluation 流式获取用户评价
+// @Summary 根据userid流式获取用户评价
+// @Description 使用Server-Sent Events逐段返回评价,message事件为新生成的片段,done事件表示生成完毕并携带结构化的评价,error事件表示生成失败
+// @Tags User
+// @Param refresh query bool false "是否强制重新生成"
+// @Produce text/event-stream
+// @Success 200 {string} string "评价片段"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Failure 409 {object} response.Err "正在推断,稍后再试"
+// @Failure 503 {object} response.Err "llm服务暂时不可用"
+// @Router /api/v1/user/getEvaluation/stream [get]
+func (c *UserController) StreamEvaluation(ctx *gin.Context) {
+	UserID, err := getUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("auth: %w", err),
+		})
+		return
+	}
+
+	var req request.GetEvaluation
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{Err: err})
+		return
+	}
+
+	//客户端断开连接后请求的ctx会被取消,llm的生成也会随之中断
+	reqCtx := ctx.Request.Context()
+	started := false
+	evaluation, err := c.userService.StreamEvaluation(reqCtx, UserID, req.Refresh, func(delta string) error {
+		if !started {
+			startSSE(ctx)
+			started = true
+		}
+		ctx.SSEvent("message", delta)
+		ctx.Writer.Flush()
+		return reqCtx.Err()
+	})
+	if err != nil {
+		//还没有开始输出时按照普通请求返回错误
+		if !started {
+			writeLLMErr(ctx, fmt.Errorf("StreamEvaluation: %w", err))
+			return
+		}
+		if reqCtx.Err() != nil {
+			return
+		}
+		code := ""
+		if errors.Is(err, client.ErrLLMUnavailable) {
+			code = response.CodeLLMUnavailable
+		}
+		ctx.SSEvent("error", gin.H{"error": err.Error(), "code": code})
+		ctx.Writer.Flush()
+		return
+	}
+
+	if !started {
+		startSSE(ctx)
+	}
+	ctx.SSEvent("done", response.NewEvaluationResp(evaluation))
+	ctx.Writer.Flush()
+}
+
+// GetEvaluationHistory 获取用户的历史评价
+// @Summary 根据userid获取用户的历史评价
+// @Tags User
+// @Param page query int true "分页参数表示这是第几页"
+// @Param page_size query int true "每页返回的评价数量"
+// @Produce json
+// @Success 200 {object} response.Success{data=response.EvaluationHistoryResp} "获取成功"
+// @Failure 400 {object} response.Err "请求参数错误"
+// @Router /api/v1/user/getEvaluationHistory [get]
+func (c *UserController) GetEvaluationHistory(ctx *gin.Context) {
+	UserID, err := getUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Err{
+			Err: fmt.Errorf("auth: %w", err),
+		})
+		return
+	}
+
+	var req request.GetEvaluationHistory
+	if err := ctx.ShouldBindQuery(&req); err != nil || req.Page <= 0 || req.PageSize <= 0 {
+		ctx.JSON(http.StatusBadRequest, response.Err{Err: errors.New("page and page_size must be positive")})
+		return
+	}
+
+	evaluations, err := c.userService.GetEvaluationHistory(ctx, UserID, req.Page, req.PageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, response.Err{Err: fmt.Errorf("GetEvaluationHistory: %w", err)})
+		return
+	}
+	ctx.JSON(http.StatusOK, response.Success{Data: response.EvaluationHistoryResp{Evaluations: evaluations}, Msg: "success"})
+}
+
 // GetNation 获取用户所在国家
 // @Summary 根据用户 ID 获取用户所在国家
 // @Tags User
@@ -125,6 +237,8 @@ func (c *UserController) GetEvaluation(ctx *gin.Context) {
 // @Success 200 {object} response.Success{data=response.NationResp} "国家获取成功"
 // @Failure 400 {object} response.Err "请求参数错误"
 // @Failure 404 {object} response.Err "用户未找到"
+// @Failure 409 {object} response.Err "正在推断,稍后再试"
+// @Failure 503 {object} response.Err "llm服务暂时不可用"
 // @Router /api/v1/user/getNation [get]
 func (c *UserController) GetNation(ctx *gin.Context) {
 	UserID, err := getUserID(ctx)
@@ -135,12 +249,12 @@ func (c *UserController) GetNation(ctx *gin.Context) {
 		return
 	}
 
-	nation, err := c.userService.GetNationByUserId(ctx, UserID)
+	nation, confidence, cached, err := c.userService.GetNationByUserId(ctx, UserID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.Err{Err: fmt.Errorf("GetNation: %w", err)})
+		writeLLMErr(ctx, fmt.Errorf("GetNation: %w", err))
 		return
 	}
-	ctx.JSON(http.StatusOK, response.Success{Data: response.NationResp{Nation: nation}, Msg: "success"})
+	ctx.JSON(http.StatusOK, response.Success{Data: response.NationResp{Nation: nation, Confidence: confidence, Cached: cached}, Msg: "success"})
 
 	return
 
@@ -153,6 +267,8 @@ func (c *UserController) GetNation(ctx *gin.Context) {
 // @Success 200 {object} response.Success{data=response.DomainResp} "领域获取成功"
 // @Failure 400 {object} response.Err "请求参数错误"
 // @Failure 404 {object} response.Err "用户未找到"
+// @Failure 409 {object} response.Err "正在推断,稍后再试"
+// @Failure 503 {object} response.Err "llm服务暂时不可用"
 // @Router /api/v1/user/getDomain [get]
 func (c *UserController) GetDomain(ctx *gin.Context) {
 	UserID, err := getUserID(ctx)
@@ -163,12 +279,12 @@ func (c *UserController) GetDomain(ctx *gin.Context) {
 		return
 	}
 
-	domain, err := c.userService.GetDomainByUserId(ctx, UserID)
+	domain, cached, err := c.userService.GetDomainByUserId(ctx, UserID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.Err{Err: fmt.Errorf("GetDomain: %w", err)})
+		writeLLMErr(ctx, fmt.Errorf("GetDomain: %w", err))
 		return
 	}
-	ctx.JSON(http.StatusOK, response.Success{Data: response.DomainResp{Domain: domain}, Msg: "success"})
+	ctx.JSON(http.StatusOK, response.Success{Data: response.DomainResp{Domain: domain, Cached: cached}, Msg: "success"})
 	return
 }
 
@@ -177,6 +293,7 @@ func (c *UserController) GetDomain(ctx *gin.Context) {
 // @Tags User
 // @Param nation query string false "国家，选择性参数"
 // @Param domain query string true "领域，选择性参数"
+// @Param min_confidence query number false "领域和国籍的最低置信度，默认为0"
 // @Param page query int true "分页参数表示这是第几页"
 // @Param page_size query int true "每页返回的用户数量，建议一次返回10个"
 // @Produce json
@@ -192,7 +309,7 @@ func (c *UserController) SearchUser(ctx *gin.Context) {
 		return
 	}
 
-	users, err := c.userService.SearchUser(ctx, req.Nation, req.Domain, req.Page, req.PageSize)
+	users, err := c.userService.SearchUser(ctx, req.Nation, req.Domain, req.MinConfidence, req.Page, req.PageSize)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, response.Err{
 			Err: fmt.Errorf("FailSearch: %w", err),
@@ -232,10 +349,12 @@ func (c *UserController) GetUserInfo(ctx *gin.Context) {
 	}
 
 	domain := c.userService.GetDomains(ctx, req.UserId)
+	interests := c.userService.GetInterests(ctx, req.UserId)
 	ctx.JSON(http.StatusOK, response.Success{
 		Data: response.User{
-			U:      user,
-			Domain: domain,
+			U:         user,
+			Domain:    domain,
+			Interests: interests,
 		},
 		Msg: "success",
 	})
@@ -252,3 +371,26 @@ func getUserID(ctx *gin.Context) (int64, error) {
 	}
 	return UserID, nil
 }
+
+// startSSE 设置Server-Sent Events需要的响应头
+func startSSE(ctx *gin.Context) {
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	//关闭nginx的缓冲,否则片段会被攒到一起返回
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+}
+
+// writeLLMErr llm服务不可用时返回503和错误码,同一个用户的推断正在执行时返回409,其他错误返回500
+func writeLLMErr(ctx *gin.Context, err error) {
+	if errors.Is(err, service.ErrAlreadyRunning) {
+		ctx.JSON(http.StatusConflict, response.Err{Err: err, Code: response.CodeAlreadyRunning})
+		return
+	}
+	if errors.Is(err, client.ErrLLMUnavailable) {
+		ctx.JSON(http.StatusServiceUnavailable, response.Err{Err: err, Code: response.CodeLLMUnavailable})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, response.Err{Err: err})
+}
@@ -15,6 +15,179 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/v1/admin/deadJobs": {
+            "get": {
+                "description": "只有配置中的管理员可以访问",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "分页获取超过重试次数或者遇到不可重试错误的后台任务",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "分页参数表示这是第几页",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页返回的任务数量",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.DeadJobsResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "403": {
+                        "description": "不是管理员",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/llmUsage": {
+            "get": {
+                "description": "只有配置中的管理员可以访问",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "按天和按用户聚合llm的调用次数,失败次数,token用量和平均耗时",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "统计最近多少天,默认7天",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "按用户聚合的分页参数表示这是第几页",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页返回的用户数量",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.LLMUsageResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "403": {
+                        "description": "不是管理员",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/requeueJob": {
+            "post": {
+                "description": "只有配置中的管理员可以访问",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "把死信中的任务放回队列,重试次数清零",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "任务的id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "已经放回队列",
+                        "schema": {
+                            "$ref": "#/definitions/response.Success"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "403": {
+                        "description": "不是管理员",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "404": {
+                        "description": "任务不存在或者不在死信中",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/auth/callBack": {
             "get": {
                 "description": "使用code进行最终登录同时异步用来初始化这个用户,会返回一个token",
@@ -28,10 +201,22 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "github重定向的code",
+                        "description": "代码托管平台重定向的code",
                         "name": "code",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "代码托管平台,为空时使用state,默认为github",
+                        "name": "forge",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "登录时携带的state,即代码托管平台的名称",
+                        "name": "state",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -70,14 +255,22 @@ const docTemplate = `{
         },
         "/api/v1/auth/login": {
             "get": {
-                "description": "github用户登录授权接口,会自动重定向到github的授权接口上",
+                "description": "用户登录授权接口,会自动重定向到对应代码托管平台的授权接口上",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "github用户登录授权接口",
+                "summary": "用户登录授权接口",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "代码托管平台,github,gitlab或者gitea,默认为github",
+                        "name": "forge",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "登录成功",
@@ -132,18 +325,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/getDomain": {
-            "get": {
+        "/api/v1/discovery/create": {
+            "post": {
+                "description": "基于github的用户搜索,例如 \"Go developers in Berlin with \u003e100 followers\",匹配的用户会被导入,评分并异步推断国籍和领域",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Discovery"
+                ],
+                "summary": "创建并运行一个人才发现查询",
+                "parameters": [
+                    {
+                        "description": "查询条件",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateDiscovery"
+                        }
+                    }
                 ],
-                "summary": "根据用户 ID 获取用户的领域",
                 "responses": {
                     "200": {
-                        "description": "领域获取成功",
+                        "description": "创建成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -153,7 +361,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/response.DomainResp"
+                                            "$ref": "#/definitions/model.DiscoveryQuery"
                                         }
                                     }
                                 }
@@ -165,28 +373,22 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/response.Err"
                         }
-                    },
-                    "404": {
-                        "description": "用户未找到",
-                        "schema": {
-                            "$ref": "#/definitions/response.Err"
-                        }
                     }
                 }
             }
         },
-        "/api/v1/user/getEvaluation": {
+        "/api/v1/discovery/list": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Discovery"
                 ],
-                "summary": "根据userid获取用户评价",
+                "summary": "获取当前用户保存的人才发现查询以及导入状态",
                 "responses": {
                     "200": {
-                        "description": "登录成功",
+                        "description": "获取成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -196,7 +398,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/response.EvaluationResp"
+                                            "$ref": "#/definitions/response.DiscoveriesResp"
                                         }
                                     }
                                 }
@@ -212,18 +414,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/getInfo": {
+        "/api/v1/discovery/results": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Discovery"
+                ],
+                "summary": "获取人才发现查询的导入状态和匹配的用户",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "查询的id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "分页参数表示这是第几页",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页返回的用户数量",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "从userid获取用户",
                 "responses": {
                     "200": {
-                        "description": "登录成功",
+                        "description": "获取成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -233,7 +458,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/response.User"
+                                            "$ref": "#/definitions/response.DiscoveryResultsResp"
                                         }
                                     }
                                 }
@@ -245,22 +470,37 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/response.Err"
                         }
+                    },
+                    "404": {
+                        "description": "查询不存在",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/user/getNation": {
+        "/api/v1/discovery/run": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Discovery"
+                ],
+                "summary": "重新运行一个已经保存的人才发现查询",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "查询的id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "根据用户 ID 获取用户所在国家",
                 "responses": {
                     "200": {
-                        "description": "国家获取成功",
+                        "description": "运行成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -270,7 +510,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/response.NationResp"
+                                            "$ref": "#/definitions/model.DiscoveryQuery"
                                         }
                                     }
                                 }
@@ -284,7 +524,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "用户未找到",
+                        "description": "查询不存在",
                         "schema": {
                             "$ref": "#/definitions/response.Err"
                         }
@@ -292,18 +532,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/getRank": {
-            "get": {
+        "/api/v1/match/job": {
+            "post": {
+                "description": "按照和用户的领域,语言以及技术栈的重合度结合评分排序",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Match"
+                ],
+                "summary": "从职位描述中提取要求的领域,语言和技术栈,返回排序之后的候选人以及匹配的原因",
+                "parameters": [
+                    {
+                        "description": "职位描述和筛选条件",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.MatchJob"
+                        }
+                    }
                 ],
-                "summary": "根据userid获取用户的score的排行榜",
                 "responses": {
                     "200": {
-                        "description": "登录成功",
+                        "description": "匹配成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -313,7 +568,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/response.Ranking"
+                                            "$ref": "#/definitions/response.MatchResp"
                                         }
                                     }
                                 }
@@ -325,31 +580,38 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/response.Err"
                         }
+                    },
+                    "503": {
+                        "description": "llm服务暂时不可用",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/user/getUserInfo": {
+        "/api/v1/org/getInfo": {
             "get": {
+                "description": "包括组织的基本信息,成员数,star最多的仓库以及成员的领域分布",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Org"
                 ],
-                "summary": "根据userid获取用户详细信息",
+                "summary": "根据组织的登录名获取组织的画像",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "用户的user_id",
-                        "name": "user_id",
+                        "description": "组织的登录名",
+                        "name": "login",
                         "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "用户信息获取成功",
+                        "description": "获取成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -359,7 +621,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/response.User"
+                                            "$ref": "#/definitions/model.OrgProfile"
                                         }
                                     }
                                 }
@@ -375,39 +637,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/search": {
+        "/api/v1/org/list": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Org"
                 ],
-                "summary": "根据国家和领域搜索用户",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "国家，选择性参数",
-                        "name": "nation",
-                        "in": "query"
+                "summary": "获取当前用户所属的组织",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.OrgsResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/org/members": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Org"
+                ],
+                "summary": "根据组织的登录名获取组织成员以及他们的评分",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "领域，选择性参数",
-                        "name": "domain",
+                        "description": "组织的登录名",
+                        "name": "login",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "分页参数表示这是第几页",
+                        "description": "分页参数表示这是第几页,从1开始",
                         "name": "page",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "每页返回的用户数量，建议一次返回10个",
+                        "description": "每页返回的用户数量,最多100",
                         "name": "page_size",
                         "in": "query",
                         "required": true
@@ -415,7 +708,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "用户搜索成功",
+                        "description": "获取成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -425,7 +718,229 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/response.SearchResp"
+                                            "$ref": "#/definitions/response.OrgMembersResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/org/refresh": {
+            "post": {
+                "description": "从github拉取组织的信息,公开成员和仓库,成员会同时计算评分,耗时较长,组织的成员替换为拉取到的公开成员",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Org"
+                ],
+                "summary": "重新拉取组织的信息",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "组织的登录名",
+                        "name": "login",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "拉取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Organization"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/public/user": {
+            "get": {
+                "description": "不需要登录,按照ip限流,查询过的用户会缓存一段时间,infer为true时会异步推断用户的国籍和领域",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "根据github登录名获取用户信息和评分",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "github登录名",
+                        "name": "login",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "是否推断国籍和领域",
+                        "name": "infer",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "429": {
+                        "description": "请求过于频繁",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "503": {
+                        "description": "限流服务暂时不可用",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/similar/search": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Similar"
+                ],
+                "summary": "按照自由文本的描述搜索画像最接近的开发者",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "自由文本的描述",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "最多返回的用户数,默认20,不超过100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "搜索成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.SimilarResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "503": {
+                        "description": "llm服务暂时不可用",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/similar/user": {
+            "get": {
+                "description": "画像由简介,领域,语言和README摘要组成,用户还没有向量时使用存储的信息计算",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Similar"
+                ],
+                "summary": "按照画像向量的余弦相似度查找和指定用户相似的开发者",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户的user_id,不填时为自己",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "最多返回的用户数,默认20,不超过100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查找成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.SimilarResp"
                                         }
                                     }
                                 }
@@ -437,25 +952,1279 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/response.Err"
                         }
+                    },
+                    "404": {
+                        "description": "用户还没有简介和领域",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "503": {
+                        "description": "llm服务暂时不可用",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/compare": {
+            "get": {
+                "description": "领域和语言是所有人的并集,每个人的置信度和权重与之一一对应;summary为true时生成llm的对比总结,失败时其他数据仍然返回",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "对比2到5个开发者的评分,领域,语言,活动和排行榜中的位置",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "用户的user_id,可以重复多次",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "github的登录名,可以重复多次",
+                        "name": "login",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "是否生成对比总结",
+                        "name": "summary",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "对比成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.CompareResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/getDomain": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "根据用户 ID 获取用户的领域",
+                "responses": {
+                    "200": {
+                        "description": "领域获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.DomainResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "404": {
+                        "description": "用户未找到",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "409": {
+                        "description": "正在推断,稍后再试",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "503": {
+                        "description": "llm服务暂时不可用",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/getEvaluation": {
+            "get": {
+                "description": "默认返回最新的评价,生成评价的输入发生变化或者refresh为true时重新生成,登录过期时直接返回最新的评价",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "根据userid获取用户评价",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "是否强制重新生成",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "登录成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.EvaluationResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "409": {
+                        "description": "正在推断,稍后再试",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "503": {
+                        "description": "llm服务暂时不可用",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/getEvaluation/stream": {
+            "get": {
+                "description": "使用Server-Sent Events逐段返回评价,message事件为新生成的片段,done事件表示生成完毕并携带结构化的评价,error事件表示生成失败",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "根据userid流式获取用户评价",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "是否强制重新生成",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "评价片段",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "409": {
+                        "description": "正在推断,稍后再试",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "503": {
+                        "description": "llm服务暂时不可用",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/getEvaluationHistory": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "根据userid获取用户的历史评价",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "分页参数表示这是第几页",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页返回的评价数量",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.EvaluationHistoryResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/getInfo": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "从userid获取用户",
+                "responses": {
+                    "200": {
+                        "description": "登录成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/getNation": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "根据用户 ID 获取用户所在国家",
+                "responses": {
+                    "200": {
+                        "description": "国家获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.NationResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "404": {
+                        "description": "用户未找到",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "409": {
+                        "description": "正在推断,稍后再试",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "503": {
+                        "description": "llm服务暂时不可用",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/getRank": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "根据userid获取用户的score的排行榜",
+                "responses": {
+                    "200": {
+                        "description": "登录成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.Ranking"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/getUserInfo": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "根据userid获取用户详细信息",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户的user_id",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "用户信息获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/search": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "根据国家和领域搜索用户",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "国家，选择性参数",
+                        "name": "nation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "领域，选择性参数",
+                        "name": "domain",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "领域和国籍的最低置信度，默认为0",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "分页参数表示这是第几页",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页返回的用户数量，建议一次返回10个",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "用户搜索成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.SearchResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/syncStatus": {
+            "get": {
+                "description": "state依次为pending,fetching_graph,scoring,inferring_domain,inferring_nation,done,任何阶段最终失败时为failed;error不为空且没有失败时表示正在重试",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "获取自己的关注关系,领域和国籍是否已经同步完成",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SyncStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "404": {
+                        "description": "最近没有同步过",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/syncStatus/ticket": {
+            "post": {
+                "description": "浏览器的websocket不能设置请求头,先通过这个接口获取票据,再通过syncStatus/ws?ticket=建立连接;票据30秒内有效,只能使用一次",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "获取一次性的websocket票据",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.TicketResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/syncStatus/ws": {
+            "get": {
+                "description": "通过syncStatus/ticket获取的一次性票据认证,只接受配置中允许的来源;连接建立后先推送当前状态,每条消息和syncStatus接口的响应相同,同步结束或者出错时服务端关闭连接",
+                "tags": [
+                    "User"
+                ],
+                "summary": "建立websocket连接,推送同步状态的每次变化",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "一次性票据",
+                        "name": "ticket",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "切换为websocket",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Success"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SyncStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "票据无效或者已经使用过",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.Activity": {
+            "type": "object",
+            "properties": {
+                "commits": {
+                    "type": "integer"
+                },
+                "issues": {
+                    "type": "integer"
+                },
+                "pull_requests": {
+                    "type": "integer"
+                },
+                "repos": {
+                    "description": "有活动的仓库数",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Candidate": {
+            "type": "object",
+            "properties": {
+                "match": {
+                    "description": "和要求的重合度,范围0-1",
+                    "type": "number"
+                },
+                "rank": {
+                    "description": "结合重合度和评分的排序依据,范围0-1",
+                    "type": "number"
+                },
+                "reasons": {
+                    "description": "匹配的原因",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "model.ComparedUser": {
+            "type": "object",
+            "properties": {
+                "activity": {
+                    "description": "获取失败时为空",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Activity"
+                        }
+                    ]
+                },
+                "domain_confidence": {
+                    "description": "没有这个领域时为0",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "global_rank": {
+                    "description": "在所有存储的用户中按照评分的排名,从1开始",
+                    "type": "integer"
+                },
+                "language_weight": {
+                    "description": "没有这个语言时为0",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "network_rank": {
+                    "description": "在自己关注关系的排行榜中的排名,从1开始",
+                    "type": "integer"
+                },
+                "network_size": {
+                    "description": "自己关注关系的排行榜的人数",
+                    "type": "integer"
+                },
+                "scores": {
+                    "description": "最新评价的各维度评分,没有评价时为空",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.EvaluationScores"
+                        }
+                    ]
+                },
+                "user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "model.Comparison": {
+            "type": "object",
+            "properties": {
+                "domains": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "summary": {
+                    "description": "llm生成的对比总结",
+                    "type": "string"
+                },
+                "summary_error": {
+                    "description": "生成总结失败时的原因,其他数据仍然可用",
+                    "type": "string"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ComparedUser"
+                    }
+                }
+            }
+        },
+        "model.DiscoveryQuery": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "creator_id": {
+                    "description": "创建这个查询的用户",
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imported": {
+                    "description": "成功导入的用户数",
+                    "type": "integer"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "limit": {
+                    "description": "最多导入的用户数",
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "min_followers": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "query": {
+                    "description": "最终发给github的查询语句",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "导入状态",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "github上匹配的总数",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DiscoveryResult": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "login": {
+                    "type": "string"
+                },
+                "query_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.DiscoveryUser": {
+            "type": "object",
+            "properties": {
+                "result": {
+                    "$ref": "#/definitions/model.DiscoveryResult"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "model.Domain": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "description": "置信度",
+                    "type": "number"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "llm或者fallback",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Evaluation": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "evaluation": {
+                    "type": "string"
+                },
+                "evidence": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EvidenceLink"
+                    }
+                },
+                "fingerprint": {
+                    "description": "生成评价时输入的sha256",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "model_version": {
+                    "type": "string"
+                },
+                "prompt_version": {
+                    "type": "string"
+                },
+                "scores": {
+                    "description": "结构化的评分,方便前端排序,筛选和画图",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.EvaluationScores"
+                        }
+                    ]
+                },
+                "strengths": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "weaknesses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.EvaluationScores": {
+            "type": "object",
+            "properties": {
+                "activity": {
+                    "type": "number"
+                },
+                "breadth": {
+                    "type": "number"
+                },
+                "code_quality": {
+                    "type": "number"
+                },
+                "collaboration": {
+                    "type": "number"
+                },
+                "influence": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.EvidenceLink": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "repo": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Interest": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "description": "language或者topic",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "weight": {
+                    "description": "归一化之后的权重",
+                    "type": "number"
+                }
+            }
+        },
+        "model.Job": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "已经领取的次数",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "locked_until": {
+                    "description": "执行中的任务的租约",
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "description": "json格式的参数",
+                    "type": "string"
+                },
+                "run_at": {
+                    "description": "最早的执行时间,重试时按照退避时间推后",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.JobRequirements": {
+            "type": "object",
+            "properties": {
+                "domains": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "skills": {
+                    "description": "框架,工具等技术栈",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.LLMUsage": {
+            "type": "object",
+            "properties": {
+                "avg_latency": {
+                    "description": "单位毫秒",
+                    "type": "number"
+                },
+                "calls": {
+                    "type": "integer"
+                },
+                "completion_tokens": {
+                    "type": "integer"
+                },
+                "day": {
+                    "type": "string"
+                },
+                "failures": {
+                    "type": "integer"
+                },
+                "prompt_tokens": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Leaderboard": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "user_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.OrgDomain": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "拥有这个领域的成员数",
+                    "type": "integer"
+                },
+                "domain": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.OrgProfile": {
+            "type": "object",
+            "properties": {
+                "domains": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OrgDomain"
+                    }
+                },
+                "member_count": {
+                    "type": "integer"
+                },
+                "org": {
+                    "$ref": "#/definitions/model.Organization"
+                },
+                "top_repos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OrgRepo"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.Leaderboard": {
+        },
+        "model.OrgRepo": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "forks_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "org_id": {
+                    "type": "integer"
+                },
+                "stargazers_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Organization": {
             "type": "object",
             "properties": {
                 "avatar_url": {
+                    "description": "头像的 URL",
+                    "type": "string"
+                },
+                "blog": {
+                    "description": "博客连接",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "组织简介",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "邮箱",
+                    "type": "string"
+                },
+                "followers": {
+                    "description": "粉丝数",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "description": "地区",
                     "type": "string"
                 },
+                "login": {
+                    "description": "组织的登录名",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "组织名称",
+                    "type": "string"
+                },
+                "public_repos": {
+                    "description": "公开的仓库的数量",
+                    "type": "integer"
+                },
                 "score": {
+                    "description": "组织自身仓库的评分",
                     "type": "number"
+                }
+            }
+        },
+        "model.SimilarUser": {
+            "type": "object",
+            "properties": {
+                "similarity": {
+                    "description": "余弦相似度,范围-1到1",
+                    "type": "number"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "model.SyncStatus": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.SyncStep"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
                 },
                 "user_id": {
                     "type": "integer"
+                }
+            }
+        },
+        "model.SyncStep": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
                 },
-                "user_name": {
+                "state": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
@@ -491,6 +2260,10 @@ const docTemplate = `{
                     "description": "评估",
                     "type": "string"
                 },
+                "external_id": {
+                    "description": "用户在代码托管平台上的ID",
+                    "type": "integer"
+                },
                 "followers": {
                     "description": "粉丝数",
                     "type": "integer"
@@ -499,9 +2272,21 @@ const docTemplate = `{
                     "description": "关注数",
                     "type": "integer"
                 },
+                "forge": {
+                    "description": "代码托管平台",
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "inferred_at": {
+                    "description": "后台批量推断的时间,为空表示还没有推断过",
+                    "type": "string"
+                },
+                "last_synced_at": {
+                    "description": "最近一次从平台拉取资料和评分的时间",
+                    "type": "string"
+                },
                 "location": {
                     "description": "地区",
                     "type": "string"
@@ -518,6 +2303,14 @@ const docTemplate = `{
                     "description": "国籍",
                     "type": "string"
                 },
+                "nationality_confidence": {
+                    "description": "国籍的置信度",
+                    "type": "number"
+                },
+                "nationality_source": {
+                    "description": "llm或者fallback",
+                    "type": "string"
+                },
                 "public_repos": {
                     "description": "用户公开的仓库的数量",
                     "type": "integer"
@@ -532,6 +2325,54 @@ const docTemplate = `{
                 }
             }
         },
+        "request.CreateDiscovery": {
+            "type": "object",
+            "properties": {
+                "keyword": {
+                    "description": "搜索关键词,可以为空",
+                    "type": "string"
+                },
+                "language": {
+                    "description": "例如 Go",
+                    "type": "string"
+                },
+                "limit": {
+                    "description": "最多导入的用户数,不超过100",
+                    "type": "integer"
+                },
+                "location": {
+                    "description": "例如 Berlin",
+                    "type": "string"
+                },
+                "min_followers": {
+                    "description": "粉丝数需要大于这个值",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "request.MatchJob": {
+            "type": "object",
+            "properties": {
+                "job_description": {
+                    "type": "string"
+                },
+                "limit": {
+                    "description": "最多返回的候选人数,默认20,不超过100",
+                    "type": "integer"
+                },
+                "min_score": {
+                    "description": "评分需要大于等于这个值",
+                    "type": "number"
+                },
+                "nation": {
+                    "description": "只匹配这个国家的用户,选择性参数",
+                    "type": "string"
+                }
+            }
+        },
         "response.CallBack": {
             "type": "object",
             "properties": {
@@ -540,13 +2381,62 @@ const docTemplate = `{
                 }
             }
         },
+        "response.CompareResp": {
+            "type": "object",
+            "properties": {
+                "comparison": {
+                    "$ref": "#/definitions/model.Comparison"
+                }
+            }
+        },
+        "response.DeadJobsResp": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "description": "按照最后一次失败的时间倒序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Job"
+                    }
+                }
+            }
+        },
+        "response.DiscoveriesResp": {
+            "type": "object",
+            "properties": {
+                "queries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DiscoveryQuery"
+                    }
+                }
+            }
+        },
+        "response.DiscoveryResultsResp": {
+            "type": "object",
+            "properties": {
+                "query": {
+                    "$ref": "#/definitions/model.DiscoveryQuery"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DiscoveryUser"
+                    }
+                }
+            }
+        },
         "response.DomainResp": {
             "type": "object",
             "properties": {
+                "cached": {
+                    "description": "输入没有变化,直接使用了缓存的推断结果",
+                    "type": "boolean"
+                },
                 "domain": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/model.Domain"
                     }
                 }
             }
@@ -554,25 +2444,140 @@ const docTemplate = `{
         "response.Err": {
             "type": "object",
             "properties": {
+                "code": {
+                    "description": "错误码,方便前端区分错误类型",
+                    "type": "string"
+                },
                 "error": {}
             }
         },
+        "response.EvaluationHistoryResp": {
+            "type": "object",
+            "properties": {
+                "evaluations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Evaluation"
+                    }
+                }
+            }
+        },
         "response.EvaluationResp": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "description": "生成评价的时间",
+                    "type": "string"
+                },
                 "evaluation": {
+                    "description": "叙述性的评价",
+                    "type": "string"
+                },
+                "evidence": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EvidenceLink"
+                    }
+                },
+                "model_version": {
+                    "type": "string"
+                },
+                "prompt_version": {
                     "type": "string"
+                },
+                "scores": {
+                    "$ref": "#/definitions/model.EvaluationScores"
+                },
+                "strengths": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "weaknesses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "response.LLMUsageResp": {
+            "type": "object",
+            "properties": {
+                "daily": {
+                    "description": "按天聚合,按日期倒序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LLMUsage"
+                    }
+                },
+                "users": {
+                    "description": "按用户聚合,按token用量倒序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LLMUsage"
+                    }
+                }
+            }
+        },
+        "response.MatchResp": {
+            "type": "object",
+            "properties": {
+                "candidates": {
+                    "description": "按照rank从高到低排序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Candidate"
+                    }
+                },
+                "requirements": {
+                    "description": "从职位描述中提取的要求",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.JobRequirements"
+                        }
+                    ]
                 }
             }
         },
         "response.NationResp": {
             "type": "object",
             "properties": {
+                "cached": {
+                    "description": "输入没有变化,直接使用了缓存的推断结果",
+                    "type": "boolean"
+                },
+                "confidence": {
+                    "type": "number"
+                },
                 "nation": {
                     "type": "string"
                 }
             }
         },
+        "response.OrgMembersResp": {
+            "type": "object",
+            "properties": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.User"
+                    }
+                }
+            }
+        },
+        "response.OrgsResp": {
+            "type": "object",
+            "properties": {
+                "orgs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Organization"
+                    }
+                }
+            }
+        },
         "response.Ranking": {
             "type": "object",
             "properties": {
@@ -595,6 +2600,18 @@ const docTemplate = `{
                 }
             }
         },
+        "response.SimilarResp": {
+            "type": "object",
+            "properties": {
+                "users": {
+                    "description": "按照相似度从高到低排序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SimilarUser"
+                    }
+                }
+            }
+        },
         "response.Success": {
             "type": "object",
             "properties": {
@@ -604,13 +2621,27 @@ const docTemplate = `{
                 }
             }
         },
+        "response.TicketResp": {
+            "type": "object",
+            "properties": {
+                "ticket": {
+                    "type": "string"
+                }
+            }
+        },
         "response.User": {
             "type": "object",
             "properties": {
                 "domain": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/model.Domain"
+                    }
+                },
+                "interests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Interest"
                     }
                 },
                 "user": {
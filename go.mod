@@ -11,6 +11,7 @@ require (
 	github.com/google/wire v0.6.0
 	github.com/spf13/viper v1.19.0
 	github.com/swaggo/swag v1.16.4
+	golang.org/x/net v0.30.0
 	golang.org/x/oauth2 v0.22.0
 	google.golang.org/grpc v1.67.1
 	google.golang.org/protobuf v1.35.1
@@ -70,7 +71,6 @@ require (
 	golang.org/x/arch v0.11.0 // indirect
 	golang.org/x/crypto v0.28.0 // indirect
 	golang.org/x/exp v0.0.0-20241009180824-f66d83c29e7c // indirect
-	golang.org/x/net v0.30.0 // indirect
 	golang.org/x/sys v0.26.0 // indirect
 	golang.org/x/text v0.19.0 // indirect
 	golang.org/x/tools v0.26.0 // indirect
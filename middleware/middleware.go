@@ -1,11 +1,16 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"github.com/GitEval/GitEval-Backend/api/response"
+	"github.com/GitEval/GitEval-Backend/conf"
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
+	"log"
 	"net/http"
+	"slices"
+	"time"
 )
 
 var ProviderSet = wire.NewSet(NewMiddleware, NewJWTClient)
@@ -13,12 +18,18 @@ var ProviderSet = wire.NewSet(NewMiddleware, NewJWTClient)
 type ParTokener interface {
 	ParseToken(tokenString string) (int64, error)
 }
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+}
 type Middleware struct {
-	jwt ParTokener
+	jwt     ParTokener
+	limiter RateLimiter
+	public  *conf.PublicConfig
+	admin   *conf.AdminConfig
 }
 
-func NewMiddleware(jwt ParTokener) *Middleware {
-	return &Middleware{jwt}
+func NewMiddleware(jwt ParTokener, limiter RateLimiter, public *conf.PublicConfig, admin *conf.AdminConfig) *Middleware {
+	return &Middleware{jwt: jwt, limiter: limiter, public: public, admin: admin}
 }
 
 // AuthMiddleware 从请求头中获取认证信息并解析出 user_id
@@ -47,3 +58,37 @@ func (m *Middleware) AuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RateLimitMiddleware 按照客户端ip对公开接口限流
+// 只有来自信任的代理的请求才会使用X-Forwarded-For中的ip
+func (m *Middleware) RateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allow, err := m.limiter.Allow(c, c.ClientIP(), m.public.Limit, time.Duration(m.public.Window)*time.Second)
+		// 限流组件出错时拒绝请求,公开接口会调用github和llm,不能在无法限流时放开
+		if err != nil {
+			log.Println("rate limit failed:", err)
+			c.JSON(http.StatusServiceUnavailable, response.Err{Err: errors.New("rate limiter is unavailable, please try again later.")})
+			c.Abort()
+			return
+		}
+		if !allow {
+			c.JSON(http.StatusTooManyRequests, response.Err{Err: errors.New("too many requests, please try again later.")})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// AdminMiddleware 只允许配置中的管理员访问,需要放在 AuthMiddleware 之后
+func (m *Middleware) AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetInt64("user_id")
+		if !slices.Contains(m.admin.Users, userID) {
+			c.JSON(http.StatusForbidden, response.Err{Err: errors.New("admin only.")})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -2,8 +2,11 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"github.com/GitEval/GitEval-Backend/conf"
 	"github.com/go-redis/redis/v8"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -33,3 +36,202 @@ func (r *RedisClient) CheckBlacklist(jti string) (bool, error) {
 	// 如果 result > 0，说明该 token 被列入黑名单
 	return result > 0, nil
 }
+
+// allowScript 计数和设置窗口期在同一个脚本中完成,不会留下没有过期时间的计数
+var allowScript = redis.NewScript(`
+local cnt = redis.call("incr", KEYS[1])
+if cnt == 1 then
+	redis.call("pexpire", KEYS[1], ARGV[1])
+end
+return cnt`)
+
+// Allow 固定窗口限流,窗口期内 key 的请求数不超过 limit 时返回 true
+func (r *RedisClient) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	cnt, err := allowScript.Run(ctx, r.client, []string{"ratelimit:" + key}, window.Milliseconds()).Int64()
+	if err != nil {
+		return false, err
+	}
+	return cnt <= int64(limit), nil
+}
+
+// SetPublicUser 记录通过公开接口查询过的用户,缓存期内直接从数据库读取
+func (r *RedisClient) SetPublicUser(ctx context.Context, login string, userID int64, expire time.Duration) error {
+	return r.client.Set(ctx, "public:user:"+strings.ToLower(login), userID, expire).Err()
+}
+
+// GetPublicUser 获取缓存的用户ID,不存在时返回 false
+func (r *RedisClient) GetPublicUser(ctx context.Context, login string) (int64, bool, error) {
+	userID, err := r.client.Get(ctx, "public:user:"+strings.ToLower(login)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return userID, true, nil
+}
+
+// SetInference 缓存llm推断的结果,key是请求的指纹
+func (r *RedisClient) SetInference(ctx context.Context, key string, value []byte, expire time.Duration) error {
+	return r.client.Set(ctx, "inference:"+key, value, expire).Err()
+}
+
+// GetInference 获取缓存的推断结果,不存在时返回 false
+func (r *RedisClient) GetInference(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.client.Get(ctx, "inference:"+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// SetSyncStep 记录用户同步的一个阶段的状态,并通知订阅了这个用户的连接
+func (r *RedisClient) SetSyncStep(ctx context.Context, userID int64, step string, value []byte, expire time.Duration) error {
+	key := syncKey(userID)
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, key, step, value)
+	pipe.Expire(ctx, key, expire)
+	pipe.Publish(ctx, key, step)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ResetSync 清除用户上一次同步的状态
+func (r *RedisClient) ResetSync(ctx context.Context, userID int64) error {
+	return r.client.Del(ctx, syncKey(userID)).Err()
+}
+
+// GetSyncSteps 获取用户同步的各个阶段的状态,键是阶段
+func (r *RedisClient) GetSyncSteps(ctx context.Context, userID int64) (map[string]string, error) {
+	return r.client.HGetAll(ctx, syncKey(userID)).Result()
+}
+
+// SubscribeSync 订阅用户同步状态的变化,返回的channel在取消订阅之后关闭
+// 只通知有变化,来不及处理的通知会被合并
+func (r *RedisClient) SubscribeSync(ctx context.Context, userID int64) (<-chan struct{}, func() error, error) {
+	ps := r.client.Subscribe(ctx, syncKey(userID))
+	//等待订阅生效,之后读取的状态不会漏掉变化
+	if _, err := ps.Receive(ctx); err != nil {
+		ps.Close()
+		return nil, nil, err
+	}
+	ch := make(chan struct{}, 1)
+	go func() {
+		defer close(ch)
+		for range ps.Channel() {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return ch, ps.Close, nil
+}
+
+func syncKey(userID int64) string {
+	return "sync:" + strconv.FormatInt(userID, 10)
+}
+
+// activeUsersKey 最近出现在排行榜或者搜索结果中的用户,分数是最近一次出现的时间
+const activeUsersKey = "refresh:active"
+
+// TouchUsers 记录用户在at时出现过
+func (r *RedisClient) TouchUsers(ctx context.Context, ids []int64, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	members := make([]*redis.Z, len(ids))
+	for i, id := range ids {
+		members[i] = &redis.Z{Score: float64(at.Unix()), Member: id}
+	}
+	return r.client.ZAdd(ctx, activeUsersKey, members...).Err()
+}
+
+// GetActiveUsers 获取since之后出现过的用户,最近出现的优先,同时清除更早的记录
+func (r *RedisClient) GetActiveUsers(ctx context.Context, since time.Time, limit int) ([]int64, error) {
+	from := strconv.FormatInt(since.Unix(), 10)
+	if err := r.client.ZRemRangeByScore(ctx, activeUsersKey, "-inf", "("+from).Err(); err != nil {
+		return nil, err
+	}
+	members, err := r.client.ZRevRangeByScore(ctx, activeUsersKey, &redis.ZRangeBy{
+		Min:   from,
+		Max:   "+inf",
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// unlockScript 只有持有锁的一方才能释放锁
+var unlockScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0`)
+
+// renewScript 只有持有锁的一方才能延长锁的过期时间
+var renewScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// TryLock 尝试获取分布式锁,锁已经被持有时返回false,token用于释放锁时确认持有者
+func (r *RedisClient) TryLock(ctx context.Context, key, token string, expire time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, "lock:"+key, token, expire).Result()
+}
+
+// Unlock 释放自己持有的锁,锁已经过期或者被其他人持有时不做任何操作
+func (r *RedisClient) Unlock(ctx context.Context, key, token string) error {
+	return unlockScript.Run(ctx, r.client, []string{"lock:" + key}, token).Err()
+}
+
+// RenewLock 延长自己持有的锁的过期时间,锁已经过期或者被其他人持有时返回false
+func (r *RedisClient) RenewLock(ctx context.Context, key, token string, expire time.Duration) (bool, error) {
+	n, err := renewScript.Run(ctx, r.client, []string{"lock:" + key}, token, expire.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// SetTicket 存储一次性的票据,在expire之后失效
+func (r *RedisClient) SetTicket(ctx context.Context, ticket string, userID int64, expire time.Duration) error {
+	return r.client.Set(ctx, ticketKey(ticket), userID, expire).Err()
+}
+
+// TakeTicket 读取并删除票据,票据不存在或者已经使用过时返回false
+func (r *RedisClient) TakeTicket(ctx context.Context, ticket string) (int64, bool, error) {
+	pipe := r.client.TxPipeline()
+	get := pipe.Get(ctx, ticketKey(ticket))
+	pipe.Del(ctx, ticketKey(ticket))
+	_, err := pipe.Exec(ctx)
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	userID, err := get.Int64()
+	if err != nil {
+		return 0, false, err
+	}
+	return userID, true, nil
+}
+
+func ticketKey(ticket string) string {
+	return "ticket:" + ticket
+}
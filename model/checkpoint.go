@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+const (
+	CheckpointTable = "sync_checkpoints"
+)
+
+// EventCursor 增量获取事件的位置,零值表示还没有处理过事件
+type EventCursor struct {
+	ID int64      `gorm:"column:id" json:"id"` //已经处理过的最新的事件ID
+	At *time.Time `gorm:"column:at" json:"at,omitempty"`
+}
+
+// SyncCheckpoint 用户增量同步的检查点,每个用户只有一个
+// 事件只处理游标之后的部分,仓库最近推送的时间没有变化时直接使用上一次获取的readme和提交数
+type SyncCheckpoint struct {
+	UserID    int64       `gorm:"column:user_id;primaryKey;autoIncrement:false" json:"user_id"`
+	Cursor    EventCursor `gorm:"embedded;embeddedPrefix:last_event_" json:"cursor"`
+	Events    []UserEvent `gorm:"column:events;type:mediumtext;serializer:json" json:"events"` //最近一段时间内按照仓库和日期统计的事件,过期的部分在合并时去掉
+	Repos     []Repo      `gorm:"column:repos;type:mediumtext;serializer:json" json:"repos"`   //上一次获取的仓库
+	UpdatedAt time.Time   `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (c *SyncCheckpoint) TableName() string {
+	return CheckpointTable
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log"
+	"time"
+)
+
+type GormCheckpointDAO struct {
+	data *Data
+}
+
+func NewGormCheckpointDAO(d *Data) *GormCheckpointDAO {
+	return &GormCheckpointDAO{
+		data: d,
+	}
+}
+
+// GetCheckpoint 获取用户的检查点,没有时返回零值
+func (o *GormCheckpointDAO) GetCheckpoint(ctx context.Context, userID int64) (checkpoint SyncCheckpoint, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(CheckpointTable)
+	err = db.Where("user_id = ?", userID).First(&checkpoint).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return SyncCheckpoint{UserID: userID}, nil
+	}
+	if err != nil {
+		log.Println("Error getting sync checkpoint")
+		return SyncCheckpoint{}, err
+	}
+	return checkpoint, nil
+}
+
+// SaveCheckpointRepos 只更新检查点中的仓库
+func (o *GormCheckpointDAO) SaveCheckpointRepos(ctx context.Context, userID int64, repos []Repo) error {
+	db := o.data.DB(ctx).Table(CheckpointTable)
+	err := db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"repos", "updated_at"}),
+	}).Create(&SyncCheckpoint{UserID: userID, Repos: repos, UpdatedAt: time.Now()}).Error
+	if err != nil {
+		log.Println("Error saving checkpoint repos")
+		return err
+	}
+	return nil
+}
+
+// SaveCheckpointEvents 把事件的游标从from移动到to,同时更新累计的事件
+// 游标已经被其他同步移动过时不更新,返回false,避免同一批事件被重复累计
+func (o *GormCheckpointDAO) SaveCheckpointEvents(ctx context.Context, userID int64, from, to EventCursor, events []UserEvent) (bool, error) {
+	db := o.data.DB(ctx).Table(CheckpointTable)
+	//还没有检查点时先创建一个空的,之后统一按照游标更新
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&SyncCheckpoint{UserID: userID, UpdatedAt: time.Now()}).Error
+	if err != nil {
+		log.Println("Error creating checkpoint")
+		return false, err
+	}
+	res := o.data.DB(ctx).Model(&SyncCheckpoint{}).
+		Where("user_id = ? AND last_event_id = ?", userID, from.ID).
+		Select("last_event_id", "last_event_at", "events", "updated_at").
+		Updates(&SyncCheckpoint{Cursor: to, Events: events, UpdatedAt: time.Now()})
+	if res.Error != nil {
+		log.Println("Error saving checkpoint events")
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
@@ -0,0 +1,31 @@
+package model
+
+// Comparison 多个开发者的对比,Domains和Languages是所有人的并集
+// 每个用户的 DomainConfidence 和 LanguageWeight 与之一一对应,方便前端并排展示
+type Comparison struct {
+	Domains      []string       `json:"domains"`
+	Languages    []string       `json:"languages"`
+	Users        []ComparedUser `json:"users"`
+	Summary      string         `json:"summary,omitempty"`       //llm生成的对比总结
+	SummaryError string         `json:"summary_error,omitempty"` //生成总结失败时的原因,其他数据仍然可用
+}
+
+// ComparedUser 对比中一个开发者的数据
+type ComparedUser struct {
+	User             User              `json:"user"`
+	Scores           *EvaluationScores `json:"scores,omitempty"`   //最新评价的各维度评分,没有评价时为空
+	DomainConfidence []float32         `json:"domain_confidence"`  //没有这个领域时为0
+	LanguageWeight   []float64         `json:"language_weight"`    //没有这个语言时为0
+	Activity         *Activity         `json:"activity,omitempty"` //获取失败时为空
+	GlobalRank       int64             `json:"global_rank"`        //在所有存储的用户中按照评分的排名,从1开始
+	NetworkRank      int               `json:"network_rank"`       //在自己关注关系的排行榜中的排名,从1开始
+	NetworkSize      int               `json:"network_size"`       //自己关注关系的排行榜的人数
+}
+
+// Activity 近期的活动,按照平台返回的事件统计
+type Activity struct {
+	Repos        int `json:"repos"` //有活动的仓库数
+	Commits      int `json:"commits"`
+	PullRequests int `json:"pull_requests"`
+	Issues       int `json:"issues"`
+}
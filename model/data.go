@@ -35,11 +35,37 @@ func NewDB(c *conf.DataConfig) *gorm.DB {
 	if err != nil {
 		panic("connect mysql failed")
 	}
-	if err := db.AutoMigrate(&User{}, &FollowingContact{}, &Domain{}); err != nil {
+	if err := db.AutoMigrate(&User{}, &FollowingContact{}, &Domain{}, &Interest{}, &Organization{}, &Membership{}, &OrgRepo{}, &DiscoveryQuery{}, &DiscoveryResult{}, &Evaluation{}, &LLMAudit{}, &Embedding{}, &Job{}, &SyncCheckpoint{}); err != nil {
+		panic(err)
+	}
+	// 区分代码托管平台之前存储的都是github用户
+	err = db.Table(UserTable).Where("forge = ? AND external_id = 0", ForgeGitHub).Update("external_id", gorm.Expr("id")).Error
+	if err != nil {
+		panic(err)
+	}
+	if err := migrateConfidence(db); err != nil {
 		panic(err)
 	}
 	return db
 }
+
+// migrateConfidence 把之前 "Go Backend|(trust:0.82)" 格式的领域和国籍拆分成值和置信度两列
+// mysql的update按照书写顺序赋值,所以最后才修改原来的列
+func migrateConfidence(db *gorm.DB) error {
+	err := db.Exec(`UPDATE domain SET
+		confidence = CAST(SUBSTRING_INDEX(SUBSTRING_INDEX(domain, '(trust:', -1), ')', 1) AS DECIMAL(4,2)),
+		source = IF(domain LIKE '%|(fallback)', ?, ?),
+		domain = SUBSTRING_INDEX(domain, '|', 1)
+		WHERE domain LIKE '%|(trust:%'`, SourceFallback, SourceLLM).Error
+	if err != nil {
+		return err
+	}
+	return db.Exec(`UPDATE users SET
+		nationality_confidence = CAST(SUBSTRING_INDEX(SUBSTRING_INDEX(nationality, '(trust:', -1), ')', 1) AS DECIMAL(4,2)),
+		nationality_source = IF(nationality LIKE '%|(fallback)', ?, ?),
+		nationality = SUBSTRING_INDEX(nationality, '|', 1)
+		WHERE nationality LIKE '%|(trust:%'`, SourceFallback, SourceLLM).Error
+}
 func (d *Data) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
 	return d.Mysql.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 将tx放入到ctx中
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	DiscoveryQueryTable  = "discovery_queries"
+	DiscoveryResultTable = "discovery_results"
+)
+
+const (
+	DiscoveryStatusPending = "pending"
+	DiscoveryStatusRunning = "running"
+	DiscoveryStatusDone    = "done"
+	DiscoveryStatusFailed  = "failed"
+
+	DiscoveryResultImported = "imported"
+	DiscoveryResultFailed   = "failed"
+)
+
+// DiscoveryQuery 保存下来的人才发现查询,例如 "Go developers in Berlin with >100 followers"
+type DiscoveryQuery struct {
+	ID           int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	CreatorID    int64     `gorm:"column:creator_id;index" json:"creator_id"` //创建这个查询的用户
+	Name         string    `gorm:"column:name" json:"name"`
+	Keyword      string    `gorm:"column:keyword" json:"keyword"`
+	Location     string    `gorm:"column:location" json:"location"`
+	Language     string    `gorm:"column:language" json:"language"`
+	MinFollowers int       `gorm:"column:min_followers" json:"min_followers"`
+	Limit        int       `gorm:"column:import_limit" json:"limit"` //最多导入的用户数
+	Query        string    `gorm:"column:query" json:"query"`        //最终发给github的查询语句
+	Status       string    `gorm:"column:status" json:"status"`      //导入状态
+	Error        string    `gorm:"column:error" json:"error"`
+	Total        int       `gorm:"column:total" json:"total"`       //github上匹配的总数
+	Imported     int       `gorm:"column:imported" json:"imported"` //成功导入的用户数
+	CreatedAt    time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// DiscoveryResult 查询匹配到的用户以及导入的结果
+type DiscoveryResult struct {
+	ID      string `gorm:"column:id;primaryKey" json:"id"`
+	QueryID int64  `gorm:"column:query_id;index" json:"query_id"`
+	UserID  int64  `gorm:"column:user_id" json:"user_id"`
+	Login   string `gorm:"column:login" json:"login"`
+	Status  string `gorm:"column:status" json:"status"`
+}
+
+// DiscoveryUser 查询结果以及对应的用户信息
+type DiscoveryUser struct {
+	Result DiscoveryResult `json:"result"`
+	User   User            `json:"user"`
+}
+
+func (q *DiscoveryQuery) TableName() string {
+	return DiscoveryQueryTable
+}
+func (r *DiscoveryResult) TableName() string {
+	return DiscoveryResultTable
+}
+func (r *DiscoveryResult) GenerateID() {
+	r.ID = fmt.Sprintf("%d#%d", r.QueryID, r.UserID)
+}
+func (r *DiscoveryResult) BeforeCreate(tx *gorm.DB) (err error) {
+	r.GenerateID()
+	return nil
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"context"
+	"gorm.io/gorm/clause"
+	"log"
+	"time"
+)
+
+type GormDiscoveryDAO struct {
+	data *Data
+}
+
+func NewGormDiscoveryDAO(data *Data) *GormDiscoveryDAO {
+	return &GormDiscoveryDAO{
+		data: data,
+	}
+}
+
+func (o *GormDiscoveryDAO) CreateQuery(ctx context.Context, query *DiscoveryQuery) error {
+	db := o.data.DB(ctx).Table(DiscoveryQueryTable)
+	if err := db.Create(query).Error; err != nil {
+		log.Println("Error creating discovery query")
+		return err
+	}
+	return nil
+}
+
+func (o *GormDiscoveryDAO) SaveQuery(ctx context.Context, query *DiscoveryQuery) error {
+	db := o.data.DB(ctx).Table(DiscoveryQueryTable)
+	if err := db.Save(query).Error; err != nil {
+		log.Println("Error saving discovery query")
+		return err
+	}
+	return nil
+}
+
+// StartQuery 把没有在等待或者运行中的查询标记为等待运行,查询已经在等待或者运行中时返回false
+// 通过条件更新判断,同时发起的多次运行只有一次会成功
+func (o *GormDiscoveryDAO) StartQuery(ctx context.Context, id int64) (bool, error) {
+	db := o.data.DB(ctx).Table(DiscoveryQueryTable)
+	res := db.Where("id = ? AND status NOT IN ?", id, []string{DiscoveryStatusPending, DiscoveryStatusRunning}).
+		Updates(map[string]any{"status": DiscoveryStatusPending, "error": "", "updated_at": time.Now()})
+	if res.Error != nil {
+		log.Println("Error starting discovery query")
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (o *GormDiscoveryDAO) GetQueryByID(ctx context.Context, id int64) (query DiscoveryQuery, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(DiscoveryQueryTable)
+	err = db.Where("id = ?", id).First(&query).Error
+	if err != nil {
+		log.Println("Error getting discovery query by ID")
+		return DiscoveryQuery{}, err
+	}
+	return query, nil
+}
+
+func (o *GormDiscoveryDAO) GetQueriesByCreator(ctx context.Context, creatorID int64) (queries []DiscoveryQuery, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(DiscoveryQueryTable)
+	err = db.Where("creator_id = ?", creatorID).Order("id DESC").Find(&queries).Error
+	if err != nil {
+		log.Println("Error getting discovery queries")
+		return nil, err
+	}
+	return queries, nil
+}
+
+// SaveResults 重复运行同一个查询时更新导入状态
+func (o *GormDiscoveryDAO) SaveResults(ctx context.Context, results []DiscoveryResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	db := o.data.DB(ctx).Table(DiscoveryResultTable)
+	err := db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"status"}),
+	}).Create(&results).Error
+	if err != nil {
+		log.Println("Error saving discovery results")
+		return err
+	}
+	return nil
+}
+
+// GetResults 按照用户分数从高到低分页返回查询的结果,导入失败的用户信息为空
+func (o *GormDiscoveryDAO) GetResults(ctx context.Context, queryID int64, page int, pageSize int) ([]DiscoveryUser, error) {
+	var results []DiscoveryResult
+	db := o.data.Mysql.WithContext(ctx).Table(DiscoveryResultTable)
+	err := db.Select("discovery_results.*").
+		Joins("LEFT JOIN users ON users.id = discovery_results.user_id").
+		Where("discovery_results.query_id = ?", queryID).
+		Order("users.score DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&results).Error
+	if err != nil {
+		log.Println("Error getting discovery results")
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(results))
+	for _, v := range results {
+		ids = append(ids, v.UserID)
+	}
+	var users []User
+	if len(ids) > 0 {
+		err = o.data.Mysql.WithContext(ctx).Table(UserTable).Where("id IN ?", ids).Find(&users).Error
+		if err != nil {
+			log.Println("Error getting discovery users")
+			return nil, err
+		}
+	}
+	mp := make(map[int64]User, len(users))
+	for _, u := range users {
+		mp[u.ID] = u
+	}
+
+	resp := make([]DiscoveryUser, 0, len(results))
+	for _, v := range results {
+		resp = append(resp, DiscoveryUser{Result: v, User: mp[v.UserID]})
+	}
+	return resp, nil
+}
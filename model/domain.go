@@ -5,8 +5,10 @@ const (
 )
 
 type Domain struct {
-	UserID int64  `gorm:"index;column:user_id" json:"user_id"`
-	Domain string `gorm:"index;column:domain" json:"domain"`
+	UserID     int64   `gorm:"index;column:user_id" json:"user_id"`
+	Domain     string  `gorm:"column:domain;index:idx_domain_confidence,priority:1" json:"domain"`
+	Confidence float32 `gorm:"column:confidence;index:idx_domain_confidence,priority:2" json:"confidence"` //置信度
+	Source     string  `gorm:"column:source" json:"source"`                                                //llm或者fallback
 }
 
 func (d *Domain) TableName() string {
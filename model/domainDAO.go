@@ -15,15 +15,16 @@ func NewGormDomainDAO(d *Data) *GormDomainDAO {
 	}
 }
 
-func (o *GormDomainDAO) GetDomainById(ctx context.Context, id int64) ([]string, error) {
-	var lang []string
+// GetDomainById 按置信度从大到小返回用户的领域
+func (o *GormDomainDAO) GetDomainById(ctx context.Context, id int64) ([]Domain, error) {
+	var domains []Domain
 	db := o.data.Mysql.WithContext(ctx).Table(DomainTable)
-	err := db.Where("user_id = ?", id).Select("domain").Find(&lang).Error
+	err := db.Where("user_id = ?", id).Order("confidence DESC").Find(&domains).Error
 	if err != nil {
 		log.Println("Error getting domain by ID")
 		return nil, err
 	}
-	return lang, nil
+	return domains, nil
 }
 
 func (o *GormDomainDAO) Create(ctx context.Context, domain []Domain) error {
@@ -48,3 +49,30 @@ func (o *GormDomainDAO) Delete(ctx context.Context, id int64) error {
 	}
 	return nil
 }
+
+// GetDomainsByUserIds 批量获取用户的领域
+func (o *GormDomainDAO) GetDomainsByUserIds(ctx context.Context, ids []int64) ([]Domain, error) {
+	var domains []Domain
+	if len(ids) == 0 {
+		return domains, nil
+	}
+	db := o.data.Mysql.WithContext(ctx).Table(DomainTable)
+	err := db.Where("user_id IN ?", ids).Order("confidence DESC").Find(&domains).Error
+	if err != nil {
+		log.Println("Error getting domains by IDs")
+		return nil, err
+	}
+	return domains, nil
+}
+
+// GetDomainNames 获取系统中最常见的limit个领域
+func (o *GormDomainDAO) GetDomainNames(ctx context.Context, limit int) ([]string, error) {
+	var names []string
+	db := o.data.Mysql.WithContext(ctx).Table(DomainTable)
+	err := db.Group("domain").Order("COUNT(*) DESC").Limit(limit).Pluck("domain", &names).Error
+	if err != nil {
+		log.Println("Error getting domain names")
+		return nil, err
+	}
+	return names, nil
+}
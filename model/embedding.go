@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+const (
+	EmbeddingTable = "embeddings"
+)
+
+// Embedding 用户画像的向量,每个用户只保留最新的一个
+// 画像的指纹不变时不再重复计算
+type Embedding struct {
+	UserID      int64     `gorm:"column:user_id;primaryKey;autoIncrement:false" json:"user_id"`
+	Model       string    `gorm:"column:model;size:128" json:"model"`            //计算向量使用的模型,不同模型的向量不能比较
+	Fingerprint string    `gorm:"column:fingerprint;size:64" json:"fingerprint"` //画像文本的sha256
+	Vector      []float32 `gorm:"column:vector;type:mediumtext;serializer:json" json:"-"`
+	UpdatedAt   time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// SimilarUser 相似度搜索的结果
+type SimilarUser struct {
+	User       User    `json:"user"`
+	Similarity float32 `json:"similarity"` //余弦相似度,范围-1到1
+}
+
+func (e *Embedding) TableName() string {
+	return EmbeddingTable
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"context"
+	"gorm.io/gorm/clause"
+	"log"
+)
+
+type GormEmbeddingDAO struct {
+	data *Data
+}
+
+func NewGormEmbeddingDAO(d *Data) *GormEmbeddingDAO {
+	return &GormEmbeddingDAO{
+		data: d,
+	}
+}
+
+// SaveEmbedding 写入或者替换用户的向量
+func (o *GormEmbeddingDAO) SaveEmbedding(ctx context.Context, embedding *Embedding) error {
+	db := o.data.DB(ctx).Table(EmbeddingTable)
+	err := db.Clauses(clause.OnConflict{UpdateAll: true}).Create(embedding).Error
+	if err != nil {
+		log.Println("Error saving embedding")
+		return err
+	}
+	return nil
+}
+
+// GetEmbedding 获取用户的向量,没有时返回 gorm.ErrRecordNotFound
+func (o *GormEmbeddingDAO) GetEmbedding(ctx context.Context, userID int64) (embedding Embedding, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(EmbeddingTable)
+	err = db.Where("user_id = ?", userID).First(&embedding).Error
+	return embedding, err
+}
+
+// GetFingerprints 获取用户画像的指纹,没有向量的用户不在结果中
+func (o *GormEmbeddingDAO) GetFingerprints(ctx context.Context, userIDs []int64) (map[int64]string, error) {
+	var embeddings []Embedding
+	db := o.data.Mysql.WithContext(ctx).Table(EmbeddingTable)
+	err := db.Select("user_id", "fingerprint").Where("user_id IN ?", userIDs).Find(&embeddings).Error
+	if err != nil {
+		log.Println("Error getting embedding fingerprints")
+		return nil, err
+	}
+	resp := make(map[int64]string, len(embeddings))
+	for _, e := range embeddings {
+		resp[e.UserID] = e.Fingerprint
+	}
+	return resp, nil
+}
+
+// ListEmbeddings 按照user_id分页获取向量,用于启动时加载索引
+func (o *GormEmbeddingDAO) ListEmbeddings(ctx context.Context, afterUserID int64, limit int) (embeddings []Embedding, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(EmbeddingTable)
+	err = db.Where("user_id > ?", afterUserID).Order("user_id").Limit(limit).Find(&embeddings).Error
+	if err != nil {
+		log.Println("Error listing embeddings")
+		return nil, err
+	}
+	return embeddings, nil
+}
+
+// GetUsersMissingEmbedding 获取已经有领域但是还没有向量的用户,分数高的优先
+func (o *GormEmbeddingDAO) GetUsersMissingEmbedding(ctx context.Context, limit int) (users []User, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(UserTable)
+	err = db.Where("EXISTS (SELECT 1 FROM domain WHERE domain.user_id = users.id)").
+		Where("NOT EXISTS (SELECT 1 FROM embeddings WHERE embeddings.user_id = users.id)").
+		Order("score DESC").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		log.Println("Error getting users missing embedding")
+		return nil, err
+	}
+	return users, nil
+}
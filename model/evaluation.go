@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+const (
+	EvaluationTable = "evaluations"
+)
+
+// Evaluation 每次生成的评价都会保存下来
+// 输入的指纹不变时直接使用最新的评价,不再重复调用llm
+type Evaluation struct {
+	ID            int64            `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID        int64            `gorm:"column:user_id;index:idx_user_evaluation" json:"user_id"`
+	Evaluation    string           `gorm:"column:evaluation;type:text" json:"evaluation"`
+	Fingerprint   string           `gorm:"column:fingerprint;size:64" json:"fingerprint"` //生成评价时输入的sha256
+	ModelVersion  string           `gorm:"column:model_version" json:"model_version"`
+	PromptVersion string           `gorm:"column:prompt_version" json:"prompt_version"`
+	Scores        EvaluationScores `gorm:"embedded;embeddedPrefix:score_" json:"scores"` //结构化的评分,方便前端排序,筛选和画图
+	Strengths     []string         `gorm:"column:strengths;type:text;serializer:json" json:"strengths"`
+	Weaknesses    []string         `gorm:"column:weaknesses;type:text;serializer:json" json:"weaknesses"`
+	Evidence      []EvidenceLink   `gorm:"column:evidence;type:text;serializer:json" json:"evidence"`
+	CreatedAt     time.Time        `gorm:"column:created_at;index:idx_user_evaluation" json:"created_at"`
+}
+
+// EvaluationScores 各个维度的评分,范围0-100
+type EvaluationScores struct {
+	CodeQuality   float32 `gorm:"column:code_quality" json:"code_quality"`
+	Activity      float32 `gorm:"column:activity" json:"activity"`
+	Collaboration float32 `gorm:"column:collaboration" json:"collaboration"`
+	Influence     float32 `gorm:"column:influence" json:"influence"`
+	Breadth       float32 `gorm:"column:breadth" json:"breadth"`
+}
+
+// EvidenceLink 评价所依据的仓库
+type EvidenceLink struct {
+	Repo   string `json:"repo"`
+	URL    string `json:"url"`
+	Reason string `json:"reason"`
+}
+
+func (e *Evaluation) TableName() string {
+	return EvaluationTable
+}
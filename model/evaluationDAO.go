@@ -0,0 +1,45 @@
+package model
+
+import (
+	"context"
+	"log"
+)
+
+type GormEvaluationDAO struct {
+	data *Data
+}
+
+func NewGormEvaluationDAO(d *Data) *GormEvaluationDAO {
+	return &GormEvaluationDAO{
+		data: d,
+	}
+}
+
+func (o *GormEvaluationDAO) Create(ctx context.Context, evaluation *Evaluation) error {
+	db := o.data.DB(ctx).Table(EvaluationTable)
+	err := db.Create(evaluation).Error
+	if err != nil {
+		log.Println("Error creating evaluation")
+		return err
+	}
+	return nil
+}
+
+// GetLatest 获取用户最新的评价,没有时返回 gorm.ErrRecordNotFound
+func (o *GormEvaluationDAO) GetLatest(ctx context.Context, userID int64) (evaluation Evaluation, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(EvaluationTable)
+	err = db.Where("user_id = ?", userID).Order("id DESC").First(&evaluation).Error
+	return evaluation, err
+}
+
+// GetHistory 按时间倒序获取用户的历史评价
+func (o *GormEvaluationDAO) GetHistory(ctx context.Context, userID int64, page, pageSize int) (evaluations []Evaluation, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(EvaluationTable)
+	err = db.Where("user_id = ?", userID).Order("id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).Find(&evaluations).Error
+	if err != nil {
+		log.Println("Error getting evaluation history")
+		return nil, err
+	}
+	return evaluations, nil
+}
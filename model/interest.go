@@ -0,0 +1,22 @@
+package model
+
+const (
+	InterestTable = "interest"
+)
+
+const (
+	InterestKindLanguage = "language"
+	InterestKindTopic    = "topic"
+)
+
+// Interest 由用户star的仓库聚合出的兴趣向量中的一个分量
+type Interest struct {
+	UserID int64   `gorm:"index;column:user_id" json:"user_id"`
+	Kind   string  `gorm:"column:kind" json:"kind"` //language或者topic
+	Name   string  `gorm:"index;column:name" json:"name"`
+	Weight float64 `gorm:"column:weight" json:"weight"` //归一化之后的权重
+}
+
+func (i *Interest) TableName() string {
+	return InterestTable
+}
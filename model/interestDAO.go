@@ -0,0 +1,66 @@
+package model
+
+import (
+	"context"
+	"log"
+)
+
+type GormInterestDAO struct {
+	data *Data
+}
+
+func NewGormInterestDAO(d *Data) *GormInterestDAO {
+	return &GormInterestDAO{
+		data: d,
+	}
+}
+
+// GetInterestsById 按权重从大到小返回用户的兴趣
+func (o *GormInterestDAO) GetInterestsById(ctx context.Context, id int64) ([]Interest, error) {
+	var interests []Interest
+	db := o.data.Mysql.WithContext(ctx).Table(InterestTable)
+	err := db.Where("user_id = ?", id).Order("weight DESC").Find(&interests).Error
+	if err != nil {
+		log.Println("Error getting interests by ID")
+		return nil, err
+	}
+	return interests, nil
+}
+
+func (o *GormInterestDAO) Create(ctx context.Context, interests []Interest) error {
+	if len(interests) == 0 {
+		return nil
+	}
+	db := o.data.DB(ctx).Table(InterestTable)
+	err := db.Create(&interests).Error
+	if err != nil {
+		log.Println("Error creating interests")
+		return err
+	}
+	return nil
+}
+
+func (o *GormInterestDAO) Delete(ctx context.Context, id int64) error {
+	db := o.data.DB(ctx).Table(InterestTable)
+	err := db.Where("user_id = ?", id).Delete(Interest{}).Error
+	if err != nil {
+		log.Println("Error deleting interests")
+		return err
+	}
+	return nil
+}
+
+// GetInterestsByUserIds 批量获取用户的兴趣
+func (o *GormInterestDAO) GetInterestsByUserIds(ctx context.Context, ids []int64) ([]Interest, error) {
+	var interests []Interest
+	if len(ids) == 0 {
+		return interests, nil
+	}
+	db := o.data.Mysql.WithContext(ctx).Table(InterestTable)
+	err := db.Where("user_id IN ?", ids).Order("weight DESC").Find(&interests).Error
+	if err != nil {
+		log.Println("Error getting interests by IDs")
+		return nil, err
+	}
+	return interests, nil
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+const (
+	JobTable = "jobs"
+)
+
+// 任务的状态
+const (
+	JobPending = "pending"
+	JobRunning = "running"
+	JobDone    = "done"
+	// JobDead 超过最大重试次数或者遇到不可重试的错误,需要人工处理
+	JobDead = "dead"
+)
+
+// Job 持久化的后台任务,进程重启之后仍然会执行
+// 正在执行的任务超过租约没有完成时,说明执行的进程已经退出,会被重新领取
+type Job struct {
+	ID      int64  `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Type    string `gorm:"column:type;size:32" json:"type"`
+	Payload string `gorm:"column:payload;type:text" json:"payload"` //json格式的参数
+	//任务的去重键,相同的任务在等待,执行和重试时只保留一个,完成或者放入死信之后清空
+	DedupKey    *string    `gorm:"column:dedup_key;size:191;uniqueIndex" json:"-"`
+	Status      string     `gorm:"column:status;size:16;index:idx_job_claim,priority:1" json:"status"`
+	Attempts    int        `gorm:"column:attempts" json:"attempts"` //已经领取的次数
+	MaxAttempts int        `gorm:"column:max_attempts" json:"max_attempts"`
+	RunAt       time.Time  `gorm:"column:run_at;index:idx_job_claim,priority:2" json:"run_at"` //最早的执行时间,重试时按照退避时间推后
+	LockedUntil *time.Time `gorm:"column:locked_until" json:"locked_until,omitempty"`          //执行中的任务的租约
+	LastError   string     `gorm:"column:last_error;type:text" json:"last_error"`
+	CreatedAt   time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (j *Job) TableName() string {
+	return JobTable
+}
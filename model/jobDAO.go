@@ -0,0 +1,143 @@
+package model
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log"
+	"time"
+)
+
+type GormJobDAO struct {
+	data *Data
+}
+
+func NewGormJobDAO(d *Data) *GormJobDAO {
+	return &GormJobDAO{
+		data: d,
+	}
+}
+
+// CreateJob 存储一个任务,已经有相同去重键的任务在等待执行时不做任何操作
+func (o *GormJobDAO) CreateJob(ctx context.Context, job *Job) error {
+	db := o.data.DB(ctx).Table(JobTable)
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(job).Error
+	if err != nil {
+		log.Println("Error creating job:", err)
+		return err
+	}
+	return nil
+}
+
+// ClaimJob 领取一个到期的任务,同时设置租约并增加领取次数,没有任务时返回false
+// 使用 SKIP LOCKED 让多个实例可以同时领取不同的任务,需要mysql 8.0
+// 租约到期时已经用完领取次数的任务说明每次执行都没有结束(例如进程崩溃),直接放入死信
+func (o *GormJobDAO) ClaimJob(ctx context.Context, now time.Time, lease time.Duration) (job Job, ok bool, err error) {
+	err = o.data.Mysql.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table(JobTable).
+			Where("status = ? AND locked_until < ? AND attempts >= max_attempts", JobRunning, now).
+			Updates(map[string]any{
+				"status":       JobDead,
+				"dedup_key":    nil,
+				"locked_until": nil,
+				"last_error":   "lease expired on the last attempt",
+				"updated_at":   now,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		var jobs []Job
+		err = tx.Table(JobTable).Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?)", JobPending, now, JobRunning, now).
+			Order("run_at").Limit(1).
+			Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		job, ok = jobs[0], true
+		lockedUntil := now.Add(lease)
+		job.Status, job.Attempts, job.LockedUntil = JobRunning, job.Attempts+1, &lockedUntil
+		return tx.Table(JobTable).Where("id = ?", job.ID).Updates(map[string]any{
+			"status":       job.Status,
+			"attempts":     job.Attempts,
+			"locked_until": job.LockedUntil,
+			"updated_at":   now,
+		}).Error
+	})
+	if err != nil {
+		log.Println("Error claiming job:", err)
+		return Job{}, false, err
+	}
+	return job, ok, nil
+}
+
+// CompleteJob 任务执行成功,清空去重键之后可以再提交相同的任务
+func (o *GormJobDAO) CompleteJob(ctx context.Context, id int64) error {
+	return o.updateJob(ctx, id, map[string]any{"status": JobDone, "dedup_key": nil, "locked_until": nil, "last_error": ""})
+}
+
+// RetryJob 任务失败之后在runAt重新执行
+func (o *GormJobDAO) RetryJob(ctx context.Context, id int64, runAt time.Time, lastError string) error {
+	return o.updateJob(ctx, id, map[string]any{"status": JobPending, "run_at": runAt, "locked_until": nil, "last_error": lastError})
+}
+
+// DeferJob 任务暂时不能执行,在runAt重新执行,这次领取不计入领取次数
+func (o *GormJobDAO) DeferJob(ctx context.Context, id int64, runAt time.Time) error {
+	return o.updateJob(ctx, id, map[string]any{"status": JobPending, "run_at": runAt, "locked_until": nil, "attempts": gorm.Expr("GREATEST(attempts - 1, 0)")})
+}
+
+// DeleteDoneJobs 删除在before之前完成的任务,返回删除的数量
+func (o *GormJobDAO) DeleteDoneJobs(ctx context.Context, before time.Time) (int64, error) {
+	res := o.data.Mysql.WithContext(ctx).Table(JobTable).Where("status = ? AND updated_at < ?", JobDone, before).Delete(&Job{})
+	if res.Error != nil {
+		log.Println("Error deleting done jobs:", res.Error)
+		return 0, res.Error
+	}
+	return res.RowsAffected, nil
+}
+
+// DeadJob 把任务放入死信,不再自动执行,清空去重键之后可以再提交相同的任务
+func (o *GormJobDAO) DeadJob(ctx context.Context, id int64, lastError string) error {
+	return o.updateJob(ctx, id, map[string]any{"status": JobDead, "dedup_key": nil, "locked_until": nil, "last_error": lastError})
+}
+
+// GetDeadJobs 按照时间倒序获取死信中的任务
+func (o *GormJobDAO) GetDeadJobs(ctx context.Context, page, pageSize int) (jobs []Job, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(JobTable)
+	err = db.Where("status = ?", JobDead).Order("updated_at DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).Find(&jobs).Error
+	if err != nil {
+		log.Println("Error getting dead jobs")
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// RequeueJob 把死信中的任务重新放回队列,领取次数清零,任务不在死信中时返回false
+func (o *GormJobDAO) RequeueJob(ctx context.Context, id int64, now time.Time) (bool, error) {
+	db := o.data.Mysql.WithContext(ctx).Table(JobTable)
+	res := db.Where("id = ? AND status = ?", id, JobDead).Updates(map[string]any{
+		"status":     JobPending,
+		"attempts":   0,
+		"run_at":     now,
+		"updated_at": now,
+	})
+	if res.Error != nil {
+		log.Println("Error requeuing job")
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (o *GormJobDAO) updateJob(ctx context.Context, id int64, values map[string]any) error {
+	values["updated_at"] = time.Now()
+	db := o.data.Mysql.WithContext(ctx).Table(JobTable)
+	err := db.Where("id = ?", id).Updates(values).Error
+	if err != nil {
+		log.Println("Error updating job:", err)
+		return err
+	}
+	return nil
+}
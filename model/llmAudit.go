@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+const (
+	LLMAuditTable = "llm_audits"
+)
+
+// LLMAudit 每次调用llm的审计记录,用于排查问题和统计成本
+type LLMAudit struct {
+	ID               int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID           int64     `gorm:"column:user_id;index:idx_user_audit" json:"user_id"` //不是由用户触发的调用为0
+	Method           string    `gorm:"column:method;size:32" json:"method"`
+	Backend          string    `gorm:"column:backend;size:16" json:"backend"`
+	InputSize        int       `gorm:"column:input_size" json:"input_size"` //请求序列化之后的字节数
+	Latency          int64     `gorm:"column:latency" json:"latency"`       //单位毫秒,包括重试的时间
+	Status           string    `gorm:"column:status;size:32" json:"status"` //grpc的状态码
+	Error            string    `gorm:"column:error;type:text" json:"error"`
+	Response         string    `gorm:"column:response;type:mediumtext" json:"response"`
+	PromptTokens     int32     `gorm:"column:prompt_tokens" json:"prompt_tokens"`
+	CompletionTokens int32     `gorm:"column:completion_tokens" json:"completion_tokens"`
+	CreatedAt        time.Time `gorm:"column:created_at;index:idx_audit_created;index:idx_user_audit" json:"created_at"`
+}
+
+// LLMUsage 按天或者按用户聚合的用量
+type LLMUsage struct {
+	Day              string  `gorm:"column:day" json:"day,omitempty"`
+	UserID           int64   `gorm:"column:user_id" json:"user_id,omitempty"`
+	Calls            int64   `gorm:"column:calls" json:"calls"`
+	Failures         int64   `gorm:"column:failures" json:"failures"`
+	PromptTokens     int64   `gorm:"column:prompt_tokens" json:"prompt_tokens"`
+	CompletionTokens int64   `gorm:"column:completion_tokens" json:"completion_tokens"`
+	AvgLatency       float64 `gorm:"column:avg_latency" json:"avg_latency"` //单位毫秒
+}
+
+func (a *LLMAudit) TableName() string {
+	return LLMAuditTable
+}
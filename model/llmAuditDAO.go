@@ -0,0 +1,60 @@
+package model
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// 聚合时统计的字段
+const usageColumns = "COUNT(*) AS calls, SUM(status <> 'OK') AS failures, " +
+	"SUM(prompt_tokens) AS prompt_tokens, SUM(completion_tokens) AS completion_tokens, AVG(latency) AS avg_latency"
+
+type GormLLMAuditDAO struct {
+	data *Data
+}
+
+func NewGormLLMAuditDAO(d *Data) *GormLLMAuditDAO {
+	return &GormLLMAuditDAO{
+		data: d,
+	}
+}
+
+func (o *GormLLMAuditDAO) CreateLLMAudit(ctx context.Context, audit *LLMAudit) error {
+	db := o.data.Mysql.WithContext(ctx).Table(LLMAuditTable)
+	err := db.Create(audit).Error
+	if err != nil {
+		log.Println("Error creating llm audit:", err)
+		return err
+	}
+	return nil
+}
+
+// GetDailyUsage 按天聚合since之后的用量,按日期倒序
+func (o *GormLLMAuditDAO) GetDailyUsage(ctx context.Context, since time.Time) (usages []LLMUsage, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(LLMAuditTable)
+	err = db.Select("DATE_FORMAT(created_at, '%Y-%m-%d') AS day, "+usageColumns).
+		Where("created_at >= ?", since).
+		Group("day").Order("day DESC").
+		Scan(&usages).Error
+	if err != nil {
+		log.Println("Error getting daily llm usage")
+		return nil, err
+	}
+	return usages, nil
+}
+
+// GetUserUsage 按用户聚合since之后的用量,按消耗的token倒序
+func (o *GormLLMAuditDAO) GetUserUsage(ctx context.Context, since time.Time, page, pageSize int) (usages []LLMUsage, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(LLMAuditTable)
+	err = db.Select("user_id, "+usageColumns).
+		Where("created_at >= ?", since).
+		Group("user_id").Order("SUM(prompt_tokens) + SUM(completion_tokens) DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Scan(&usages).Error
+	if err != nil {
+		log.Println("Error getting llm usage by user")
+		return nil, err
+	}
+	return usages, nil
+}
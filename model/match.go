@@ -0,0 +1,16 @@
+package model
+
+// JobRequirements 从职位描述中提取的要求
+type JobRequirements struct {
+	Domains   []string `json:"domains"`
+	Languages []string `json:"languages"`
+	Skills    []string `json:"skills"` //框架,工具等技术栈
+}
+
+// Candidate 和职位匹配的候选人
+type Candidate struct {
+	User    User     `json:"user"`
+	Match   float64  `json:"match"`   //和要求的重合度,范围0-1
+	Rank    float64  `json:"rank"`    //结合重合度和评分的排序依据,范围0-1
+	Reasons []string `json:"reasons"` //匹配的原因
+}
@@ -8,4 +8,12 @@ var ProviderSet = wire.NewSet(
 	NewGormUserDAO,
 	NewGormDomainDAO,
 	NewGormContactDAO,
+	NewGormInterestDAO,
+	NewGormOrganizationDAO,
+	NewGormDiscoveryDAO,
+	NewGormEvaluationDAO,
+	NewGormLLMAuditDAO,
+	NewGormEmbeddingDAO,
+	NewGormJobDAO,
+	NewGormCheckpointDAO,
 )
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"gorm.io/gorm"
+)
+
+const (
+	OrganizationTable = "organizations"
+	MembershipTable   = "memberships"
+	OrgRepoTable      = "org_repos"
+)
+
+// Organization GitHub上的组织
+type Organization struct {
+	ID          int64   `gorm:"column:id;primaryKey" json:"id"`
+	Login       string  `gorm:"column:login;index" json:"login"`         //组织的登录名
+	Name        string  `gorm:"column:name" json:"name"`                 //组织名称
+	Description string  `gorm:"column:description" json:"description"`   //组织简介
+	Blog        string  `gorm:"column:blog" json:"blog"`                 //博客连接
+	Location    string  `gorm:"column:location" json:"location"`         //地区
+	Email       string  `gorm:"column:email" json:"email"`               //邮箱
+	AvatarURL   string  `gorm:"column:avatar_url" json:"avatar_url"`     //头像的 URL
+	PublicRepos int     `gorm:"column:public_repos" json:"public_repos"` //公开的仓库的数量
+	Followers   int     `gorm:"column:followers" json:"followers"`       //粉丝数
+	Score       float64 `gorm:"column:score;index" json:"score"`         //组织自身仓库的评分
+}
+
+// Membership user 是 org 的成员
+type Membership struct {
+	ID     string `gorm:"column:id;primaryKey" json:"id"`
+	OrgID  int64  `gorm:"column:org_id;index:idx_membership" json:"org_id"`
+	UserID int64  `gorm:"column:user_id;index:idx_membership" json:"user_id"`
+}
+
+// OrgRepo 组织自己的仓库,只保存star最多的一部分
+type OrgRepo struct {
+	ID              int64  `gorm:"column:id;primaryKey" json:"id"`
+	OrgID           int64  `gorm:"column:org_id;index" json:"org_id"`
+	Name            string `gorm:"column:name" json:"name"`
+	Description     string `gorm:"column:description" json:"description"`
+	Language        string `gorm:"column:language" json:"language"`
+	StargazersCount int    `gorm:"column:stargazers_count" json:"stargazers_count"`
+	ForksCount      int    `gorm:"column:forks_count" json:"forks_count"`
+}
+
+// OrgDomain 组织成员的领域聚合结果
+type OrgDomain struct {
+	Domain string `json:"domain"`
+	Count  int64  `json:"count"` //拥有这个领域的成员数
+}
+
+// OrgProfile 组织的整体画像
+type OrgProfile struct {
+	Org         Organization `json:"org"`
+	MemberCount int64        `json:"member_count"`
+	TopRepos    []OrgRepo    `json:"top_repos"`
+	Domains     []OrgDomain  `json:"domains"`
+}
+
+func (o *Organization) TableName() string {
+	return OrganizationTable
+}
+func (m *Membership) TableName() string {
+	return MembershipTable
+}
+func (r *OrgRepo) TableName() string {
+	return OrgRepoTable
+}
+func (m *Membership) GenerateID() {
+	m.ID = fmt.Sprintf("%d@%d", m.UserID, m.OrgID)
+}
+func (m *Membership) BeforeCreate(tx *gorm.DB) (err error) {
+	m.GenerateID()
+	return nil
+}
+
+func TransformOrganization(org *github.Organization) Organization {
+	return Organization{
+		ID:          org.GetID(),
+		Login:       org.GetLogin(),
+		Name:        org.GetName(),
+		Description: org.GetDescription(),
+		Blog:        org.GetBlog(),
+		Location:    org.GetLocation(),
+		Email:       org.GetEmail(),
+		AvatarURL:   org.GetAvatarURL(),
+		PublicRepos: org.GetPublicRepos(),
+		Followers:   org.GetFollowers(),
+	}
+}
+
+func TransformOrgRepo(orgID int64, repo *github.Repository) OrgRepo {
+	return OrgRepo{
+		ID:              repo.GetID(),
+		OrgID:           orgID,
+		Name:            repo.GetFullName(),
+		Description:     repo.GetDescription(),
+		Language:        repo.GetLanguage(),
+		StargazersCount: repo.GetStargazersCount(),
+		ForksCount:      repo.GetForksCount(),
+	}
+}
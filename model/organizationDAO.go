@@ -0,0 +1,145 @@
+package model
+
+import (
+	"context"
+	"gorm.io/gorm/clause"
+	"log"
+)
+
+type GormOrganizationDAO struct {
+	data *Data
+}
+
+func NewGormOrganizationDAO(data *Data) *GormOrganizationDAO {
+	return &GormOrganizationDAO{
+		data: data,
+	}
+}
+
+func (o *GormOrganizationDAO) SaveOrganizations(ctx context.Context, orgs []Organization) error {
+	if len(orgs) == 0 {
+		return nil
+	}
+	db := o.data.DB(ctx).Table(OrganizationTable)
+	err := db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&orgs).Error
+	if err != nil {
+		log.Println("Error saving organizations")
+		return err
+	}
+	return nil
+}
+
+func (o *GormOrganizationDAO) GetOrganizationByLogin(ctx context.Context, login string) (org Organization, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(OrganizationTable)
+	err = db.Where("login = ?", login).First(&org).Error
+	if err != nil {
+		log.Println("Error getting organization by login")
+		return Organization{}, err
+	}
+	return org, nil
+}
+
+func (o *GormOrganizationDAO) GetOrganizationsByUserID(ctx context.Context, userID int64) (orgs []Organization, err error) {
+	db := o.data.Mysql.WithContext(ctx)
+	err = db.Select("DISTINCT organizations.*").
+		Joins("JOIN memberships ON memberships.org_id = organizations.id").
+		Where("memberships.user_id = ?", userID).
+		Find(&orgs).Error
+	if err != nil {
+		log.Println("Error getting organizations by user ID")
+		return nil, err
+	}
+	return orgs, nil
+}
+
+func (o *GormOrganizationDAO) CreateMemberships(ctx context.Context, memberships []Membership) error {
+	if len(memberships) == 0 {
+		return nil
+	}
+	db := o.data.DB(ctx).Table(MembershipTable)
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&memberships).Error
+	if err != nil {
+		log.Println("Error creating memberships")
+		return err
+	}
+	return nil
+}
+
+// ReplaceMemberships 用新的成员关系覆盖组织原有的成员关系
+func (o *GormOrganizationDAO) ReplaceMemberships(ctx context.Context, orgID int64, memberships []Membership) error {
+	db := o.data.DB(ctx).Table(MembershipTable)
+	if err := db.Where("org_id = ?", orgID).Delete(Membership{}).Error; err != nil {
+		log.Println("Error deleting memberships")
+		return err
+	}
+	return o.CreateMemberships(ctx, memberships)
+}
+
+func (o *GormOrganizationDAO) GetCountOfMembers(ctx context.Context, orgID int64) (cnt int64, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(MembershipTable)
+	err = db.Where("org_id = ?", orgID).Count(&cnt).Error
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// GetMembersJoinMembership 按照分数从高到低分页返回组织的成员
+func (o *GormOrganizationDAO) GetMembersJoinMembership(ctx context.Context, orgID int64, page int, pageSize int) (users []User, err error) {
+	db := o.data.Mysql.WithContext(ctx)
+	err = db.Select("DISTINCT users.*").
+		Joins("JOIN memberships ON memberships.user_id = users.id").
+		Where("memberships.org_id = ?", orgID).
+		Order("users.score DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&users).Error
+	if err != nil {
+		log.Println("Error getting members of organization")
+		return nil, err
+	}
+	return users, nil
+}
+
+// SaveOrgRepos 用新的仓库列表覆盖组织原有的仓库
+func (o *GormOrganizationDAO) SaveOrgRepos(ctx context.Context, orgID int64, repos []OrgRepo) error {
+	db := o.data.DB(ctx).Table(OrgRepoTable)
+	if err := db.Where("org_id = ?", orgID).Delete(OrgRepo{}).Error; err != nil {
+		log.Println("Error deleting org repos")
+		return err
+	}
+	if len(repos) == 0 {
+		return nil
+	}
+	if err := o.data.DB(ctx).Table(OrgRepoTable).Create(&repos).Error; err != nil {
+		log.Println("Error creating org repos")
+		return err
+	}
+	return nil
+}
+
+func (o *GormOrganizationDAO) GetOrgRepos(ctx context.Context, orgID int64) (repos []OrgRepo, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(OrgRepoTable)
+	err = db.Where("org_id = ?", orgID).Order("stargazers_count DESC").Find(&repos).Error
+	if err != nil {
+		log.Println("Error getting org repos")
+		return nil, err
+	}
+	return repos, nil
+}
+
+// GetOrgDomains 统计组织成员的领域分布
+func (o *GormOrganizationDAO) GetOrgDomains(ctx context.Context, orgID int64) (domains []OrgDomain, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(DomainTable)
+	err = db.Select("domain.domain AS domain, COUNT(DISTINCT domain.user_id) AS count").
+		Joins("JOIN memberships ON memberships.user_id = domain.user_id").
+		Where("memberships.org_id = ?", orgID).
+		Group("domain.domain").
+		Order("count DESC").
+		Scan(&domains).Error
+	if err != nil {
+		log.Println("Error getting org domains")
+		return nil, err
+	}
+	return domains, nil
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// 用户同步的阶段,登录之后先拉取关注关系并计算分数,再分别推断领域和国籍
+const (
+	SyncStepGraph  = "graph"
+	SyncStepDomain = "domain"
+	SyncStepNation = "nation"
+)
+
+// 同步的状态,阶段的状态只会是 pending,done,failed 和阶段自己的执行状态
+const (
+	SyncPending         = "pending"
+	SyncFetchingGraph   = "fetching_graph"
+	SyncScoring         = "scoring"
+	SyncInferringDomain = "inferring_domain"
+	SyncInferringNation = "inferring_nation"
+	SyncDone            = "done"
+	SyncFailed          = "failed"
+)
+
+// SyncStep 一个阶段的状态,Error 不为空且状态不是 failed 时表示上一次执行失败,正在等待重试
+type SyncStep struct {
+	State     string    `json:"state"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SyncStatus 用户同步的整体状态,由各个阶段的状态得出
+type SyncStatus struct {
+	UserID    int64               `json:"user_id"`
+	State     string              `json:"state"`
+	Error     string              `json:"error,omitempty"`
+	Steps     map[string]SyncStep `json:"steps"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
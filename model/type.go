@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type RepoInfo struct {
 	Name             string `json:"name"` // 仓库名称
 	Description      string `json:"description"`
@@ -10,15 +12,49 @@ type RepoInfo struct {
 }
 
 type Repo struct {
-	Name     string `json:"name"`
-	Readme   string `json:"readme"`
-	Language string `json:"language"` // 使用最多的编程语言
-	Commit   int32  `json:"commit_count"`
+	Name     string    `json:"name"`
+	Readme   string    `json:"readme"`
+	Language string    `json:"language"` // 使用最多的编程语言
+	Commit   int32     `json:"commit_count"`
+	PushedAt time.Time `json:"pushed_at"` // 最近推送的时间,没有变化时增量同步不再获取readme和提交数
+	URL      string    `json:"url"`       // 仓库的API地址,包含仓库当前的所有者
+}
+
+// StarredRepo 用户star过的仓库
+type StarredRepo struct {
+	Name            string   `json:"name"`
+	Language        string   `json:"language"`
+	Topics          []string `json:"topics"`
+	StargazersCount int      `json:"stargazers_count"`
 }
 
 type UserEvent struct {
 	Repo             RepoInfo `json:"repo"`
+	Day              string   `json:"day,omitempty"` // 按照仓库和日期统计时的日期(UTC,2006-01-02),为空表示只按照仓库统计
 	PushCount        int      `json:"push_count"`
 	IssuesCount      int      `json:"issues_count"`
 	PullRequestCount int      `json:"pull_request_count"`
 }
+
+// SumUserEventsByRepo 把按照仓库和日期统计的事件合并为只按照仓库统计,仓库信息使用最完整的一个
+func SumUserEventsByRepo(events []UserEvent) []UserEvent {
+	resp := make([]UserEvent, 0, len(events))
+	index := make(map[string]int, len(events))
+	for _, v := range events {
+		v.Day = ""
+		i, ok := index[v.Repo.Name]
+		if !ok {
+			index[v.Repo.Name] = len(resp)
+			resp = append(resp, v)
+			continue
+		}
+		resp[i].PushCount += v.PushCount
+		resp[i].IssuesCount += v.IssuesCount
+		resp[i].PullRequestCount += v.PullRequestCount
+		//只有用户自己的仓库有完整的信息
+		if v.Repo != (RepoInfo{Name: v.Repo.Name}) {
+			resp[i].Repo = v.Repo
+		}
+	}
+	return resp
+}
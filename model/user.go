@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/google/go-github/v50/github"
 	"gorm.io/gorm"
+	"hash/fnv"
+	"time"
 )
 
 const (
@@ -11,25 +13,47 @@ const (
 	ContactTable = "contacts"
 )
 
+// 支持的代码托管平台
+const (
+	ForgeGitHub = "github"
+	ForgeGitLab = "gitlab"
+	ForgeGitea  = "gitea"
+)
+
+const (
+	// SourceLLM 推断结果来自llm
+	SourceLLM = "llm"
+	// SourceFallback 推断结果来自规则推断,置信度更低,之后llm成功时会被替换
+	SourceFallback = "fallback"
+	// NationUnknown 国籍的置信度低于阈值时存储的值
+	NationUnknown = "N/A"
+)
+
 // User 模型
 type User struct {
-	ID                int64   `gorm:"column:id;primaryKey" `
-	LoginName         string  `gorm:"column:login_name" json:"login_name"`                   //用户的登录名
-	Name              string  `gorm:"column:name" json:"name"`                               //真实姓名
-	Location          string  `gorm:"column:location" json:"location"`                       //地区
-	Email             string  `gorm:"column:email" json:"email"`                             //邮箱
-	Following         int     `gorm:"column:following" json:"following"`                     //关注数
-	Followers         int     `gorm:"column:followers" json:"followers"`                     //粉丝数
-	Blog              string  `gorm:"column:blog" json:"blog"`                               //博客连接
-	Bio               string  `gorm:"column:bio" json:"Bio"`                                 //用户的个人简介
-	PublicRepos       int     `gorm:"column:public_repos" json:"public_repos"`               //用户公开的仓库的数量
-	TotalPrivateRepos int     `gorm:"column:total_private_repos" json:"total_private_repos"` //用户的私有仓库总数
-	Company           string  `gorm:"column:company" json:"company"`                         //用户所属的公司
-	AvatarURL         string  `gorm:"column:avatar_url" json:"avatar_url"`                   //用户头像的 URL
-	Collaborators     int     `gorm:"column:collaborators" json:"collaborators"`             //协作者的数量
-	Nationality       string  `gorm:"column:nationality" json:"nationality"`                 //国籍
-	Score             float64 `gorm:"column:score;index" json:"score"`                       //评分
-	Evaluation        string  `gorm:"column:evaluation" json:"evaluation"`                   //评估
+	ID                int64      `gorm:"column:id;primaryKey" `
+	LoginName         string     `gorm:"column:login_name" json:"login_name"`                                                                     //用户的登录名
+	Name              string     `gorm:"column:name" json:"name"`                                                                                 //真实姓名
+	Location          string     `gorm:"column:location" json:"location"`                                                                         //地区
+	Email             string     `gorm:"column:email" json:"email"`                                                                               //邮箱
+	Following         int        `gorm:"column:following" json:"following"`                                                                       //关注数
+	Followers         int        `gorm:"column:followers" json:"followers"`                                                                       //粉丝数
+	Blog              string     `gorm:"column:blog" json:"blog"`                                                                                 //博客连接
+	Bio               string     `gorm:"column:bio" json:"Bio"`                                                                                   //用户的个人简介
+	PublicRepos       int        `gorm:"column:public_repos" json:"public_repos"`                                                                 //用户公开的仓库的数量
+	TotalPrivateRepos int        `gorm:"column:total_private_repos" json:"total_private_repos"`                                                   //用户的私有仓库总数
+	Company           string     `gorm:"column:company" json:"company"`                                                                           //用户所属的公司
+	AvatarURL         string     `gorm:"column:avatar_url" json:"avatar_url"`                                                                     //用户头像的 URL
+	Collaborators     int        `gorm:"column:collaborators" json:"collaborators"`                                                               //协作者的数量
+	Nationality       string     `gorm:"column:nationality;index:idx_nationality_confidence,priority:1" json:"nationality"`                       //国籍
+	NationConfidence  float32    `gorm:"column:nationality_confidence;index:idx_nationality_confidence,priority:2" json:"nationality_confidence"` //国籍的置信度
+	NationSource      string     `gorm:"column:nationality_source" json:"nationality_source"`                                                     //llm或者fallback
+	Score             float64    `gorm:"column:score;index" json:"score"`                                                                         //评分
+	Evaluation        string     `gorm:"column:evaluation" json:"evaluation"`                                                                     //评估
+	Forge             string     `gorm:"column:forge;default:github;index:idx_forge_user" json:"forge"`                                           //代码托管平台
+	ExternalID        int64      `gorm:"column:external_id;index:idx_forge_user" json:"external_id"`                                              //用户在代码托管平台上的ID
+	InferredAt        *time.Time `gorm:"column:inferred_at" json:"inferred_at,omitempty"`                                                         //后台批量推断的时间,为空表示还没有推断过
+	LastSyncedAt      *time.Time `gorm:"column:last_synced_at;index" json:"last_synced_at,omitempty"`                                             //最近一次从平台拉取资料和评分的时间
 }
 
 type FollowingContact struct {
@@ -60,9 +84,22 @@ func (c *FollowingContact) BeforeCreate(tx *gorm.DB) (err error) {
 	return nil
 }
 
+// ForgeUserID 由(forge, 平台上的ID)得到系统内的用户ID
+// github用户直接使用github的ID以兼容已有的数据,其他平台的用户使用哈希得到的负数,避免和github的ID冲突
+func ForgeUserID(forge string, externalID int64) int64 {
+	if forge == ForgeGitHub || forge == "" {
+		return externalID
+	}
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%s:%d", forge, externalID)
+	return -int64(h.Sum64()>>2) - 1
+}
+
 func TransformUser(userInfo *github.User) User {
 	return User{
 		ID:                userInfo.GetID(),
+		Forge:             ForgeGitHub,
+		ExternalID:        userInfo.GetID(),
 		LoginName:         userInfo.GetLogin(),
 		AvatarURL:         userInfo.GetAvatarURL(),
 		Name:              userInfo.GetName(),
@@ -78,4 +115,3 @@ func TransformUser(userInfo *github.User) User {
 		Collaborators:     userInfo.GetCollaborators(),
 	}
 }
-
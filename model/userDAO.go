@@ -4,6 +4,8 @@ import (
 	"context"
 	"gorm.io/gorm/clause"
 	"log"
+	"strings"
+	"time"
 )
 
 // GormUserDAO 实现了 UserDAO 接口
@@ -19,10 +21,17 @@ func NewGormUserDAO(data *Data) *GormUserDAO {
 }
 
 // CreateUsers 这里更新的数据不包括国籍和评价
+// 传入的都是刚从平台拉取的用户,同时记录同步的时间
 func (o *GormUserDAO) CreateUsers(ctx context.Context, users []User) error {
 	if len(users) == 0 {
 		return nil
 	}
+	now := time.Now()
+	synced := make([]User, len(users))
+	for i, u := range users {
+		u.LastSyncedAt = &now
+		synced[i] = u
+	}
 
 	db := o.data.DB(ctx).Table(UserTable)
 
@@ -30,13 +39,14 @@ func (o *GormUserDAO) CreateUsers(ctx context.Context, users []User) error {
 	updateFields := []string{
 		"login_name", "name", "location", "email", "following", "followers",
 		"blog", "bio", "public_repos", "total_private_repos", "company",
-		"avatar_url", "collaborators", "score",
+		"avatar_url", "collaborators", "score", "forge", "external_id",
+		"last_synced_at",
 	}
 
 	// 设置冲突时更新指定字段
 	err := db.Clauses(clause.OnConflict{
 		DoUpdates: clause.AssignmentColumns(updateFields),
-	}).Create(&users).Error
+	}).Create(&synced).Error
 
 	// 错误处理
 	if err != nil {
@@ -57,6 +67,20 @@ func (o *GormUserDAO) GetUserByID(ctx context.Context, id int64) (u User, err er
 	return u, nil
 }
 
+// GetUsersByIds 批量获取用户,不保证顺序
+func (o *GormUserDAO) GetUsersByIds(ctx context.Context, ids []int64) (users []User, err error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	db := o.data.Mysql.WithContext(ctx).Table(UserTable)
+	err = db.Where("id IN ?", ids).Find(&users).Error
+	if err != nil {
+		log.Println("Error getting users by IDs")
+		return nil, err
+	}
+	return users, nil
+}
+
 func (o *GormUserDAO) GetFollowingUsersJoinContact(ctx context.Context, id int64) (users []User, err error) {
 	db := o.data.Mysql.WithContext(ctx)
 	err = db.Select("DISTINCT users.*").
@@ -93,20 +117,44 @@ func (o *GormUserDAO) SaveUser(ctx context.Context, user User) error {
 	return nil
 }
 
-func (o *GormUserDAO) SearchUser(ctx context.Context, nation *string, domain string, page int, pageSize int) (users []User, err error) {
+// SaveEvaluation 只更新用户最新的评价,避免覆盖其他字段
+func (o *GormUserDAO) SaveEvaluation(ctx context.Context, id int64, evaluation string) error {
+	db := o.data.DB(ctx).Table(UserTable)
+	err := db.Where("id = ?", id).Update("evaluation", evaluation).Error
+	if err != nil {
+		log.Println("Error saving evaluation")
+		return err
+	}
+	return nil
+}
+
+// GetScoreRank 评分高于score的用户数加一,即按照评分的排名
+func (o *GormUserDAO) GetScoreRank(ctx context.Context, score float64) (int64, error) {
+	var count int64
+	db := o.data.Mysql.WithContext(ctx).Table(UserTable)
+	err := db.Where("score > ?", score).Count(&count).Error
+	if err != nil {
+		log.Println("Error getting score rank")
+		return 0, err
+	}
+	return count + 1, nil
+}
+
+// SearchUser minConfidence 同时作用于领域和国籍的置信度
+func (o *GormUserDAO) SearchUser(ctx context.Context, nation *string, domain string, minConfidence float64, page int, pageSize int) (users []User, err error) {
 	db := o.data.Mysql.WithContext(ctx)
 
-	// 基础查询：联合查询用户和域名，按域名和分数排序,这里使用|进行划分
+	// 基础查询：联合查询用户和域名，按域名和分数排序
 	query := db.Select("DISTINCT users.*").
 		Joins("JOIN domain ON domain.user_id = users.id").
-		Where("SUBSTRING_INDEX(domain.domain, '|', 1) = ?", domain).
+		Where("domain.domain = ? AND domain.confidence >= ?", domain, minConfidence).
 		Order("users.score DESC").
 		Offset((page - 1) * pageSize).
 		Limit(pageSize)
 
 	// 如果 nation 不为 nil，添加国家筛选条件
 	if nation != nil {
-		query = query.Where("SUBSTRING_INDEX(users.nationality, '|', 1) = ?", *nation)
+		query = query.Where("users.nationality = ? AND users.nationality_confidence >= ?", *nation, minConfidence)
 	}
 
 	// 执行查询
@@ -118,3 +166,159 @@ func (o *GormUserDAO) SearchUser(ctx context.Context, nation *string, domain str
 
 	return users, nil
 }
+
+// SaveNationality 只更新国籍相关的字段
+func (o *GormUserDAO) SaveNationality(ctx context.Context, id int64, nation string, confidence float32, source string) error {
+	db := o.data.DB(ctx).Table(UserTable)
+	err := db.Where("id = ?", id).Updates(map[string]interface{}{
+		"nationality":            nation,
+		"nationality_confidence": confidence,
+		"nationality_source":     source,
+	}).Error
+	if err != nil {
+		log.Println("Error saving nationality")
+		return err
+	}
+	return nil
+}
+
+// GetUsersMissingInference 获取还没有批量推断过,并且缺少国籍或者领域的用户,分数高的优先
+func (o *GormUserDAO) GetUsersMissingInference(ctx context.Context, limit int) (users []User, err error) {
+	db := o.data.Mysql.WithContext(ctx).Table(UserTable)
+	err = db.Where("inferred_at IS NULL").
+		Where("nationality = '' OR nationality IS NULL OR NOT EXISTS (SELECT 1 FROM domain WHERE domain.user_id = users.id)").
+		Order("score DESC").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		log.Println("Error getting users missing inference")
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetStaleUsers 获取上次同步早于before的github用户,从来没有同步过的和最久的优先
+// ids不为nil时只在这些用户中选取
+func (o *GormUserDAO) GetStaleUsers(ctx context.Context, ids []int64, before time.Time, limit int) (users []User, err error) {
+	if ids != nil && len(ids) == 0 {
+		return nil, nil
+	}
+	db := o.data.Mysql.WithContext(ctx).Table(UserTable)
+	query := db.Where("forge = ?", ForgeGitHub).
+		Where("last_synced_at IS NULL OR last_synced_at < ?", before).
+		Order("last_synced_at ASC").
+		Limit(limit)
+	if ids != nil {
+		query = query.Where("id IN ?", ids)
+	}
+	err = query.Find(&users).Error
+	if err != nil {
+		log.Println("Error getting stale users")
+		return nil, err
+	}
+	return users, nil
+}
+
+// MarkSynced 只更新用户的同步时间,用于拉取失败的用户
+func (o *GormUserDAO) MarkSynced(ctx context.Context, ids []int64, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	db := o.data.DB(ctx).Table(UserTable)
+	err := db.Where("id IN ?", ids).Update("last_synced_at", at).Error
+	if err != nil {
+		log.Println("Error marking users synced")
+		return err
+	}
+	return nil
+}
+
+// MarkInferred 标记用户已经批量推断过,没有推断出结果的用户也不会再被选中
+func (o *GormUserDAO) MarkInferred(ctx context.Context, ids []int64, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	db := o.data.DB(ctx).Table(UserTable)
+	err := db.Where("id IN ?", ids).Update("inferred_at", at).Error
+	if err != nil {
+		log.Println("Error marking users inferred")
+		return err
+	}
+	return nil
+}
+
+// GetMatchCandidates 获取领域或者兴趣和要求有交集的用户,交集大的优先,交集相同时分数高的优先
+// domains和names需要是去掉分隔符的小写形式,领域允许一个包含另一个,和排序时的匹配规则一致
+func (o *GormUserDAO) GetMatchCandidates(ctx context.Context, domains []string, names []string, nation *string, minScore float64, limit int) (users []User, err error) {
+	var (
+		match, overlap []string
+		args           []any
+	)
+	if len(domains) > 0 {
+		column := normalizedColumn("domain")
+		var like []string
+		for _, d := range domains {
+			like = append(like,
+				column+" LIKE ? ESCAPE '!'",
+				"(? LIKE CONCAT('%', "+escapedColumn(column)+", '%') ESCAPE '!' AND "+column+" <> '')")
+			args = append(args, "%"+escapeLike(d)+"%", d)
+		}
+		cond := strings.Join(like, " OR ")
+		match = append(match, "users.id IN (SELECT user_id FROM domain WHERE "+cond+")")
+		overlap = append(overlap, "(SELECT COUNT(*) FROM domain WHERE domain.user_id = users.id AND ("+cond+"))")
+	}
+	if len(names) > 0 {
+		cond := normalizedColumn("name") + " IN ?"
+		match = append(match, "users.id IN (SELECT user_id FROM interest WHERE "+cond+")")
+		overlap = append(overlap, "(SELECT COUNT(*) FROM interest WHERE interest.user_id = users.id AND "+cond+")")
+		args = append(args, names)
+	}
+	if len(match) == 0 {
+		return nil, nil
+	}
+
+	//先按照匹配到的领域和兴趣的数量排序,评分低但是匹配度高的用户不会在排序之前被截掉
+	db := o.data.Mysql.WithContext(ctx).Table(UserTable)
+	query := db.Where("users.score >= ?", minScore).
+		Where("("+strings.Join(match, " OR ")+")", args...).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  strings.Join(overlap, " + ") + " DESC, users.score DESC",
+			Vars: args,
+		}}).
+		Limit(limit)
+	if nation != nil {
+		query = query.Where("users.nationality = ?", *nation)
+	}
+
+	err = query.Find(&users).Error
+	if err != nil {
+		log.Println("Error getting match candidates:", err)
+		return nil, err
+	}
+	return users, nil
+}
+
+// skillSeparators 比较领域和兴趣时忽略的分隔符,例如 Node.js 和 nodejs, machine learning 和 machine-learning
+var skillSeparators = []string{" ", "-", "_", ".", "/", "(", ")", ",", "&"}
+
+// normalizedColumn 在sql中转成小写并去掉分隔符
+func normalizedColumn(column string) string {
+	expr := "LOWER(" + column + ")"
+	for _, sep := range skillSeparators {
+		expr = "REPLACE(" + expr + ", '" + sep + "', '')"
+	}
+	return expr
+}
+
+// LIKE 的转义字符,使用 ! 避免反斜杠在不同的 sql_mode 下含义不同
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// escapeLike 转义LIKE中的通配符,需要和 ESCAPE '!' 一起使用
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// escapedColumn 在sql中转义列中的通配符,列的内容作为LIKE的模式时使用
+func escapedColumn(column string) string {
+	return "REPLACE(REPLACE(REPLACE(" + column + ", '!', '!!'), '%', '!%'), '_', '!_')"
+}
@@ -0,0 +1,58 @@
+package model
+
+import "testing"
+
+func TestNormalizedColumn(t *testing.T) {
+	tests := []struct {
+		column string
+		want   string
+	}{
+		{
+			column: "domain",
+			want:   "REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(LOWER(domain), ' ', ''), '-', ''), '_', ''), '.', ''), '/', ''), '(', ''), ')', ''), ',', ''), '&', '')",
+		},
+		{
+			column: "interest.name",
+			want:   "REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(LOWER(interest.name), ' ', ''), '-', ''), '_', ''), '.', ''), '/', ''), '(', ''), ')', ''), ',', ''), '&', '')",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			if got := normalizedColumn(tt.column); got != tt.want {
+				t.Errorf("normalizedColumn(%q) = %q, want %q", tt.column, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "backend", want: "backend"},
+		{in: "c++", want: "c++"},
+		{in: "100%", want: "100!%"},
+		{in: "a_b", want: "a!_b"},
+		{in: "wow!", want: "wow!!"},
+		{in: "!%_", want: "!!!%!_"},
+		{in: `c:\dev`, want: `c:\dev`},
+		{in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := escapeLike(tt.in); got != tt.want {
+				t.Errorf("escapeLike(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapedColumn(t *testing.T) {
+	want := "REPLACE(REPLACE(REPLACE(domain, '!', '!!'), '%', '!%'), '_', '!_')"
+	if got := escapedColumn("domain"); got != want {
+		t.Errorf("escapedColumn() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,130 @@
+package fallback
+
+import (
+	"github.com/GitEval/GitEval-Backend/model"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// MaxConfidence 规则推断的置信度上限,低于llm的结果,之后llm成功时会被替换
+	MaxConfidence = 0.5
+	// LocationConfidence 用户自己填写的地点匹配成功时的置信度
+	LocationConfidence = 0.45
+	// ContactConfidence 只能从关注关系推断国籍时的置信度上限
+	ContactConfidence = 0.3
+	// MinContactVotes 从关注关系推断国籍至少需要匹配的人数
+	MinContactVotes = 3
+	// MaxDomains 最多推断的领域数
+	MaxDomains = 3
+	// MinDomainShare 领域的权重占比低于这个值时不返回
+	MinDomainShare = 0.2
+	// InterestWeight star过的仓库只是弱信号
+	InterestWeight = 0.5
+)
+
+// Domain 推断出的领域
+type Domain struct {
+	Domain     string
+	Confidence float32
+}
+
+// Inferrer 基于规则的推断,在llm不可用时使用,结果是确定的
+type Inferrer struct {
+	gazetteer *Gazetteer
+}
+
+func NewInferrer() *Inferrer {
+	return &Inferrer{gazetteer: NewGazetteer()}
+}
+
+// Nation 优先使用用户自己填写的地点,否则按照关注关系中的地点投票
+func (f *Inferrer) Nation(location string, followerLoc, followingLoc []string) (string, float32) {
+	if country, ok := f.gazetteer.Match(location); ok {
+		return country, LocationConfidence
+	}
+
+	var (
+		votes = make(map[string]int)
+		total int
+	)
+	for _, locs := range [][]string{followerLoc, followingLoc} {
+		for _, loc := range locs {
+			if country, ok := f.gazetteer.Match(loc); ok {
+				votes[country]++
+				total++
+			}
+		}
+	}
+	if total < MinContactVotes {
+		return "", 0
+	}
+
+	country, count := "", 0
+	for c, n := range votes {
+		if n > count || (n == count && c < country) {
+			country, count = c, n
+		}
+	}
+	share := float64(count) / float64(total)
+	//没有明显多数时不做推断
+	if share < 0.5 {
+		return "", 0
+	}
+	return country, float32(ContactConfidence * share)
+}
+
+// Domains 根据仓库使用的语言和star过的仓库的topic推断领域
+func (f *Inferrer) Domains(repos []*model.Repo, interests []model.Interest) []Domain {
+	weights := make(map[string]float64)
+	for _, repo := range repos {
+		//提交越多的仓库越能代表用户,取对数避免单个仓库占比过大
+		addDomains(weights, languageDomains[strings.ToLower(repo.Language)], 1+math.Log10(1+float64(repo.Commit)))
+	}
+	for _, interest := range interests {
+		var domains []string
+		switch interest.Kind {
+		case model.InterestKindLanguage:
+			domains = languageDomains[strings.ToLower(interest.Name)]
+		case model.InterestKindTopic:
+			domains = topicDomains[strings.ToLower(interest.Name)]
+		}
+		addDomains(weights, domains, interest.Weight*InterestWeight)
+	}
+
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 {
+		return nil
+	}
+
+	res := make([]Domain, 0, len(weights))
+	for d, w := range weights {
+		if share := w / total; share >= MinDomainShare {
+			res = append(res, Domain{Domain: d, Confidence: float32(MaxConfidence * share)})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Confidence != res[j].Confidence {
+			return res[i].Confidence > res[j].Confidence
+		}
+		return res[i].Domain < res[j].Domain
+	})
+	if len(res) > MaxDomains {
+		res = res[:MaxDomains]
+	}
+	return res
+}
+
+// addDomains 第一个领域是主要领域,之后的领域权重减半
+func addDomains(weights map[string]float64, domains []string, weight float64) {
+	for i, d := range domains {
+		if i > 0 {
+			weight /= 2
+		}
+		weights[d] += weight
+	}
+}
@@ -0,0 +1,145 @@
+package fallback
+
+import (
+	"math"
+	"testing"
+
+	"github.com/GitEval/GitEval-Backend/model"
+)
+
+func TestNation(t *testing.T) {
+	tests := []struct {
+		name           string
+		location       string
+		followers      []string
+		following      []string
+		wantCountry    string
+		wantConfidence float32
+	}{
+		{
+			name:           "own location",
+			location:       "Berlin, Germany",
+			followers:      []string{"Tokyo", "Tokyo", "Tokyo"},
+			wantCountry:    "Germany",
+			wantConfidence: LocationConfidence,
+		},
+		{
+			name:           "majority of contacts",
+			location:       "Earth",
+			followers:      []string{"Tokyo", "Osaka"},
+			following:      []string{"Seoul", "somewhere"},
+			wantCountry:    "Japan",
+			wantConfidence: ContactConfidence * 2 / 3,
+		},
+		{
+			name:           "all contacts agree",
+			followers:      []string{"London"},
+			following:      []string{"Manchester", "UK"},
+			wantCountry:    "United Kingdom",
+			wantConfidence: ContactConfidence,
+		},
+		{
+			name:           "tie is broken by name",
+			followers:      []string{"Seoul", "Tokyo"},
+			following:      []string{"Seoul", "Tokyo"},
+			wantCountry:    "Japan",
+			wantConfidence: ContactConfidence / 2,
+		},
+		{
+			name:      "too few contacts",
+			followers: []string{"Tokyo", "Osaka", "nowhere"},
+		},
+		{
+			name:      "no clear majority",
+			followers: []string{"Tokyo", "Seoul", "Berlin", "London"},
+		},
+		{
+			name: "nothing to infer from",
+		},
+	}
+
+	f := NewInferrer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			country, confidence := f.Nation(tt.location, tt.followers, tt.following)
+			if country != tt.wantCountry || !approx(confidence, tt.wantConfidence) {
+				t.Errorf("Nation() = (%q, %v), want (%q, %v)", country, confidence, tt.wantCountry, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestDomains(t *testing.T) {
+	tests := []struct {
+		name      string
+		repos     []*model.Repo
+		interests []model.Interest
+		want      []Domain
+	}{
+		{
+			name: "nothing to infer from",
+			want: nil,
+		},
+		{
+			name:  "unknown language",
+			repos: []*model.Repo{{Language: "Brainfuck"}},
+			want:  nil,
+		},
+		{
+			name:  "secondary domain weighs half",
+			repos: []*model.Repo{{Language: "Go"}},
+			want: []Domain{
+				{Domain: "Backend", Confidence: MaxConfidence * 2 / 3},
+				{Domain: "Cloud Native", Confidence: MaxConfidence / 3},
+			},
+		},
+		{
+			name:  "more commits weigh more",
+			repos: []*model.Repo{{Language: "Go", Commit: 99}, {Language: "Python"}},
+			// Backend 3+0.5, Cloud Native 1.5, Machine Learning 1
+			want: []Domain{
+				{Domain: "Backend", Confidence: MaxConfidence * 3.5 / 6},
+				{Domain: "Cloud Native", Confidence: MaxConfidence * 1.5 / 6},
+			},
+		},
+		{
+			name: "interests weigh less",
+			interests: []model.Interest{
+				{Kind: model.InterestKindTopic, Name: "Kubernetes", Weight: 1},
+				{Kind: model.InterestKindLanguage, Name: "unknown", Weight: 1},
+			},
+			want: []Domain{
+				{Domain: "Cloud Native", Confidence: MaxConfidence * 2 / 3},
+				{Domain: "DevOps", Confidence: MaxConfidence / 3},
+			},
+		},
+		{
+			name:  "keeps at most MaxDomains",
+			repos: []*model.Repo{{Language: "Verilog"}, {Language: "Solidity"}, {Language: "Lua"}, {Language: "Shell"}, {Language: "R"}},
+			want: []Domain{
+				{Domain: "Blockchain", Confidence: MaxConfidence / 5},
+				{Domain: "Data Science", Confidence: MaxConfidence / 5},
+				{Domain: "DevOps", Confidence: MaxConfidence / 5},
+			},
+		},
+	}
+
+	f := NewInferrer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := f.Domains(tt.repos, tt.interests)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Domains() = %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i].Domain != tt.want[i].Domain || !approx(got[i].Confidence, tt.want[i].Confidence) {
+					t.Errorf("Domains()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func approx(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-6
+}
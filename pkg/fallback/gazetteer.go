@@ -0,0 +1,106 @@
+package fallback
+
+import (
+	"bufio"
+	_ "embed"
+	"strings"
+	"unicode"
+)
+
+//go:embed gazetteer.txt
+var gazetteerData string
+
+// Gazetteer 离线地名表,把地点中的城市,省/州,国家的别名映射到国家
+type alias struct {
+	name    string
+	country string
+}
+
+type Gazetteer struct {
+	aliases  map[string]string
+	cjk      []alias //中日韩的别名没有空格分词,只能按子串匹配
+	maxWords int
+}
+
+func NewGazetteer() *Gazetteer {
+	g := &Gazetteer{
+		aliases: make(map[string]string),
+	}
+	scanner := bufio.NewScanner(strings.NewReader(gazetteerData))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		country, aliases, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		country = strings.TrimSpace(country)
+		for _, name := range strings.Split(aliases, ",") {
+			g.add(country, name)
+		}
+	}
+	return g
+}
+
+func (g *Gazetteer) add(country, name string) {
+	if isCJK(name) {
+		g.cjk = append(g.cjk, alias{name: strings.TrimSpace(name), country: country})
+		return
+	}
+	words := tokenize(name)
+	if len(words) == 0 {
+		return
+	}
+	g.aliases[strings.Join(words, " ")] = country
+	g.maxWords = max(g.maxWords, len(words))
+}
+
+// Match 匹配地点所在的国家,有多个匹配时取最靠后的,因为国家一般写在最后,例如 "Cambridge, MA, USA"
+func (g *Gazetteer) Match(location string) (string, bool) {
+	var (
+		country string
+		bestEnd = -1
+		bestLen int
+	)
+	words := tokenize(location)
+	for i := range words {
+		for n := min(g.maxWords, len(words)-i); n > 0; n-- {
+			c, ok := g.aliases[strings.Join(words[i:i+n], " ")]
+			if !ok {
+				continue
+			}
+			if end := i + n; end > bestEnd || (end == bestEnd && n > bestLen) {
+				country, bestEnd, bestLen = c, end, n
+			}
+			break
+		}
+	}
+	if country != "" {
+		return country, true
+	}
+
+	for _, a := range g.cjk {
+		if i := strings.LastIndex(location, a.name); i >= 0 && i+len(a.name) > bestEnd {
+			country, bestEnd = a.country, i+len(a.name)
+		}
+	}
+	return country, country != ""
+}
+
+// tokenize 转成小写,只保留字母和数字
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func isCJK(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+	return false
+}
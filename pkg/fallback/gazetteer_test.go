@@ -0,0 +1,30 @@
+package fallback
+
+import "testing"
+
+func TestGazetteerMatch(t *testing.T) {
+	tests := []struct {
+		location string
+		want     string
+	}{
+		{location: "Berlin", want: "Germany"},
+		{location: "mountain view, CA", want: "United States"},
+		{location: "Xi'an", want: "China"},
+		{location: "Cambridge", want: "United Kingdom"},
+		{location: "Cambridge, MA, USA", want: "United States"},
+		{location: "北京", want: "China"},
+		{location: "東京", want: "Japan"},
+		{location: "Remote / Earth", want: ""},
+		{location: "", want: ""},
+	}
+
+	g := NewGazetteer()
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			got, ok := g.Match(tt.location)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("Match(%q) = (%q, %v), want %q", tt.location, got, ok, tt.want)
+			}
+		})
+	}
+}
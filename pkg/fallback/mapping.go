@@ -0,0 +1,118 @@
+package fallback
+
+// languageDomains 编程语言到领域的映射,第一个是主要领域
+var languageDomains = map[string][]string{
+	"go":               {"Backend", "Cloud Native"},
+	"java":             {"Backend", "Mobile"},
+	"kotlin":           {"Mobile", "Backend"},
+	"scala":            {"Big Data", "Backend"},
+	"php":              {"Backend", "Web"},
+	"ruby":             {"Backend", "Web"},
+	"elixir":           {"Backend"},
+	"erlang":           {"Backend"},
+	"c#":               {"Backend", "Game Development"},
+	"python":           {"Machine Learning", "Backend"},
+	"jupyter notebook": {"Machine Learning", "Data Science"},
+	"r":                {"Data Science"},
+	"julia":            {"Data Science"},
+	"matlab":           {"Data Science"},
+	"javascript":       {"Frontend", "Web"},
+	"typescript":       {"Frontend", "Web"},
+	"vue":              {"Frontend"},
+	"svelte":           {"Frontend"},
+	"html":             {"Frontend"},
+	"css":              {"Frontend"},
+	"scss":             {"Frontend"},
+	"swift":            {"Mobile"},
+	"objective-c":      {"Mobile"},
+	"dart":             {"Mobile"},
+	"c":                {"Systems", "Embedded"},
+	"c++":              {"Systems", "Game Development"},
+	"rust":             {"Systems", "Backend"},
+	"zig":              {"Systems"},
+	"assembly":         {"Embedded", "Systems"},
+	"verilog":          {"Hardware"},
+	"systemverilog":    {"Hardware"},
+	"vhdl":             {"Hardware"},
+	"gdscript":         {"Game Development"},
+	"lua":              {"Game Development"},
+	"shell":            {"DevOps"},
+	"dockerfile":       {"DevOps", "Cloud Native"},
+	"hcl":              {"DevOps", "Cloud Native"},
+	"nix":              {"DevOps"},
+	"solidity":         {"Blockchain"},
+	"move":             {"Blockchain"},
+	"haskell":          {"Programming Languages"},
+	"ocaml":            {"Programming Languages"},
+	"coq":              {"Programming Languages"},
+}
+
+// topicDomains 仓库topic到领域的映射,只做精确匹配
+var topicDomains = map[string][]string{
+	"machine-learning":        {"Machine Learning"},
+	"deep-learning":           {"Machine Learning"},
+	"artificial-intelligence": {"Machine Learning"},
+	"ai":                      {"Machine Learning"},
+	"llm":                     {"Machine Learning"},
+	"nlp":                     {"Machine Learning"},
+	"computer-vision":         {"Machine Learning"},
+	"pytorch":                 {"Machine Learning"},
+	"tensorflow":              {"Machine Learning"},
+	"data-science":            {"Data Science"},
+	"data-analysis":           {"Data Science"},
+	"pandas":                  {"Data Science"},
+	"visualization":           {"Data Science"},
+	"big-data":                {"Big Data"},
+	"spark":                   {"Big Data"},
+	"hadoop":                  {"Big Data"},
+	"kafka":                   {"Big Data", "Backend"},
+	"kubernetes":              {"Cloud Native", "DevOps"},
+	"k8s":                     {"Cloud Native", "DevOps"},
+	"docker":                  {"DevOps", "Cloud Native"},
+	"cloud-native":            {"Cloud Native"},
+	"microservices":           {"Cloud Native", "Backend"},
+	"devops":                  {"DevOps"},
+	"terraform":               {"DevOps"},
+	"ci-cd":                   {"DevOps"},
+	"monitoring":              {"DevOps"},
+	"react":                   {"Frontend"},
+	"vue":                     {"Frontend"},
+	"vuejs":                   {"Frontend"},
+	"angular":                 {"Frontend"},
+	"frontend":                {"Frontend"},
+	"css":                     {"Frontend"},
+	"nextjs":                  {"Frontend", "Web"},
+	"android":                 {"Mobile"},
+	"ios":                     {"Mobile"},
+	"flutter":                 {"Mobile"},
+	"react-native":            {"Mobile"},
+	"backend":                 {"Backend"},
+	"api":                     {"Backend"},
+	"web-framework":           {"Backend", "Web"},
+	"grpc":                    {"Backend"},
+	"database":                {"Database"},
+	"sql":                     {"Database"},
+	"mysql":                   {"Database"},
+	"postgresql":              {"Database"},
+	"redis":                   {"Database"},
+	"blockchain":              {"Blockchain"},
+	"ethereum":                {"Blockchain"},
+	"web3":                    {"Blockchain"},
+	"security":                {"Security"},
+	"ctf":                     {"Security"},
+	"pentesting":              {"Security"},
+	"cryptography":            {"Security"},
+	"game":                    {"Game Development"},
+	"game-engine":             {"Game Development"},
+	"gamedev":                 {"Game Development"},
+	"unity":                   {"Game Development"},
+	"godot":                   {"Game Development"},
+	"embedded":                {"Embedded"},
+	"arduino":                 {"Embedded"},
+	"iot":                     {"Embedded"},
+	"rtos":                    {"Embedded"},
+	"compiler":                {"Programming Languages", "Systems"},
+	"programming-language":    {"Programming Languages"},
+	"operating-system":        {"Systems"},
+	"linux":                   {"Systems"},
+}
@@ -0,0 +1,340 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/conf"
+	"github.com/GitEval/GitEval-Backend/model"
+	"github.com/GitEval/GitEval-Backend/pkg/github/expireMap"
+	"golang.org/x/oauth2"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ExpireTime = time.Hour * 24 * 7
+	// PageSize gitea默认单页最多50条
+	PageSize = 50
+	// MaxEvents 事件过多的话就做限流
+	MaxEvents = 1000
+)
+
+// GiteaAPI gitea的实现,直接调用 REST API v1
+// 和github共用一个map存储登录用户的客户端,用户ID由 model.ForgeUserID 得到,不会冲突
+type GiteaAPI struct {
+	clients *expireMap.ExpireMap
+	cfg     *conf.GiteaConfig
+	oauth   *oauth2.Config
+}
+
+// session 登录用户的客户端
+type session struct {
+	client *http.Client
+	login  string
+}
+
+func NewGiteaAPI(c *conf.GiteaConfig, clients *expireMap.ExpireMap) *GiteaAPI {
+	base := strings.TrimSuffix(c.BaseURL, "/")
+	return &GiteaAPI{
+		cfg:     c,
+		clients: clients,
+		oauth: &oauth2.Config{
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			RedirectURL:  c.RedirectURL,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  base + "/login/oauth/authorize",
+				TokenURL: base + "/login/oauth/access_token",
+			},
+		},
+	}
+}
+
+// Name 代码托管平台的名称
+func (g *GiteaAPI) Name() string {
+	return model.ForgeGitea
+}
+
+// Enabled 没有配置clientID时不启用
+func (g *GiteaAPI) Enabled() bool {
+	return g.cfg.ClientID != ""
+}
+
+// Authorized 用户的客户端是否还在,过期或者进程重启之后需要重新登录
+func (g *GiteaAPI) Authorized(userID int64) bool {
+	_, exist := g.getSession(userID)
+	return exist
+}
+
+func (g *GiteaAPI) GetLoginUrl() string {
+	return g.oauth.AuthCodeURL(model.ForgeGitea)
+}
+
+// Authorize 使用 code 完成登录,存储用户的客户端并返回用户信息
+func (g *GiteaAPI) Authorize(ctx context.Context, code string) (model.User, error) {
+	token, err := g.oauth.Exchange(ctx, code)
+	if err != nil {
+		return model.User{}, err
+	}
+	// 这个客户端会自动刷新token
+	client := g.oauth.Client(context.Background(), token)
+
+	var u user
+	if err := g.get(ctx, client, "/user", nil, &u); err != nil {
+		return model.User{}, err
+	}
+
+	res := u.transform()
+	g.clients.Store(res.ID, &session{client: client, login: u.Login}, ExpireTime)
+	return res, nil
+}
+
+func (g *GiteaAPI) GetFollowing(ctx context.Context, id int64) []model.User {
+	return g.listFollow(ctx, id, "following")
+}
+
+func (g *GiteaAPI) GetFollowers(ctx context.Context, id int64) []model.User {
+	return g.listFollow(ctx, id, "followers")
+}
+
+func (g *GiteaAPI) CalculateScore(ctx context.Context, id int64, name string) float64 {
+	repos, err := g.listRepos(ctx, g.getClientOrDefault(id), name)
+	if err != nil {
+		log.Printf("Error getting gitea repositories: %v\n", err)
+		return 0
+	}
+	return calculateScore(repos)
+}
+
+// GetAllRepositories 获取用户的仓库,包括readme,主要语言和用户的提交数
+func (g *GiteaAPI) GetAllRepositories(ctx context.Context, loginName string, userId int64) []*model.Repo {
+	client := g.getClientOrDefault(userId)
+	repos, err := g.listRepos(ctx, client, loginName)
+	if err != nil {
+		log.Printf("Error getting gitea repositories: %v\n", err)
+		return nil
+	}
+
+	var resp []*model.Repo
+	for _, r := range repos {
+		resp = append(resp, &model.Repo{
+			Name:     r.Name,
+			Readme:   g.getReadme(ctx, client, r),
+			Language: r.Language,
+			Commit:   g.getCommitsCount(ctx, client, r, loginName),
+		})
+	}
+	return resp
+}
+
+func (g *GiteaAPI) GetStarredRepositories(ctx context.Context, loginName string, userId int64) []*model.StarredRepo {
+	var repos []repository
+	err := g.get(ctx, g.getClientOrDefault(userId), "/users/"+url.PathEscape(loginName)+"/starred", url.Values{
+		"limit": {strconv.Itoa(PageSize)},
+	}, &repos)
+	if err != nil {
+		log.Printf("Error getting gitea starred repositories: %v\n", err)
+		return nil
+	}
+
+	resp := make([]*model.StarredRepo, 0, len(repos))
+	for _, r := range repos {
+		resp = append(resp, &model.StarredRepo{
+			Name:            r.FullName,
+			Language:        r.Language,
+			Topics:          r.Topics,
+			StargazersCount: r.StarsCount,
+		})
+	}
+	return resp
+}
+
+// GetAllUserEvents 获取登录用户的所有动态,按照仓库分类统计
+func (g *GiteaAPI) GetAllUserEvents(ctx context.Context, username string, userId int64) ([]model.UserEvent, error) {
+	s, exist := g.getSession(userId)
+	if !exist {
+		return nil, errors.New("login fail!")
+	}
+
+	var allActivities []activity
+	for page := 1; len(allActivities) < MaxEvents; page++ {
+		var activities []activity
+		err := g.get(ctx, s.client, "/users/"+url.PathEscape(username)+"/activities/feeds", url.Values{
+			"limit": {strconv.Itoa(PageSize)},
+			"page":  {strconv.Itoa(page)},
+		}, &activities)
+		if err != nil {
+			return nil, err
+		}
+		allActivities = append(allActivities, activities...)
+		if len(activities) < PageSize {
+			break
+		}
+	}
+
+	userEventsMap := make(map[string]*model.UserEvent)
+	for _, a := range allActivities {
+		if a.Repo == nil {
+			continue
+		}
+		userEvent, exists := userEventsMap[a.Repo.FullName]
+		if !exists {
+			userEvent = &model.UserEvent{Repo: model.RepoInfo{
+				Name:             a.Repo.FullName,
+				Description:      a.Repo.Description,
+				StargazersCount:  a.Repo.StarsCount,
+				ForksCount:       a.Repo.ForksCount,
+				CreatedAt:        a.Repo.CreatedAt,
+				SubscribersCount: a.Repo.WatchersCount,
+			}}
+			userEventsMap[a.Repo.FullName] = userEvent
+		}
+		switch a.OpType {
+		case "commit_repo":
+			userEvent.PushCount++
+		case "create_issue":
+			userEvent.IssuesCount++
+		case "create_pull_request":
+			userEvent.PullRequestCount++
+		}
+	}
+
+	userEventsSlice := make([]model.UserEvent, 0, len(userEventsMap))
+	for _, userEvent := range userEventsMap {
+		userEventsSlice = append(userEventsSlice, *userEvent)
+	}
+	return userEventsSlice, nil
+}
+
+func (g *GiteaAPI) listFollow(ctx context.Context, id int64, kind string) []model.User {
+	s, exist := g.getSession(id)
+	if !exist {
+		log.Println("get gitea client failed")
+		return []model.User{}
+	}
+	var users []user
+	err := g.get(ctx, s.client, "/users/"+url.PathEscape(s.login)+"/"+kind, url.Values{
+		"limit": {strconv.Itoa(PageSize)},
+	}, &users)
+	if err != nil {
+		log.Printf("get gitea %s user failed: %v\n", kind, err)
+		return []model.User{}
+	}
+
+	// 列表接口返回的就是完整的用户信息
+	res := make([]model.User, 0, len(users))
+	for _, u := range users {
+		res = append(res, u.transform())
+	}
+	return res
+}
+
+func (g *GiteaAPI) listRepos(ctx context.Context, client *http.Client, name string) ([]repository, error) {
+	var repos []repository
+	err := g.get(ctx, client, "/users/"+url.PathEscape(name)+"/repos", url.Values{
+		"limit": {strconv.Itoa(PageSize)},
+	}, &repos)
+	return repos, err
+}
+
+func (g *GiteaAPI) getReadme(ctx context.Context, client *http.Client, r repository) string {
+	if r.DefaultBranch == "" {
+		return ""
+	}
+	body, err := g.do(ctx, client, "/repos/"+r.FullName+"/raw/README.md", url.Values{"ref": {r.DefaultBranch}})
+	if err != nil {
+		return ""
+	}
+	return string(body)
+}
+
+// getCommitsCount gitea的提交接口不能按作者过滤,只统计最近的提交
+func (g *GiteaAPI) getCommitsCount(ctx context.Context, client *http.Client, r repository, loginName string) int32 {
+	var commits []commit
+	err := g.get(ctx, client, "/repos/"+r.FullName+"/commits", url.Values{
+		"limit": {strconv.Itoa(PageSize)},
+		"stat":  {"false"},
+	}, &commits)
+	if err != nil {
+		return 0
+	}
+	var count int32
+	for _, c := range commits {
+		if c.Author != nil && strings.EqualFold(c.Author.Login, loginName) {
+			count++
+		}
+	}
+	return count
+}
+
+func (g *GiteaAPI) getSession(userID int64) (*session, bool) {
+	val, exist := g.clients.Load(userID)
+	if !exist {
+		return nil, false
+	}
+	s, ok := val.(*session)
+	return s, ok
+}
+
+// getClientOrDefault 优先使用用户自己的客户端,否则使用无认证的客户端
+func (g *GiteaAPI) getClientOrDefault(userID int64) *http.Client {
+	if s, exist := g.getSession(userID); exist {
+		return s.client
+	}
+	return http.DefaultClient
+}
+
+func (g *GiteaAPI) get(ctx context.Context, client *http.Client, path string, query url.Values, v any) error {
+	body, err := g.do(ctx, client, path, query)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+func (g *GiteaAPI) do(ctx context.Context, client *http.Client, path string, query url.Values) ([]byte, error) {
+	u := strings.TrimSuffix(g.cfg.BaseURL, "/") + "/api/v1" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gitea %s: %s", path, resp.Status)
+	}
+	return body, nil
+}
+
+// 具体的计算逻辑,和github保持一致
+func calculateScore(repos []repository) float64 {
+	var totalScore float64
+	for _, r := range repos {
+		totalScore += float64(r.StarsCount)*0.6 + float64(r.ForksCount)*0.9 + float64(r.OpenIssuesCount)*2 + 1
+
+		if r.Fork {
+			totalScore += float64(r.Size) * 0.001 / 1024
+		} else {
+			totalScore += float64(r.Size) * 0.1 / 500
+		}
+	}
+	return totalScore
+}
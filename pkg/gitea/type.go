@@ -0,0 +1,59 @@
+package gitea
+
+import "github.com/GitEval/GitEval-Backend/model"
+
+type user struct {
+	ID          int64  `json:"id"`
+	Login       string `json:"login"`
+	FullName    string `json:"full_name"`
+	Email       string `json:"email"`
+	AvatarURL   string `json:"avatar_url"`
+	Location    string `json:"location"`
+	Website     string `json:"website"`
+	Description string `json:"description"`
+	Followers   int    `json:"followers_count"`
+	Following   int    `json:"following_count"`
+}
+
+type repository struct {
+	ID              int64    `json:"id"`
+	Name            string   `json:"name"`
+	FullName        string   `json:"full_name"`
+	Description     string   `json:"description"`
+	Fork            bool     `json:"fork"`
+	Language        string   `json:"language"`
+	StarsCount      int      `json:"stars_count"`
+	ForksCount      int      `json:"forks_count"`
+	WatchersCount   int      `json:"watchers_count"`
+	OpenIssuesCount int      `json:"open_issues_count"`
+	Size            int      `json:"size"`
+	DefaultBranch   string   `json:"default_branch"`
+	CreatedAt       string   `json:"created_at"`
+	Topics          []string `json:"topics"`
+}
+
+type activity struct {
+	OpType string      `json:"op_type"`
+	Repo   *repository `json:"repo"`
+}
+
+type commit struct {
+	Author *user `json:"author"`
+}
+
+func (u user) transform() model.User {
+	return model.User{
+		ID:         model.ForgeUserID(model.ForgeGitea, u.ID),
+		Forge:      model.ForgeGitea,
+		ExternalID: u.ID,
+		LoginName:  u.Login,
+		Name:       u.FullName,
+		Location:   u.Location,
+		Email:      u.Email,
+		Bio:        u.Description,
+		AvatarURL:  u.AvatarURL,
+		Blog:       u.Website,
+		Followers:  u.Followers,
+		Following:  u.Following,
+	}
+}
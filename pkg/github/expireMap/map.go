@@ -17,8 +17,8 @@ type ExpireMap struct {
 	sleepTime time.Duration
 }
 
-func NewExpireMap() (ExpireMap, func()) {
-	e := ExpireMap{
+func NewExpireMap() (*ExpireMap, func()) {
+	e := &ExpireMap{
 		mp1:       sync.Map{},
 		mp2:       sync.Map{},
 		sleepTime: SleepTime,
@@ -3,6 +3,7 @@ package github
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"github.com/GitEval/GitEval-Backend/conf"
 	"github.com/GitEval/GitEval-Backend/model"
@@ -11,22 +12,28 @@ import (
 	"github.com/google/go-github/v50/github"
 	"golang.org/x/oauth2"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
 
 const (
-	ExpireTime = time.Hour * 24 * 7
+	ExpireTime      = time.Hour * 24 * 7
+	MaxStarredRepos = 300
+	MaxOrgMembers   = 100
+	MaxOrgRepos     = 20
+	MaxSearchUsers  = 100
 )
 
 // GitHubAPI 结构体
 // 将其当作处理所有有关github账号的中枢,因为它有map
 type GitHubAPI struct {
-	clients expireMap.ExpireMap // 使用 sync.Map 实现并发安全
-	cfg     *conf.GitHubConfig  // 引用的地址完全相同节约了内存空间
+	clients *expireMap.ExpireMap // 使用 sync.Map 实现并发安全
+	cfg     *conf.GitHubConfig   // 引用的地址完全相同节约了内存空间
 }
 
-func NewGitHubAPI(c *conf.GitHubConfig, clients expireMap.ExpireMap) *GitHubAPI {
+func NewGitHubAPI(c *conf.GitHubConfig, clients *expireMap.ExpireMap) *GitHubAPI {
 	return &GitHubAPI{
 		cfg:     c,
 		clients: clients,
@@ -39,16 +46,51 @@ func (g *GitHubAPI) SetClient(userID int64, client *github.Client) {
 }
 
 // GetClientFromMap GetClient 获取用户的 GitHub 客户端
+// 其他平台的用户也存储在同一个map里面,所以需要检查类型
 func (g *GitHubAPI) GetClientFromMap(userID int64) (*github.Client, bool) {
-	client, exists := g.clients.Load(userID) // 使用 Load 方法
-	if exists {
-		return client.(*github.Client), true // 类型断言
+	val, exists := g.clients.Load(userID) // 使用 Load 方法
+	if !exists {
+		return nil, false
 	}
-	return nil, false
+	client, ok := val.(*github.Client) // 类型断言
+	return client, ok
+}
+
+// Name 代码托管平台的名称
+func (g *GitHubAPI) Name() string {
+	return model.ForgeGitHub
+}
+
+// Enabled github 总是可用的
+func (g *GitHubAPI) Enabled() bool {
+	return true
+}
+
+// Authorized 用户的客户端是否还在,过期或者进程重启之后需要重新登录
+func (g *GitHubAPI) Authorized(userID int64) bool {
+	_, exist := g.GetClientFromMap(userID)
+	return exist
+}
+
+// Authorize 使用 code 完成登录,存储用户的客户端并返回用户信息
+func (g *GitHubAPI) Authorize(ctx context.Context, code string) (model.User, error) {
+	client, err := g.GetClientByCode(code)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	userInfo, err := g.GetUserInfo(ctx, client, "")
+	if err != nil {
+		return model.User{}, err
+	}
+
+	//存储用户到内存中去
+	g.SetClient(userInfo.GetID(), client)
+	return model.TransformUser(userInfo), nil
 }
 
 func (g *GitHubAPI) GetLoginUrl() string {
-	redirectURL := "https://github.com/login/oauth/authorize?client_id=" + g.cfg.ClientID + "&scope=user"
+	redirectURL := "https://github.com/login/oauth/authorize?client_id=" + g.cfg.ClientID + "&scope=user&state=" + model.ForgeGitHub
 	return redirectURL
 }
 
@@ -75,69 +117,90 @@ func (g *GitHubAPI) GetUserInfo(ctx context.Context, client *github.Client, user
 	return userInfo, nil
 }
 
-func (g *GitHubAPI) GetFollowing(ctx context.Context, id int64) []model.User {
-	val, exist := g.clients.Load(id)
-	if !exist {
-		log.Println("get github client failed")
-		return []model.User{}
-	}
-	client := val.(*github.Client)
-	users, _, err := client.Users.ListFollowing(ctx, "", nil)
+// GetUserByLogin 根据登录名获取任意github用户的信息,不需要该用户登录过
+func (g *GitHubAPI) GetUserByLogin(ctx context.Context, login string) (model.User, error) {
+	client := g.getClientOrDefault(0)
+	userInfo, _, err := client.Users.Get(ctx, login)
 	if err != nil {
-		log.Println("get github following user failed:", err)
-		return []model.User{}
+		return model.User{}, err
 	}
+	return model.TransformUser(userInfo), nil
+}
 
-	// 获取详细用户信息
-	var detailedUsers []model.User
-	for _, user := range users {
-		detailedUser, _, err := client.Users.Get(ctx, *user.Login)
-		if err != nil {
-			log.Println("get user details failed:", err)
-			continue
-		}
-		detailedUsers = append(detailedUsers, model.TransformUser(detailedUser))
+// GetUserByExternalID 通过github的用户ID获取用户信息,用户改过登录名时仍然可以获取
+func (g *GitHubAPI) GetUserByExternalID(ctx context.Context, id int64) (model.User, error) {
+	userInfo, _, err := g.getClientOrDefault(0).Users.GetByID(ctx, id)
+	if err != nil {
+		return model.User{}, err
 	}
+	return model.TransformUser(userInfo), nil
+}
 
-	return detailedUsers
+func (g *GitHubAPI) GetFollowing(ctx context.Context, id int64) []model.User {
+	return g.getFollowDetails(ctx, id, true)
 }
 
 func (g *GitHubAPI) GetFollowers(ctx context.Context, id int64) []model.User {
-	val, exist := g.clients.Load(id)
-	if !exist {
-		log.Println("get github client failed")
-		return []model.User{}
-	}
-	client := val.(*github.Client)
-	users, _, err := client.Users.ListFollowers(ctx, "", nil)
+	return g.getFollowDetails(ctx, id, false)
+}
+
+// getFollowDetails 获取登录用户的following或者followers的详细信息
+func (g *GitHubAPI) getFollowDetails(ctx context.Context, id int64, following bool) []model.User {
+	users, err := g.ListFollow(ctx, id, following)
 	if err != nil {
-		log.Println("get github followers user failed:", err)
+		log.Println("get github follow user failed:", err)
 		return []model.User{}
 	}
 
 	// 获取详细用户信息
 	var detailedUsers []model.User
 	for _, user := range users {
-		detailedUser, _, err := client.Users.Get(ctx, *user.Login)
+		detailedUser, err := g.GetUser(ctx, id, user.LoginName)
 		if err != nil {
 			log.Println("get user details failed:", err)
 			continue
 		}
-		detailedUsers = append(detailedUsers, model.TransformUser(detailedUser))
+		detailedUsers = append(detailedUsers, detailedUser)
 	}
-
 	return detailedUsers
 }
 
-func (g *GitHubAPI) CalculateScore(ctx context.Context, id int64, name string) float64 {
-	var client = &github.Client{}
-	val, exist := g.clients.Load(id)
+// ListFollow 获取登录用户的following(following为true)或者followers,只包含ID和登录名
+func (g *GitHubAPI) ListFollow(ctx context.Context, id int64, following bool) ([]model.User, error) {
+	client, exist := g.GetClientFromMap(id)
 	if !exist {
-		// 创建一个 GitHub 客户端（无需认证）
-		client = github.NewClient(nil) // nil 表示没有使用任何认证
+		return nil, errors.New("get github client failed")
+	}
+	var (
+		users []*github.User
+		err   error
+	)
+	if following {
+		users, _, err = client.Users.ListFollowing(ctx, "", nil)
 	} else {
-		client = val.(*github.Client)
+		users, _, err = client.Users.ListFollowers(ctx, "", nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]model.User, 0, len(users))
+	for _, user := range users {
+		resp = append(resp, model.User{ID: user.GetID(), LoginName: user.GetLogin()})
+	}
+	return resp, nil
+}
+
+// GetUser 使用登录用户的客户端获取任意用户的详细信息
+func (g *GitHubAPI) GetUser(ctx context.Context, id int64, login string) (model.User, error) {
+	userInfo, _, err := g.getClientOrDefault(id).Users.Get(ctx, login)
+	if err != nil {
+		return model.User{}, err
 	}
+	return model.TransformUser(userInfo), nil
+}
+
+func (g *GitHubAPI) CalculateScore(ctx context.Context, id int64, name string) float64 {
+	client := g.getClientOrDefault(id)
 	repos, _, err := client.Repositories.List(ctx, name, nil)
 	if err != nil {
 		log.Printf("Error getting repositories: %v\n", err)
@@ -148,6 +211,44 @@ func (g *GitHubAPI) CalculateScore(ctx context.Context, id int64, name string) f
 	return score
 }
 
+// GetStarredRepositories 获取用户star过的仓库,包括topics,语言和star数
+// 没有登录的用户会使用无认证的客户端,最多获取 MaxStarredRepos 个
+func (g *GitHubAPI) GetStarredRepositories(ctx context.Context, loginName string, userId int64) []*model.StarredRepo {
+	client := g.getClientOrDefault(userId)
+	opt := &github.ActivityListStarredOptions{
+		Sort:        "created",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var resp []*model.StarredRepo
+	for {
+		starred, r, err := client.Activity.ListStarred(ctx, loginName, opt)
+		if err != nil {
+			log.Printf("Error getting starred repositories: %v\n", err)
+			return resp
+		}
+		for _, v := range starred {
+			repo := v.GetRepository()
+			if repo == nil {
+				continue
+			}
+			resp = append(resp, &model.StarredRepo{
+				Name:            repo.GetFullName(),
+				Language:        repo.GetLanguage(),
+				Topics:          repo.Topics,
+				StargazersCount: repo.GetStargazersCount(),
+			})
+		}
+		//star过多的话只取最近的一部分
+		if r.NextPage == 0 || len(resp) >= MaxStarredRepos {
+			break
+		}
+		opt.Page = r.NextPage
+	}
+	return resp
+}
+
 // GetReposDetailList 根据仓库链接获取仓库的详细信息列表
 func (g *GitHubAPI) GetRepoDetail(ctx context.Context, repoUrl string, client *github.Client) (*github.Repository, error) {
 
@@ -169,42 +270,68 @@ func (g *GitHubAPI) GetRepoDetail(ctx context.Context, repoUrl string, client *g
 // GetAllRepositories 获取用户的所有仓库信息
 // 接受用户的昵称和userID,返回所有仓库信息
 func (g *GitHubAPI) GetAllRepositories(ctx context.Context, loginName string, userId int64) []*model.Repo {
-	v, exist := g.clients.Load(userId)
-	if !exist {
-		log.Println("get github client failed")
+	repos, err := g.ListRepositories(ctx, loginName, userId)
+	if err != nil {
+		log.Printf("Error getting repositories: %v\n", err)
 		return nil
 	}
-	client := v.(*github.Client)
+	for _, repo := range repos {
+		g.FillRepository(ctx, loginName, userId, repo)
+	}
+	return repos
+}
+
+// ListRepositories 获取用户最近创建的20个仓库,只包括名称,语言和最近推送的时间
+func (g *GitHubAPI) ListRepositories(ctx context.Context, loginName string, userId int64) ([]*model.Repo, error) {
+	client := g.getClientOrDefault(userId)
 	repos, _, err := client.Repositories.List(ctx, loginName, &github.RepositoryListOptions{
 		Sort:        "created",                       // 按创建时间排序
 		Direction:   "desc",                          // 降序排列，从新到旧
 		ListOptions: github.ListOptions{PerPage: 20}, // 每页最多获取20个
 	})
 	if err != nil {
-		log.Printf("Error getting repositories: %v\n", err)
-		return nil
+		return nil, err
 	}
-	var resp []*model.Repo
+	resp := make([]*model.Repo, 0, len(repos))
 	for _, repo := range repos {
-		//尝试获取每个仓库的Readme
-		me, err := g.GetReadMe(ctx, repo.GetURL(), client)
-		if err != nil {
-			log.Println("get github readme failed:", err)
-		}
 		resp = append(resp, &model.Repo{
 			Name:     repo.GetName(),
-			Readme:   me,
 			Language: repo.GetLanguage(),
-			Commit:   g.getCommitsCount(ctx, loginName, client, repo.GetName()),
+			PushedAt: repo.GetPushedAt().Time,
+			URL:      repo.GetURL(),
 		})
 	}
-	return resp
+	return resp, nil
+}
+
+// FillRepository 获取仓库的readme和用户的提交数
+// 仓库的所有者从仓库的API地址中获取,存储的登录名可能已经过期
+func (g *GitHubAPI) FillRepository(ctx context.Context, loginName string, userId int64, repo *model.Repo) {
+	client := g.getClientOrDefault(userId)
+	repoURL := repo.URL
+	if repoURL == "" {
+		//之前存储的检查点中没有API地址
+		repoURL = "https://api.github.com/repos/" + loginName + "/" + repo.Name
+	}
+	owner, name, err := g.parseRepoURL(repoURL)
+	if err != nil {
+		log.Println("parse github repo url failed:", err)
+		return
+	}
+	//尝试获取每个仓库的Readme
+	me, err := g.GetReadMe(ctx, repoURL, client)
+	if err != nil {
+		log.Println("get github readme failed:", err)
+	}
+	repo.Readme = me
+	repo.Commit = g.getCommitsCount(ctx, owner, loginName, client, name)
 }
 
-func (g *GitHubAPI) getCommitsCount(ctx context.Context, loginName string, client *github.Client, repoName string) int32 {
+// getCommitsCount 统计author在owner的仓库中最近100个提交中的提交数
+func (g *GitHubAPI) getCommitsCount(ctx context.Context, owner, loginName string, client *github.Client, repoName string) int32 {
 
 	// 获取指定仓库的提交记录
-	commits, _, err := client.Repositories.ListCommits(ctx, loginName, repoName, &github.CommitsListOptions{
+	commits, _, err := client.Repositories.ListCommits(ctx, owner, repoName, &github.CommitsListOptions{
 		SHA:         "",          // 空字符串表示获取默认分支的提交记录
 		Since:       time.Time{}, // 不设置过滤条件，获取所有提交
 		Until:       time.Time{},
@@ -247,13 +374,13 @@ func (g *GitHubAPI) GetReadMe(ctx context.Context, repoUrl string, client *githu
 	return string(content), nil
 }
 
-func (g *GitHubAPI) GetOrganizations(ctx context.Context, userID int64) ([]*github.Organization, error) {
-	val, exist := g.clients.Load(userID)
+// GetOrganizations 获取登录用户所属的组织,并补全组织的详细信息
+func (g *GitHubAPI) GetOrganizations(ctx context.Context, userID int64) ([]model.Organization, error) {
+	client, exist := g.GetClientFromMap(userID)
 	if !exist {
 		log.Println("get github client failed")
 		return nil, fmt.Errorf("github client not found for user ID %d", userID)
 	}
-	client := val.(*github.Client)
 
 	// 获取组织列表
 	orgs, _, err := client.Organizations.List(ctx, "", nil)
@@ -262,24 +389,148 @@ func (g *GitHubAPI) GetOrganizations(ctx context.Context, userID int64) ([]*gith
 		return nil, err
 	}
 
-	return orgs, nil
+	// 列表接口只返回了简略信息
+	var detailedOrgs []model.Organization
+	for _, org := range orgs {
+		detailedOrg, _, err := client.Organizations.Get(ctx, org.GetLogin())
+		if err != nil {
+			log.Println("get organization details failed:", err)
+			continue
+		}
+		detailedOrgs = append(detailedOrgs, model.TransformOrganization(detailedOrg))
+	}
+
+	return detailedOrgs, nil
 }
 
-func (g *GitHubAPI) GetAllUserEvents(ctx context.Context, username string, client *github.Client) ([]model.UserEvent, error) {
+// GetOrganization 根据组织的登录名获取组织的详细信息
+func (g *GitHubAPI) GetOrganization(ctx context.Context, login string, userID int64) (model.Organization, error) {
+	client := g.getClientOrDefault(userID)
+	org, _, err := client.Organizations.Get(ctx, login)
+	if err != nil {
+		log.Println("Error getting organization:", err)
+		return model.Organization{}, err
+	}
+	return model.TransformOrganization(org), nil
+}
+
+// GetOrganizationMembers 获取组织公开的成员,最多获取 MaxOrgMembers 个
+// 列出成员失败时返回错误,获取单个成员的详情失败时跳过这个成员
+func (g *GitHubAPI) GetOrganizationMembers(ctx context.Context, login string, userID int64) ([]model.User, error) {
+	client := g.getClientOrDefault(userID)
+	opt := &github.ListMembersOptions{
+		PublicOnly:  true,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var members []*github.User
+	for {
+		users, resp, err := client.Organizations.ListMembers(ctx, login, opt)
+		if err != nil {
+			log.Println("get organization members failed:", err)
+			return nil, err
+		}
+		members = append(members, users...)
+		if resp.NextPage == 0 || len(members) >= MaxOrgMembers {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	if len(members) > MaxOrgMembers {
+		members = members[:MaxOrgMembers]
+	}
+
+	// 获取详细用户信息
+	var detailedUsers []model.User
+	for _, user := range members {
+		detailedUser, _, err := client.Users.Get(ctx, user.GetLogin())
+		if err != nil {
+			log.Println("get user details failed:", err)
+			continue
+		}
+		detailedUsers = append(detailedUsers, model.TransformUser(detailedUser))
+	}
+
+	return detailedUsers, nil
+}
+
+// GetOrganizationRepos 获取组织的公开仓库,按照star数排序后只保留前 MaxOrgRepos 个
+func (g *GitHubAPI) GetOrganizationRepos(ctx context.Context, org model.Organization, userID int64) []model.OrgRepo {
+	client := g.getClientOrDefault(userID)
+	opt := &github.RepositoryListByOrgOptions{
+		Type:        "public",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var repos []*github.Repository
+	for {
+		r, resp, err := client.Repositories.ListByOrg(ctx, org.Login, opt)
+		if err != nil {
+			log.Println("get organization repos failed:", err)
+			break
+		}
+		repos = append(repos, r...)
+		//仓库过多的话就不再继续获取了
+		if resp.NextPage == 0 || len(repos) >= 1000 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].GetStargazersCount() > repos[j].GetStargazersCount()
+	})
+	if len(repos) > MaxOrgRepos {
+		repos = repos[:MaxOrgRepos]
+	}
+
+	resp := make([]model.OrgRepo, 0, len(repos))
+	for _, repo := range repos {
+		resp = append(resp, model.TransformOrgRepo(org.ID, repo))
+	}
+	return resp
+}
+
+// GetAllUserEvents 获取登录用户的所有事件,按照仓库分类统计
+func (g *GitHubAPI) GetAllUserEvents(ctx context.Context, username string, userId int64) ([]model.UserEvent, error) {
+	events, _, err := g.GetUserEventsSince(ctx, username, userId, model.EventCursor{})
+	if err != nil {
+		return nil, err
+	}
+	return model.SumUserEventsByRepo(events), nil
+}
+
+// GetUserEventsSince 获取登录用户比cursor新的事件,按照仓库和日期分类统计,同时返回新的游标
+// 事件从新到旧返回,遇到已经处理过的事件就停止翻页,没有新的事件时只需要一次请求
+func (g *GitHubAPI) GetUserEventsSince(ctx context.Context, username string, userId int64, cursor model.EventCursor) ([]model.UserEvent, model.EventCursor, error) {
+	client, exist := g.GetClientFromMap(userId)
+	if !exist {
+		return nil, cursor, errors.New("login fail!")
+	}
 	allEvents := make([]*github.Event, 0)
 
 	// 分页设置
 	opt := &github.ListOptions{PerPage: 100}
 
 	// 循环获取所有用户事件
+pages:
 	for {
 		// 获取用户事件
 		events, resp, err := client.Activity.ListEventsPerformedByUser(ctx, username, false, opt)
 		if err != nil {
-			return nil, err // 返回nil而不是UserEvent{}，因为我们要返回切片
+			return nil, cursor, err // 返回nil而不是UserEvent{}，因为我们要返回切片
 		}
 
-		allEvents = append(allEvents, events...)
+		for _, event := range events {
+			id, err := strconv.ParseInt(event.GetID(), 10, 64)
+			if err != nil {
+				continue
+			}
+			if id <= cursor.ID {
+				break pages
+			}
+			allEvents = append(allEvents, event)
+		}
 
 		// 如果没有更多页面，则退出循环
 		if resp.NextPage == 0 {
@@ -292,28 +543,39 @@ func (g *GitHubAPI) GetAllUserEvents(ctx context.Context, username string, clien
 		// 更新分页选项以请求下一页
 		opt.Page = resp.NextPage
 	}
+	if len(allEvents) == 0 {
+		return nil, cursor, nil
+	}
+	//第一个事件是最新的
+	next := cursor
+	next.ID, _ = strconv.ParseInt(allEvents[0].GetID(), 10, 64)
+	if at := allEvents[0].GetCreatedAt().Time; !at.IsZero() {
+		next.At = &at
+	}
 
-	// 使用一个映射来分类不同的UserEvent
-	userEventsMap := make(map[string]*model.UserEvent)
+	// 使用一个映射来分类不同的UserEvent,键是仓库名和日期
+	userEventsMap := make(map[[2]string]*model.UserEvent)
 	info, err := g.getUserAllRepoInfo(ctx, client, "")
 	if err != nil {
-		return nil, err
+		return nil, cursor, err
 	}
 
 	for _, event := range allEvents {
 		repoName := event.Repo.GetName()
+		day := event.GetCreatedAt().UTC().Format(time.DateOnly)
+		key := [2]string{repoName, day}
 
 		// 如果该repo的UserEvent还未创建，则初始化
-		if _, exists := userEventsMap[repoName]; !exists {
+		if _, exists := userEventsMap[key]; !exists {
 			//尝试初始化
-			userEventsMap[repoName] = &model.UserEvent{Repo: model.RepoInfo{Name: repoName}}
+			userEventsMap[key] = &model.UserEvent{Repo: model.RepoInfo{Name: repoName}, Day: day}
 			//如果存在于用户的仓库中则直接完全初始化这个仓库
 			if _, ok := info[repoName]; ok {
-				userEventsMap[repoName].Repo = *info[repoName]
+				userEventsMap[key].Repo = *info[repoName]
 			}
 		}
 
-		userEvent := userEventsMap[repoName] // 获取当前repo的UserEvent实例
+		userEvent := userEventsMap[key] // 获取当前repo的UserEvent实例
 		switch event.GetType() {
 		case "PushEvent":
 			// 更新提交计数
@@ -336,7 +598,36 @@ func (g *GitHubAPI) GetAllUserEvents(ctx context.Context, username string, clien
 		userEventsSlice = append(userEventsSlice, *userEvent) // 将指针解引用
 	}
 
-	return userEventsSlice, nil
+	return userEventsSlice, next, nil
+}
+
+// GetPublicUserEvents 使用服务端的客户端获取用户最近100个公开事件,按照仓库分类统计
+// 只请求一次,不获取仓库的详细信息,用于对比等不需要完整信息的场景
+func (g *GitHubAPI) GetPublicUserEvents(ctx context.Context, username string) ([]model.UserEvent, error) {
+	events, _, err := g.getClientOrDefault(0).Activity.ListEventsPerformedByUser(ctx, username, true, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, err
+	}
+	userEventsMap := make(map[string]*model.UserEvent)
+	for _, event := range events {
+		repoName := event.Repo.GetName()
+		if _, exists := userEventsMap[repoName]; !exists {
+			userEventsMap[repoName] = &model.UserEvent{Repo: model.RepoInfo{Name: repoName}}
+		}
+		switch event.GetType() {
+		case "PushEvent":
+			userEventsMap[repoName].PushCount++
+		case "IssuesEvent":
+			userEventsMap[repoName].IssuesCount++
+		case "PullRequestEvent":
+			userEventsMap[repoName].PullRequestCount++
+		}
+	}
+	resp := make([]model.UserEvent, 0, len(userEventsMap))
+	for _, userEvent := range userEventsMap {
+		resp = append(resp, *userEvent)
+	}
+	return resp, nil
 }
 
 func (g *GitHubAPI) getUserAllRepoInfo(ctx context.Context, client *github.Client, username string) (map[string]*model.RepoInfo, error) {
@@ -381,6 +672,135 @@ func (g *GitHubAPI) getUserAllRepoInfo(ctx context.Context, client *github.Clien
 	return repoMap, nil
 }
 
+// ListFollowByLogin 获取任意用户的following(following为true)或者followers,最多limit个
+// 只包含ID和登录名,同时返回发出的请求数,便于调用方控制github的额度
+func (g *GitHubAPI) ListFollowByLogin(ctx context.Context, login string, following bool, limit int) ([]model.User, int, error) {
+	var (
+		client   = g.getClientOrDefault(0)
+		opt      = &github.ListOptions{PerPage: 100}
+		users    []*github.User
+		requests int
+	)
+	for {
+		var (
+			page []*github.User
+			resp *github.Response
+			err  error
+		)
+		if following {
+			page, resp, err = client.Users.ListFollowing(ctx, login, opt)
+		} else {
+			page, resp, err = client.Users.ListFollowers(ctx, login, opt)
+		}
+		requests++
+		if err != nil {
+			return nil, requests, err
+		}
+		users = append(users, page...)
+		if resp.NextPage == 0 || len(users) >= limit {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	if len(users) > limit {
+		users = users[:limit]
+	}
+
+	resp := make([]model.User, 0, len(users))
+	for _, user := range users {
+		resp = append(resp, model.User{ID: user.GetID(), LoginName: user.GetLogin()})
+	}
+	return resp, requests, nil
+}
+
+// BuildUserSearchQuery 拼接github用户搜索的查询语句,例如 type:user location:Berlin language:Go followers:>100
+func (g *GitHubAPI) BuildUserSearchQuery(keyword, location, language string, minFollowers int) string {
+	parts := []string{"type:user"}
+	if keyword != "" {
+		parts = append(parts, keyword)
+	}
+	if location != "" {
+		parts = append(parts, "location:"+quoteQualifier(location))
+	}
+	if language != "" {
+		parts = append(parts, "language:"+quoteQualifier(language))
+	}
+	if minFollowers > 0 {
+		parts = append(parts, fmt.Sprintf("followers:>%d", minFollowers))
+	}
+	return strings.Join(parts, " ")
+}
+
+// SearchUsers 使用github的搜索接口搜索用户,最多返回limit个(不超过 MaxSearchUsers)
+// 只包含ID和登录名,同时返回github上匹配的总数
+func (g *GitHubAPI) SearchUsers(ctx context.Context, query string, limit int) ([]model.User, int, error) {
+	if limit <= 0 || limit > MaxSearchUsers {
+		limit = MaxSearchUsers
+	}
+	client := g.getClientOrDefault(0)
+	opt := &github.SearchOptions{
+		Sort:        "followers",
+		Order:       "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var (
+		users []*github.User
+		total int
+	)
+	for {
+		result, resp, err := client.Search.Users(ctx, query, opt)
+		if err != nil {
+			return nil, 0, err
+		}
+		total = result.GetTotal()
+		users = append(users, result.Users...)
+		if resp.NextPage == 0 || len(users) >= limit {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	if len(users) > limit {
+		users = users[:limit]
+	}
+
+	resp := make([]model.User, 0, len(users))
+	for _, user := range users {
+		resp = append(resp, model.User{ID: user.GetID(), LoginName: user.GetLogin()})
+	}
+	return resp, total, nil
+}
+
+// GetRateRemaining 获取服务端客户端剩余的github core额度
+func (g *GitHubAPI) GetRateRemaining(ctx context.Context) (int, error) {
+	limits, _, err := g.getClientOrDefault(0).RateLimits(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return limits.GetCore().Remaining, nil
+}
+
+// getClientOrDefault 优先使用用户自己的客户端,不存在的话使用服务端配置的token创建客户端
+// 没有配置token时创建一个 GitHub 客户端（无需认证）
+func (g *GitHubAPI) getClientOrDefault(userID int64) *github.Client {
+	if client, exist := g.GetClientFromMap(userID); exist {
+		return client
+	}
+	if g.cfg.Token != "" {
+		ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: g.cfg.Token})
+		return github.NewClient(oauth2.NewClient(context.Background(), ts))
+	}
+	return github.NewClient(nil) // nil 表示没有使用任何认证
+}
+
+// quoteQualifier 带空格的限定词需要加上引号,例如 location:"New York"
+func quoteQualifier(v string) string {
+	if strings.ContainsAny(v, " \t") {
+		return `"` + v + `"`
+	}
+	return v
+}
+
 // parseRepoURL 从仓库链接中解析出用户名和仓库名
 func (g *GitHubAPI) parseRepoURL(url string) (owner, repo string, err error) {
 	parts := strings.Split(strings.TrimPrefix(url, "https://api.github.com/repos/"), "/")
@@ -0,0 +1,354 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/conf"
+	"github.com/GitEval/GitEval-Backend/model"
+	"github.com/GitEval/GitEval-Backend/pkg/github/expireMap"
+	"golang.org/x/oauth2"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ExpireTime = time.Hour * 24 * 7
+	// MaxEvents 事件过多的话就做限流
+	MaxEvents = 2000
+)
+
+// GitLabAPI gitlab的实现,直接调用 REST API v4
+// 和github共用一个map存储登录用户的客户端,用户ID由 model.ForgeUserID 得到,不会冲突
+type GitLabAPI struct {
+	clients *expireMap.ExpireMap
+	cfg     *conf.GitLabConfig
+	oauth   *oauth2.Config
+}
+
+// session 登录用户的客户端
+type session struct {
+	client     *http.Client
+	externalID int64
+}
+
+func NewGitLabAPI(c *conf.GitLabConfig, clients *expireMap.ExpireMap) *GitLabAPI {
+	base := strings.TrimSuffix(c.BaseURL, "/")
+	return &GitLabAPI{
+		cfg:     c,
+		clients: clients,
+		oauth: &oauth2.Config{
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			RedirectURL:  c.RedirectURL,
+			Scopes:       []string{"read_user", "read_api"},
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  base + "/oauth/authorize",
+				TokenURL: base + "/oauth/token",
+			},
+		},
+	}
+}
+
+// Name 代码托管平台的名称
+func (g *GitLabAPI) Name() string {
+	return model.ForgeGitLab
+}
+
+// Enabled 没有配置clientID时不启用
+func (g *GitLabAPI) Enabled() bool {
+	return g.cfg.ClientID != ""
+}
+
+// Authorized 用户的客户端是否还在,过期或者进程重启之后需要重新登录
+func (g *GitLabAPI) Authorized(userID int64) bool {
+	_, exist := g.getSession(userID)
+	return exist
+}
+
+func (g *GitLabAPI) GetLoginUrl() string {
+	return g.oauth.AuthCodeURL(model.ForgeGitLab)
+}
+
+// Authorize 使用 code 完成登录,存储用户的客户端并返回用户信息
+func (g *GitLabAPI) Authorize(ctx context.Context, code string) (model.User, error) {
+	token, err := g.oauth.Exchange(ctx, code)
+	if err != nil {
+		return model.User{}, err
+	}
+	// 这个客户端会自动刷新token
+	client := g.oauth.Client(context.Background(), token)
+
+	var u user
+	if _, err := g.get(ctx, client, "/user", nil, &u); err != nil {
+		return model.User{}, err
+	}
+
+	res := u.transform()
+	g.clients.Store(res.ID, &session{client: client, externalID: u.ID}, ExpireTime)
+	return res, nil
+}
+
+func (g *GitLabAPI) GetFollowing(ctx context.Context, id int64) []model.User {
+	return g.listFollow(ctx, id, "following")
+}
+
+func (g *GitLabAPI) GetFollowers(ctx context.Context, id int64) []model.User {
+	return g.listFollow(ctx, id, "followers")
+}
+
+func (g *GitLabAPI) CalculateScore(ctx context.Context, id int64, name string) float64 {
+	client := g.getClientOrDefault(id)
+	var projects []project
+	if _, err := g.get(ctx, client, "/users/"+url.PathEscape(name)+"/projects", url.Values{"per_page": {"100"}}, &projects); err != nil {
+		log.Printf("Error getting gitlab projects: %v\n", err)
+		return 0
+	}
+	return calculateScore(projects)
+}
+
+// GetAllRepositories 获取用户最近创建的20个仓库,包括readme,主要语言和用户的提交数
+func (g *GitLabAPI) GetAllRepositories(ctx context.Context, loginName string, userId int64) []*model.Repo {
+	client := g.getClientOrDefault(userId)
+	var projects []project
+	_, err := g.get(ctx, client, "/users/"+url.PathEscape(loginName)+"/projects", url.Values{
+		"order_by": {"created_at"},
+		"sort":     {"desc"},
+		"per_page": {"20"},
+	}, &projects)
+	if err != nil {
+		log.Printf("Error getting gitlab projects: %v\n", err)
+		return nil
+	}
+
+	var resp []*model.Repo
+	for _, p := range projects {
+		resp = append(resp, &model.Repo{
+			Name:     p.Name,
+			Readme:   g.getReadme(ctx, client, p),
+			Language: g.getLanguage(ctx, client, p.ID),
+			Commit:   g.getCommitsCount(ctx, client, p.ID, loginName),
+		})
+	}
+	return resp
+}
+
+func (g *GitLabAPI) GetStarredRepositories(ctx context.Context, loginName string, userId int64) []*model.StarredRepo {
+	client := g.getClientOrDefault(userId)
+	var projects []project
+	_, err := g.get(ctx, client, "/users/"+url.PathEscape(loginName)+"/starred_projects", url.Values{"per_page": {"100"}}, &projects)
+	if err != nil {
+		log.Printf("Error getting gitlab starred projects: %v\n", err)
+		return nil
+	}
+
+	resp := make([]*model.StarredRepo, 0, len(projects))
+	for _, p := range projects {
+		resp = append(resp, &model.StarredRepo{
+			Name:            p.PathWithNamespace,
+			Topics:          p.Topics,
+			StargazersCount: p.StarCount,
+		})
+	}
+	return resp
+}
+
+// GetAllUserEvents 获取登录用户的所有事件,按照仓库分类统计
+func (g *GitLabAPI) GetAllUserEvents(ctx context.Context, username string, userId int64) ([]model.UserEvent, error) {
+	s, exist := g.getSession(userId)
+	if !exist {
+		return nil, errors.New("login fail!")
+	}
+
+	var (
+		allEvents []event
+		query     = url.Values{"per_page": {"100"}}
+	)
+	for {
+		var events []event
+		next, err := g.get(ctx, s.client, fmt.Sprintf("/users/%d/events", s.externalID), query, &events)
+		if err != nil {
+			return nil, err
+		}
+		allEvents = append(allEvents, events...)
+		if next == "" || len(allEvents) >= MaxEvents {
+			break
+		}
+		query.Set("page", next)
+	}
+
+	userEventsMap := make(map[int64]*model.UserEvent)
+	for _, e := range allEvents {
+		if e.ProjectID == 0 {
+			continue
+		}
+		userEvent, exists := userEventsMap[e.ProjectID]
+		if !exists {
+			userEvent = &model.UserEvent{Repo: g.getRepoInfo(ctx, s.client, e.ProjectID)}
+			userEventsMap[e.ProjectID] = userEvent
+		}
+		switch {
+		case strings.HasPrefix(e.ActionName, "pushed"):
+			userEvent.PushCount++
+		case e.TargetType == "Issue" && e.ActionName == "opened":
+			userEvent.IssuesCount++
+		case e.TargetType == "MergeRequest" && e.ActionName == "opened":
+			userEvent.PullRequestCount++
+		}
+	}
+
+	userEventsSlice := make([]model.UserEvent, 0, len(userEventsMap))
+	for _, userEvent := range userEventsMap {
+		userEventsSlice = append(userEventsSlice, *userEvent)
+	}
+	return userEventsSlice, nil
+}
+
+func (g *GitLabAPI) listFollow(ctx context.Context, id int64, kind string) []model.User {
+	s, exist := g.getSession(id)
+	if !exist {
+		log.Println("get gitlab client failed")
+		return []model.User{}
+	}
+	var users []user
+	if _, err := g.get(ctx, s.client, fmt.Sprintf("/users/%d/%s", s.externalID, kind), url.Values{"per_page": {"100"}}, &users); err != nil {
+		log.Printf("get gitlab %s user failed: %v\n", kind, err)
+		return []model.User{}
+	}
+
+	// 获取详细用户信息
+	var detailedUsers []model.User
+	for _, u := range users {
+		var detailed user
+		if _, err := g.get(ctx, s.client, fmt.Sprintf("/users/%d", u.ID), nil, &detailed); err != nil {
+			log.Println("get user details failed:", err)
+			continue
+		}
+		detailedUsers = append(detailedUsers, detailed.transform())
+	}
+	return detailedUsers
+}
+
+func (g *GitLabAPI) getRepoInfo(ctx context.Context, client *http.Client, projectID int64) model.RepoInfo {
+	var p project
+	if _, err := g.get(ctx, client, fmt.Sprintf("/projects/%d", projectID), nil, &p); err != nil {
+		return model.RepoInfo{Name: strconv.FormatInt(projectID, 10)}
+	}
+	return model.RepoInfo{
+		Name:            p.PathWithNamespace,
+		Description:     p.Description,
+		StargazersCount: p.StarCount,
+		ForksCount:      p.ForksCount,
+		CreatedAt:       p.CreatedAt,
+	}
+}
+
+func (g *GitLabAPI) getReadme(ctx context.Context, client *http.Client, p project) string {
+	if p.DefaultBranch == "" {
+		return ""
+	}
+	body, _, err := g.do(ctx, client, fmt.Sprintf("/projects/%d/repository/files/README.md/raw", p.ID), url.Values{"ref": {p.DefaultBranch}})
+	if err != nil {
+		return ""
+	}
+	return string(body)
+}
+
+// getLanguage gitlab返回的是每种语言的占比,取占比最大的
+func (g *GitLabAPI) getLanguage(ctx context.Context, client *http.Client, projectID int64) string {
+	var languages map[string]float64
+	if _, err := g.get(ctx, client, fmt.Sprintf("/projects/%d/languages", projectID), nil, &languages); err != nil {
+		return ""
+	}
+	var (
+		lang string
+		max  float64
+	)
+	for k, v := range languages {
+		if v > max {
+			lang, max = k, v
+		}
+	}
+	return lang
+}
+
+func (g *GitLabAPI) getCommitsCount(ctx context.Context, client *http.Client, projectID int64, loginName string) int32 {
+	var commits []json.RawMessage
+	_, err := g.get(ctx, client, fmt.Sprintf("/projects/%d/repository/commits", projectID), url.Values{
+		"author":   {loginName},
+		"per_page": {"100"},
+	}, &commits)
+	if err != nil {
+		return 0
+	}
+	return int32(len(commits))
+}
+
+func (g *GitLabAPI) getSession(userID int64) (*session, bool) {
+	val, exist := g.clients.Load(userID)
+	if !exist {
+		return nil, false
+	}
+	s, ok := val.(*session)
+	return s, ok
+}
+
+// getClientOrDefault 优先使用用户自己的客户端,否则使用无认证的客户端
+func (g *GitLabAPI) getClientOrDefault(userID int64) *http.Client {
+	if s, exist := g.getSession(userID); exist {
+		return s.client
+	}
+	return http.DefaultClient
+}
+
+// get 请求 API 并解析 json,返回下一页的页码,没有下一页时为空
+func (g *GitLabAPI) get(ctx context.Context, client *http.Client, path string, query url.Values, v any) (string, error) {
+	body, header, err := g.do(ctx, client, path, query)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return "", err
+	}
+	return header.Get("X-Next-Page"), nil
+}
+
+func (g *GitLabAPI) do(ctx context.Context, client *http.Client, path string, query url.Values) ([]byte, http.Header, error) {
+	u := strings.TrimSuffix(g.cfg.BaseURL, "/") + "/api/v4" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("gitlab %s: %s", path, resp.Status)
+	}
+	return body, resp.Header, nil
+}
+
+// 具体的计算逻辑,和github保持一致,gitlab的列表接口拿不到仓库大小,所以不计算这一项
+func calculateScore(projects []project) float64 {
+	var totalScore float64
+	for _, p := range projects {
+		totalScore += float64(p.StarCount)*0.6 + float64(p.ForksCount)*0.9 + float64(p.OpenIssuesCount)*2 + 1
+	}
+	return totalScore
+}
@@ -0,0 +1,54 @@
+package gitlab
+
+import "github.com/GitEval/GitEval-Backend/model"
+
+type user struct {
+	ID           int64  `json:"id"`
+	Username     string `json:"username"`
+	Name         string `json:"name"`
+	Location     string `json:"location"`
+	PublicEmail  string `json:"public_email"`
+	Bio          string `json:"bio"`
+	AvatarURL    string `json:"avatar_url"`
+	WebsiteURL   string `json:"website_url"`
+	Organization string `json:"organization"`
+	Followers    int    `json:"followers"`
+	Following    int    `json:"following"`
+}
+
+type project struct {
+	ID                int64    `json:"id"`
+	Name              string   `json:"name"`
+	PathWithNamespace string   `json:"path_with_namespace"`
+	Description       string   `json:"description"`
+	StarCount         int      `json:"star_count"`
+	ForksCount        int      `json:"forks_count"`
+	OpenIssuesCount   int      `json:"open_issues_count"`
+	CreatedAt         string   `json:"created_at"`
+	DefaultBranch     string   `json:"default_branch"`
+	Topics            []string `json:"topics"`
+}
+
+type event struct {
+	ProjectID  int64  `json:"project_id"`
+	ActionName string `json:"action_name"`
+	TargetType string `json:"target_type"`
+}
+
+func (u user) transform() model.User {
+	return model.User{
+		ID:         model.ForgeUserID(model.ForgeGitLab, u.ID),
+		Forge:      model.ForgeGitLab,
+		ExternalID: u.ID,
+		LoginName:  u.Username,
+		Name:       u.Name,
+		Location:   u.Location,
+		Email:      u.PublicEmail,
+		Bio:        u.Bio,
+		AvatarURL:  u.AvatarURL,
+		Blog:       u.WebsiteURL,
+		Company:    u.Organization,
+		Followers:  u.Followers,
+		Following:  u.Following,
+	}
+}
@@ -1,12 +1,20 @@
 package pkg
 
 import (
+	"github.com/GitEval/GitEval-Backend/pkg/fallback"
+	"github.com/GitEval/GitEval-Backend/pkg/gitea"
 	"github.com/GitEval/GitEval-Backend/pkg/github"
 	"github.com/GitEval/GitEval-Backend/pkg/github/expireMap"
+	"github.com/GitEval/GitEval-Backend/pkg/gitlab"
+	"github.com/GitEval/GitEval-Backend/pkg/vector"
 	"github.com/google/wire"
 )
 
 var ProviderSet = wire.NewSet(
 	github.NewGitHubAPI,
+	gitlab.NewGitLabAPI,
+	gitea.NewGiteaAPI,
+	fallback.NewInferrer,
+	vector.NewIndex,
 	expireMap.NewExpireMap, //github
 )
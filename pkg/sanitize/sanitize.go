@@ -0,0 +1,156 @@
+package sanitize
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// 在交给llm之前清洗仓库的README和用户填写的文本
+// README的噪声很多(徽章,html,代码),而且可能包含针对llm的指令,直接放进prompt会超出上下文或者影响推断的结果
+
+var (
+	htmlComment   = regexp.MustCompile(`(?s)<!--.*?-->`)
+	linkedImage   = regexp.MustCompile(`\[!\[[^\]]*\]\([^)]*\)\]\([^)]*\)`)
+	image         = regexp.MustCompile(`!\[[^\]]*\](\([^)]*\)|\[[^\]]*\])`)
+	refDefinition = regexp.MustCompile(`(?m)^\s*\[[^\]]+\]:\s*\S+.*$`)
+	htmlTag       = regexp.MustCompile(`</?[a-zA-Z][^>\n]*>`)
+	link          = regexp.MustCompile(`\[([^\]]+)\](\([^)]*\)|\[[^\]]*\])`)
+	inlineCode    = regexp.MustCompile("`([^`\n]*)`")
+	rule          = regexp.MustCompile(`^\s*([-*_=|:]\s*){3,}$`)
+	spaces        = regexp.MustCompile(`[ \t]+`)
+)
+
+// Readme 去掉html,徽章,图片和代码块,中和类似指令的内容,最后截断到tokens个token以内
+func Readme(s string, tokens int) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = stripCodeBlocks(s)
+	s = htmlComment.ReplaceAllString(s, "")
+	s = linkedImage.ReplaceAllString(s, "")
+	s = image.ReplaceAllString(s, "")
+	s = refDefinition.ReplaceAllString(s, "")
+	s = htmlTag.ReplaceAllString(s, "")
+	s = link.ReplaceAllString(s, "$1")
+	s = inlineCode.ReplaceAllString(s, "$1")
+	s = html.UnescapeString(s)
+	return Truncate(compact(Neutralize(s)), tokens)
+}
+
+// Text 清洗简介和描述之类的短文本,只中和类似指令的内容
+func Text(s string) string {
+	return strings.TrimSpace(Neutralize(s))
+}
+
+// Texts 逐个清洗短文本,返回新的切片
+func Texts(s []string) []string {
+	out := make([]string, 0, len(s))
+	for _, v := range s {
+		out = append(out, Text(v))
+	}
+	return out
+}
+
+// stripCodeBlocks 去掉 ``` 和 ~~~ 包裹的代码块,没有闭合的代码块去掉到结尾
+func stripCodeBlocks(s string) string {
+	var (
+		sb    strings.Builder
+		fence string
+	)
+	for _, line := range strings.Split(s, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")) {
+			fence = trimmed[:3]
+			continue
+		}
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// compact 去掉分割线和多余的空白,连续的空行合并成一个
+func compact(s string) string {
+	var lines []string
+	blank := true
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(spaces.ReplaceAllString(line, " "))
+		if rule.MatchString(line) {
+			line = ""
+		}
+		if line == "" {
+			if !blank {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+		lines = append(lines, line)
+		blank = false
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// EstimateTokens 粗略估计token数,英文大约4个字符一个token,中文等非ascii字符按一个字一个token
+func EstimateTokens(s string) int {
+	ascii, other := 0, 0
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// Truncate 按段落保留开头的内容直到用完tokens,放不下的段落按字符截断
+// README的开头通常是项目的介绍,对推断最有用
+func Truncate(s string, tokens int) string {
+	if tokens <= 0 || EstimateTokens(s) <= tokens {
+		return s
+	}
+
+	var (
+		kept   []string
+		remain = tokens
+	)
+	for _, p := range strings.Split(s, "\n\n") {
+		cost := EstimateTokens(p)
+		if cost <= remain {
+			kept = append(kept, p)
+			remain -= cost
+			continue
+		}
+		if cut := cutRunes(p, remain); cut != "" {
+			kept = append(kept, cut+"...")
+		}
+		break
+	}
+	return strings.Join(kept, "\n\n")
+}
+
+// cutRunes 截取开头不超过tokens个token的内容
+func cutRunes(s string, tokens int) string {
+	used, ascii := 0, 0
+	for i, r := range s {
+		if r < utf8.RuneSelf {
+			ascii++
+			if ascii%4 == 1 {
+				used++
+			}
+		} else {
+			used++
+		}
+		if used > tokens {
+			return strings.TrimSpace(s[:i])
+		}
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package vector
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+)
+
+// BruteForce 在内存中逐个比较,适合几十万以内的用户
+type BruteForce struct {
+	mu      sync.RWMutex
+	entries map[int64]entry
+}
+
+type entry struct {
+	model  string
+	vector []float32 //已经归一化
+}
+
+var _ Index = (*BruteForce)(nil)
+
+func NewBruteForce() *BruteForce {
+	return &BruteForce{entries: make(map[int64]entry)}
+}
+
+func (b *BruteForce) Upsert(id int64, model string, vector []float32) {
+	v := normalize(vector)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if v == nil {
+		delete(b.entries, id)
+		return
+	}
+	b.entries[id] = entry{model: model, vector: v}
+}
+
+func (b *BruteForce) Delete(id int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.entries, id)
+}
+
+func (b *BruteForce) Search(model string, query []float32, k int) []Result {
+	q := normalize(query)
+	if q == nil || k <= 0 {
+		return nil
+	}
+
+	//小顶堆保存当前最相似的k个
+	h := make(resultHeap, 0, k)
+	b.mu.RLock()
+	for id, e := range b.entries {
+		if e.model != model || len(e.vector) != len(q) {
+			continue
+		}
+		r := Result{ID: id, Score: dot(q, e.vector)}
+		if len(h) < k {
+			heap.Push(&h, r)
+		} else if r.Score > h[0].Score {
+			h[0] = r
+			heap.Fix(&h, 0)
+		}
+	}
+	b.mu.RUnlock()
+
+	resp := []Result(h)
+	sort.Slice(resp, func(i, j int) bool {
+		if resp[i].Score != resp[j].Score {
+			return resp[i].Score > resp[j].Score
+		}
+		return resp[i].ID < resp[j].ID
+	})
+	return resp
+}
+
+func (b *BruteForce) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.entries)
+}
+
+type resultHeap []Result
+
+func (h resultHeap) Len() int           { return len(h) }
+func (h resultHeap) Less(i, j int) bool { return h[i].Score < h[j].Score }
+func (h resultHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *resultHeap) Push(x any)        { *h = append(*h, x.(Result)) }
+func (h *resultHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,65 @@
+package vector
+
+import (
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/conf"
+	"math"
+)
+
+// 按照余弦相似度搜索用户画像的向量
+// 不同模型计算的向量不能比较,所以每个向量都带有模型名,搜索时只比较同一个模型的向量
+
+const (
+	KindBruteForce = "bruteforce"
+)
+
+// Index 向量索引,实现需要是并发安全的
+type Index interface {
+	// Upsert 写入或者替换id对应的向量
+	Upsert(id int64, model string, vector []float32)
+	Delete(id int64)
+	// Search 返回和query最相似的k个向量,按照相似度从高到低排序
+	Search(model string, query []float32, k int) []Result
+	Len() int
+}
+
+// Result 搜索结果,Score是余弦相似度,范围-1到1
+type Result struct {
+	ID    int64
+	Score float32
+}
+
+// NewIndex 按照配置创建索引,默认是进程内的暴力搜索
+func NewIndex(cfg *conf.EmbeddingConfig) Index {
+	switch cfg.Index {
+	case "", KindBruteForce:
+		return NewBruteForce()
+	default:
+		panic(fmt.Sprintf("unsupported vector index: %s", cfg.Index))
+	}
+}
+
+// normalize 返回单位向量,之后的余弦相似度就是点积,零向量返回nil
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return nil
+	}
+	norm := float32(math.Sqrt(sum))
+	resp := make([]float32, len(v))
+	for i, x := range v {
+		resp[i] = x / norm
+	}
+	return resp
+}
+
+func dot(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"github.com/GitEval/GitEval-Backend/model"
+	"time"
+)
+
+// 管理员使用的统计服务
+
+type LLMAuditDAOProxy interface {
+	GetDailyUsage(ctx context.Context, since time.Time) ([]model.LLMUsage, error)
+	GetUserUsage(ctx context.Context, since time.Time, page, pageSize int) ([]model.LLMUsage, error)
+}
+
+// DeadJobDAOProxy 查看和重新执行死信中的任务
+type DeadJobDAOProxy interface {
+	GetDeadJobs(ctx context.Context, page, pageSize int) ([]model.Job, error)
+	RequeueJob(ctx context.Context, id int64, now time.Time) (bool, error)
+}
+
+type AdminService struct {
+	audit LLMAuditDAOProxy
+	job   DeadJobDAOProxy
+}
+
+func NewAdminService(audit LLMAuditDAOProxy, job DeadJobDAOProxy) *AdminService {
+	return &AdminService{audit: audit, job: job}
+}
+
+// GetLLMUsage 统计最近days天llm的调用次数,失败次数,token用量和平均耗时,分别按天和按用户聚合
+func (s *AdminService) GetLLMUsage(ctx context.Context, days, page, pageSize int) (daily []model.LLMUsage, users []model.LLMUsage, err error) {
+	now := time.Now()
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1-days)
+
+	daily, err = s.audit.GetDailyUsage(ctx, since)
+	if err != nil {
+		return nil, nil, err
+	}
+	users, err = s.audit.GetUserUsage(ctx, since, page, pageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return daily, users, nil
+}
+
+// GetDeadJobs 分页获取死信中的任务
+func (s *AdminService) GetDeadJobs(ctx context.Context, page, pageSize int) ([]model.Job, error) {
+	return s.job.GetDeadJobs(ctx, page, pageSize)
+}
+
+// RequeueJob 重新执行死信中的任务,任务不在死信中时返回false
+func (s *AdminService) RequeueJob(ctx context.Context, id int64) (bool, error) {
+	return s.job.RequeueJob(ctx, id, time.Now())
+}
@@ -4,76 +4,69 @@ import (
 	"context"
 	llmv1 "github.com/GitEval/GitEval-Backend/client/gen"
 	"github.com/GitEval/GitEval-Backend/model"
-	"github.com/google/go-github/v50/github"
+	"log"
 )
 
-type GitHubAPIProxy interface {
-	GetLoginUrl() string
-	SetClient(userID int64, client *github.Client)
-	GetClientFromMap(userID int64) (*github.Client, bool)
-	GetClientByCode(code string) (*github.Client, error)
-	CalculateScore(ctx context.Context, id int64, name string) float64
-	GetUserInfo(ctx context.Context, client *github.Client, username string) (*github.User, error)
-}
-
 type UserServiceProxy interface {
 	InitUser(ctx context.Context, u model.User) (err error)
 	GetUserById(ctx context.Context, id int64) (model.User, error)
 	CreateUser(ctx context.Context, u model.User) error
 }
 
+type OrgServiceProxy interface {
+	SyncUserOrganizations(ctx context.Context, u model.User) error
+}
+
 type AuthService struct {
-	githubAPI GitHubAPIProxy
-	u         UserServiceProxy
-	l         llmv1.LLMServiceClient
+	forges *ForgeRegistry
+	u      UserServiceProxy
+	o      OrgServiceProxy
+	queue  JobQueue
+	sync   SyncTracker
+	l      llmv1.LLMServiceClient
 }
 
-func NewAuthService(u UserServiceProxy, api GitHubAPIProxy, l llmv1.LLMServiceClient) *AuthService {
-	return &AuthService{
+func NewAuthService(u UserServiceProxy, o OrgServiceProxy, forges *ForgeRegistry, queue JobQueue, sync SyncTracker, l llmv1.LLMServiceClient) *AuthService {
+	s := &AuthService{
 		u: u,
+		o: o,
 		//因为让其成为中枢，必然要依赖注入到这个authService
-		githubAPI: api,
-		l:         l,
+		forges: forges,
+		queue:  queue,
+		sync:   sync,
+		l:      l,
 	}
+	queue.Register(JobInitUser, s.initUser)
+	return s
 }
 
-func (s *AuthService) Login(ctx context.Context) (url string, err error) {
-	url = s.githubAPI.GetLoginUrl()
+func (s *AuthService) Login(ctx context.Context, forge string) (url string, err error) {
+	f, err := s.forges.GetForge(forge)
+	if err != nil {
+		return "", err
+	}
+	url = f.GetLoginUrl()
 	return url, nil
 }
 
-func (s *AuthService) CallBack(ctx context.Context, code string) (userId int64, err error) {
-	client, err := s.githubAPI.GetClientByCode(code)
+func (s *AuthService) CallBack(ctx context.Context, forge string, code string) (userId int64, err error) {
+	f, err := s.forges.GetForge(forge)
 	if err != nil {
 		return 0, err
 	}
 
-	userInfo, err := s.githubAPI.GetUserInfo(ctx, client, "")
+	// 平台会自己存储用户的客户端
+	userInfo, err := f.Authorize(ctx, code)
 	if err != nil {
 		return 0, err
 	}
 
 	// 根据用户 ID 查找用户
-	user, err := s.u.GetUserById(ctx, userInfo.GetID())
+	user, err := s.u.GetUserById(ctx, userInfo.ID)
 	// 如果用户不存在，创建新用户,如果存在
 	if (user == model.User{}) {
-		user = model.User{
-			LoginName:         userInfo.GetLogin(),
-			ID:                userInfo.GetID(),
-			AvatarURL:         userInfo.GetAvatarURL(),
-			Name:              userInfo.GetName(),
-			Company:           userInfo.GetName(),
-			Blog:              userInfo.GetBlog(),
-			Location:          userInfo.GetLocation(),
-			Email:             userInfo.GetEmail(),
-			Bio:               userInfo.GetBio(),
-			PublicRepos:       userInfo.GetPublicRepos(),
-			Followers:         userInfo.GetFollowers(),
-			Following:         userInfo.GetFollowing(),
-			TotalPrivateRepos: userInfo.GetTotalPrivateRepos(),
-			Collaborators:     userInfo.GetCollaborators(),
-			Score:             s.githubAPI.CalculateScore(ctx, userInfo.GetID(), userInfo.GetLogin()), //获取用户的分数
-		}
+		user = userInfo
+		user.Score = f.CalculateScore(ctx, user.ID, user.LoginName) //获取用户的分数
 
 		//首次创建用户
 		err = s.u.CreateUser(ctx, user)
@@ -84,16 +77,34 @@ func (s *AuthService) CallBack(ctx context.Context, code string) (userId int64,
 	}
 
 	//这里做异步主要是为了保证用户体验,否则等待时间过长了
-	go func() {
-		// 每次都尝试初始化用户关系网
-		err = s.u.InitUser(context.Background(), user)
-		if err != nil {
-			return
-		}
-	}()
-
-	//存储用户到内存中去
-	s.githubAPI.SetClient(user.ID, client)
+	//每次都尝试初始化用户关系网,提交失败不影响登录
+	s.sync.BeginSync(ctx, user.ID, model.SyncStepGraph)
+	if err := s.queue.Enqueue(ctx, JobInitUser, userJob{UserID: user.ID}); err != nil {
+		log.Println("enqueue init user failed:", err)
+	}
 
 	return user.ID, nil
 }
+
+// initUser 初始化用户的关系网,顺便同步用户所属的组织
+func (s *AuthService) initUser(ctx context.Context, payload []byte) error {
+	job, err := decodeUserJob(payload)
+	if err != nil {
+		return err
+	}
+	user, err := s.u.GetUserById(ctx, job.UserID)
+	if err != nil {
+		return err
+	}
+	if err := s.u.InitUser(ctx, user); err != nil {
+		return err
+	}
+	//目前只有github支持组织,同步失败不重试整个初始化
+	if user.Forge != model.ForgeGitHub {
+		return nil
+	}
+	if err := s.o.SyncUserOrganizations(ctx, user); err != nil {
+		log.Println("sync organizations failed:", err)
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/client"
+	llmv1 "github.com/GitEval/GitEval-Backend/client/gen"
+	"github.com/GitEval/GitEval-Backend/model"
+	"github.com/GitEval/GitEval-Backend/pkg/sanitize"
+	"gorm.io/gorm"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 并排对比多个开发者,可以选择让llm生成对比的总结
+
+const (
+	MinCompareUsers = 2
+	MaxCompareUsers = 5
+)
+
+var (
+	// ErrCompareUsers 去重之后的用户数不在范围内
+	ErrCompareUsers = fmt.Errorf("compare needs %d to %d different users", MinCompareUsers, MaxCompareUsers)
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// CompareUserResolver 按照登录名获取用户,没有存储时从github拉取
+type CompareUserResolver interface {
+	GetPublicUser(ctx context.Context, login string, infer bool) (model.User, error)
+}
+
+// LeaderboardProxy 读取排行榜,不能有记录活跃用户之类的副作用
+type LeaderboardProxy interface {
+	BuildLeaderboard(ctx context.Context, userId int64) ([]model.Leaderboard, error)
+}
+
+type CompareService struct {
+	user        UserDAOProxy
+	domain      DomainDAOProxy
+	interest    InterestDAOProxy
+	evaluation  EvaluationDAOProxy
+	checkpoint  CheckpointDAOProxy
+	forges      *ForgeRegistry
+	resolver    CompareUserResolver
+	leaderboard LeaderboardProxy
+	l           llmv1.LLMServiceClient
+}
+
+func NewCompareService(user UserDAOProxy, domain DomainDAOProxy, interest InterestDAOProxy, evaluation EvaluationDAOProxy, checkpoint CheckpointDAOProxy, forges *ForgeRegistry, resolver CompareUserResolver, leaderboard LeaderboardProxy, l llmv1.LLMServiceClient) *CompareService {
+	return &CompareService{
+		user:        user,
+		domain:      domain,
+		interest:    interest,
+		evaluation:  evaluation,
+		checkpoint:  checkpoint,
+		forges:      forges,
+		resolver:    resolver,
+		leaderboard: leaderboard,
+		l:           l,
+	}
+}
+
+// CompareUsers 对比通过id或者登录名指定的用户,summary为true时生成对比的总结
+// 生成总结失败时其他数据仍然返回,失败的原因放在 SummaryError 中
+func (s *CompareService) CompareUsers(ctx context.Context, requester int64, ids []int64, logins []string, summary bool) (model.Comparison, error) {
+	users, err := s.resolveUsers(ctx, ids, logins)
+	if err != nil {
+		return model.Comparison{}, err
+	}
+	userIds := make([]int64, 0, len(users))
+	for _, u := range users {
+		userIds = append(userIds, u.ID)
+	}
+
+	domains, err := s.domain.GetDomainsByUserIds(ctx, userIds)
+	if err != nil {
+		return model.Comparison{}, err
+	}
+	interests, err := s.interest.GetInterestsByUserIds(ctx, userIds)
+	if err != nil {
+		return model.Comparison{}, err
+	}
+	confidence := make(map[int64]map[string]float32, len(users))
+	for _, d := range domains {
+		if confidence[d.UserID] == nil {
+			confidence[d.UserID] = make(map[string]float32)
+		}
+		confidence[d.UserID][d.Domain] = d.Confidence
+	}
+	weight := make(map[int64]map[string]float64, len(users))
+	for _, v := range interests {
+		if v.Kind != model.InterestKindLanguage {
+			continue
+		}
+		if weight[v.UserID] == nil {
+			weight[v.UserID] = make(map[string]float64)
+		}
+		weight[v.UserID][v.Name] = v.Weight
+	}
+
+	resp := model.Comparison{
+		Domains:   unionKeys(confidence),
+		Languages: unionKeys(weight),
+		Users:     make([]model.ComparedUser, len(users)),
+	}
+	activities := s.activities(ctx, users)
+	for i, u := range users {
+		c := model.ComparedUser{
+			User:             u,
+			Scores:           s.latestScores(ctx, u.ID),
+			DomainConfidence: make([]float32, len(resp.Domains)),
+			LanguageWeight:   make([]float64, len(resp.Languages)),
+			Activity:         activities[i],
+		}
+		for j, d := range resp.Domains {
+			c.DomainConfidence[j] = confidence[u.ID][d]
+		}
+		for j, l := range resp.Languages {
+			c.LanguageWeight[j] = weight[u.ID][l]
+		}
+		if c.GlobalRank, err = s.user.GetScoreRank(ctx, u.Score); err != nil {
+			return model.Comparison{}, err
+		}
+		if c.NetworkRank, c.NetworkSize, err = s.networkRank(ctx, u.ID); err != nil {
+			return model.Comparison{}, err
+		}
+		resp.Users[i] = c
+	}
+
+	if summary {
+		res, err := s.l.CompareUsers(client.WithUserID(ctx, requester), compareRequest(resp))
+		if err != nil {
+			log.Println("compare users failed:", err)
+			resp.SummaryError = err.Error()
+		} else {
+			resp.Summary = res.Summary
+		}
+	}
+	return resp, nil
+}
+
+// resolveUsers 按照先id后登录名的顺序获取用户,重复的用户只保留一个
+func (s *CompareService) resolveUsers(ctx context.Context, ids []int64, logins []string) ([]model.User, error) {
+	var users []model.User
+	seen := make(map[int64]bool)
+	add := func(u model.User) {
+		if !seen[u.ID] {
+			seen[u.ID] = true
+			users = append(users, u)
+		}
+	}
+	for _, id := range ids {
+		u, err := s.user.GetUserByID(ctx, id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %d", ErrUserNotFound, id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		add(u)
+	}
+	for _, login := range logins {
+		u, err := s.resolver.GetPublicUser(ctx, login, false)
+		if err != nil {
+			return nil, fmt.Errorf("get user %s: %w", login, err)
+		}
+		add(u)
+	}
+	if len(users) < MinCompareUsers || len(users) > MaxCompareUsers {
+		return nil, ErrCompareUsers
+	}
+	return users, nil
+}
+
+// activities 并发地获取每个用户近期的活动,失败的用户为nil
+// 同步过事件的用户使用检查点中的事件,其他用户只获取一页公开事件,都不需要被对比的用户自己的客户端
+func (s *CompareService) activities(ctx context.Context, users []model.User) []*model.Activity {
+	resp := make([]*model.Activity, len(users))
+	var wg sync.WaitGroup
+	for i, u := range users {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			events, err := s.recentEvents(ctx, u)
+			if err != nil {
+				log.Printf("get events of %s failed: %v\n", u.LoginName, err)
+				return
+			}
+			activity := &model.Activity{Repos: len(events)}
+			for _, e := range events {
+				activity.Commits += e.PushCount
+				activity.PullRequests += e.PullRequestCount
+				activity.Issues += e.IssuesCount
+			}
+			resp[i] = activity
+		}()
+	}
+	wg.Wait()
+	return resp
+}
+
+// recentEvents 优先使用检查点中的事件,没有同步过事件时从平台获取公开事件
+func (s *CompareService) recentEvents(ctx context.Context, u model.User) ([]model.UserEvent, error) {
+	checkpoint, err := s.checkpoint.GetCheckpoint(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint.Cursor.ID != 0 {
+		return recentUserEvents(checkpoint.Events, time.Now()), nil
+	}
+	forge, err := s.forges.GetForge(u.Forge)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := forge.(PublicEventsForge)
+	if !ok {
+		return nil, fmt.Errorf("forge %s does not support public events", forge.Name())
+	}
+	return f.GetPublicUserEvents(ctx, u.LoginName)
+}
+
+// latestScores 最新评价的各维度评分,没有评价时为nil
+func (s *CompareService) latestScores(ctx context.Context, userId int64) *model.EvaluationScores {
+	evaluation, err := s.evaluation.GetLatest(ctx, userId)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("get latest evaluation failed:", err)
+		}
+		return nil
+	}
+	return &evaluation.Scores
+}
+
+// networkRank 用户在自己关注关系的排行榜中的排名和排行榜的人数
+func (s *CompareService) networkRank(ctx context.Context, userId int64) (int, int, error) {
+	leaderboard, err := s.leaderboard.BuildLeaderboard(ctx, userId)
+	if err != nil {
+		return 0, 0, err
+	}
+	for i, v := range leaderboard {
+		if v.UserID == userId {
+			return i + 1, len(leaderboard), nil
+		}
+	}
+	return 0, len(leaderboard), nil
+}
+
+// compareRequest 构造生成对比总结的请求
+func compareRequest(c model.Comparison) *llmv1.CompareUsersRequest {
+	req := &llmv1.CompareUsersRequest{Users: make([]*llmv1.CompareProfile, 0, len(c.Users))}
+	for _, u := range c.Users {
+		p := &llmv1.CompareProfile{
+			Login:       u.User.LoginName,
+			Bio:         sanitize.Text(u.User.Bio),
+			Score:       u.User.Score,
+			Followers:   int32(u.User.Followers),
+			PublicRepos: int32(u.User.PublicRepos),
+		}
+		for i, d := range c.Domains {
+			if u.DomainConfidence[i] > 0 {
+				p.Domains = append(p.Domains, &llmv1.Domain{Domain: d, Confidence: u.DomainConfidence[i]})
+			}
+		}
+		for i, l := range c.Languages {
+			if u.LanguageWeight[i] > 0 {
+				p.Languages = append(p.Languages, l)
+			}
+		}
+		if u.Scores != nil {
+			p.Scores = &llmv1.DimensionScores{
+				CodeQuality:   u.Scores.CodeQuality,
+				Activity:      u.Scores.Activity,
+				Collaboration: u.Scores.Collaboration,
+				Influence:     u.Scores.Influence,
+				Breadth:       u.Scores.Breadth,
+			}
+		}
+		if u.Activity != nil {
+			p.Commits = int32(u.Activity.Commits)
+			p.PullRequests = int32(u.Activity.PullRequests)
+			p.Issues = int32(u.Activity.Issues)
+		}
+		req.Users = append(req.Users, p)
+	}
+	return req
+}
+
+// unionKeys 所有用户的键的并集,按照总和从大到小排序,总和相同时按照名称排序
+func unionKeys[V float32 | float64](values map[int64]map[string]V) []string {
+	sum := make(map[string]float64)
+	for _, m := range values {
+		for k, v := range m {
+			sum[k] += float64(v)
+		}
+	}
+	keys := make([]string, 0, len(sum))
+	for k := range sum {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if sum[keys[i]] != sum[keys[j]] {
+			return sum[keys[i]] > sum[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
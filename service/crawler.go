@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"github.com/GitEval/GitEval-Backend/conf"
+	"github.com/GitEval/GitEval-Backend/model"
+	"log"
+	"time"
+)
+
+// 从种子用户开始沿着关注关系广度优先爬取用户,扩大搜索的人才池
+
+// crawlerFlushSize 每爬取这么多用户就写入一次数据库
+const crawlerFlushSize = 50
+
+type CrawlerGithubProxy interface {
+	GetUserByLogin(ctx context.Context, login string) (model.User, error)
+	ListFollowByLogin(ctx context.Context, login string, following bool, limit int) ([]model.User, int, error)
+	CalculateScore(ctx context.Context, id int64, name string) float64
+	GetRateRemaining(ctx context.Context) (int, error)
+}
+
+type CrawlerService struct {
+	user    UserDAOProxy
+	contact ContactDAOProxy
+	tx      Transaction
+	g       CrawlerGithubProxy
+	cfg     *conf.CrawlerConfig
+}
+
+func NewCrawlerService(user UserDAOProxy, contact ContactDAOProxy, transaction Transaction, g CrawlerGithubProxy, cfg *conf.CrawlerConfig) *CrawlerService {
+	return &CrawlerService{
+		user:    user,
+		contact: contact,
+		tx:      transaction,
+		g:       g,
+		cfg:     cfg,
+	}
+}
+
+// Start 按照配置的间隔定时运行爬虫,没有开启时直接返回
+func (s *CrawlerService) Start(ctx context.Context) {
+	if !s.cfg.Enable || len(s.cfg.Seeds) == 0 {
+		return
+	}
+	ticker := time.NewTicker(time.Duration(s.cfg.Interval) * time.Minute)
+	defer ticker.Stop()
+	for {
+		stored, err := s.Crawl(ctx, s.cfg.Seeds)
+		if err != nil {
+			log.Println("crawl failed:", err)
+		}
+		log.Printf("crawl finished, %d users stored\n", stored)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type crawlNode struct {
+	login string
+	depth int
+}
+
+// Crawl 从seeds开始广度优先爬取,返回存储的用户数
+// 受深度,每次运行的用户数,请求预算以及github剩余额度的限制
+func (s *CrawlerService) Crawl(ctx context.Context, seeds []string) (int, error) {
+	var (
+		queue    = make([]crawlNode, 0, len(seeds))
+		visited  = make(map[string]bool)
+		users    = make([]model.User, 0, crawlerFlushSize)
+		contacts = make([]model.FollowingContact, 0)
+		budget   = s.cfg.Budget
+		stored   int
+	)
+	for _, seed := range seeds {
+		if !visited[seed] {
+			visited[seed] = true
+			queue = append(queue, crawlNode{login: seed})
+		}
+	}
+
+	flush := func() error {
+		err := s.tx.InTx(ctx, func(ctx context.Context) error {
+			if err := s.user.CreateUsers(ctx, users); err != nil {
+				return err
+			}
+			return s.contact.CreateContacts(ctx, contacts)
+		})
+		if err != nil {
+			return err
+		}
+		stored += len(users)
+		users = users[:0]
+		contacts = contacts[:0]
+		return nil
+	}
+
+	for len(queue) > 0 && stored+len(users) < s.cfg.MaxUsers && budget > 0 {
+		if ctx.Err() != nil {
+			break
+		}
+		// 每一批开始之前检查github的剩余额度,避免影响登录用户的请求
+		if len(users) == 0 && !s.hasQuota(ctx) {
+			log.Println("crawler stopped: github rate limit is almost exhausted")
+			break
+		}
+
+		node := queue[0]
+		queue = queue[1:]
+
+		u, err := s.g.GetUserByLogin(ctx, node.login)
+		budget--
+		if err != nil {
+			log.Println("crawler get user failed:", err)
+			continue
+		}
+		u.Score = s.g.CalculateScore(ctx, u.ID, u.LoginName)
+		budget--
+		users = append(users, u)
+
+		if node.depth < s.cfg.Depth {
+			for _, following := range []bool{true, false} {
+				if budget <= 0 {
+					break
+				}
+				neighbours, requests, err := s.g.ListFollowByLogin(ctx, node.login, following, s.cfg.FanOut)
+				budget -= requests
+				if err != nil {
+					log.Println("crawler list follow failed:", err)
+					continue
+				}
+				if following {
+					contacts = append(contacts, getContact(u.ID, neighbours, Following)...)
+				} else {
+					contacts = append(contacts, getContact(u.ID, neighbours, Followers)...)
+				}
+				for _, v := range neighbours {
+					if !visited[v.LoginName] {
+						visited[v.LoginName] = true
+						queue = append(queue, crawlNode{login: v.LoginName, depth: node.depth + 1})
+					}
+				}
+			}
+		}
+
+		if len(users) >= crawlerFlushSize {
+			if err := flush(); err != nil {
+				return stored, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return stored, err
+	}
+	return stored, nil
+}
+
+func (s *CrawlerService) hasQuota(ctx context.Context) bool {
+	remaining, err := s.g.GetRateRemaining(ctx)
+	if err != nil {
+		log.Println("get github rate limit failed:", err)
+		return false
+	}
+	return remaining > s.cfg.MinRemaining
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/GitEval/GitEval-Backend/model"
+	"gorm.io/gorm"
+	"log"
+)
+
+// 基于github用户搜索的人才发现
+
+// ErrQueryNotFound 查询不存在或者不属于当前用户,两种情况不做区分,避免泄露其他用户的查询是否存在
+var ErrQueryNotFound = errors.New("discovery query not found")
+
+type DiscoveryDAOProxy interface {
+	CreateQuery(ctx context.Context, query *model.DiscoveryQuery) error
+	SaveQuery(ctx context.Context, query *model.DiscoveryQuery) error
+	StartQuery(ctx context.Context, id int64) (bool, error)
+	GetQueryByID(ctx context.Context, id int64) (model.DiscoveryQuery, error)
+	GetQueriesByCreator(ctx context.Context, creatorID int64) ([]model.DiscoveryQuery, error)
+	SaveResults(ctx context.Context, results []model.DiscoveryResult) error
+	GetResults(ctx context.Context, queryID int64, page int, pageSize int) ([]model.DiscoveryUser, error)
+}
+
+type DiscoveryGithubProxy interface {
+	BuildUserSearchQuery(keyword, location, language string, minFollowers int) string
+	SearchUsers(ctx context.Context, query string, limit int) ([]model.User, int, error)
+	GetUserByLogin(ctx context.Context, login string) (model.User, error)
+	CalculateScore(ctx context.Context, id int64, name string) float64
+}
+
+// discoveryJob 运行查询的任务的参数,执行时再读取最新的查询
+type discoveryJob struct {
+	QueryID int64 `json:"query_id"`
+}
+
+type DiscoveryService struct {
+	discovery DiscoveryDAOProxy
+	user      UserDAOProxy
+	g         DiscoveryGithubProxy
+	inferrer  UserInferrer
+	queue     JobQueue
+}
+
+func NewDiscoveryService(discovery DiscoveryDAOProxy, user UserDAOProxy, g DiscoveryGithubProxy, inferrer UserInferrer, queue JobQueue) *DiscoveryService {
+	s := &DiscoveryService{
+		discovery: discovery,
+		user:      user,
+		g:         g,
+		inferrer:  inferrer,
+		queue:     queue,
+	}
+	queue.Register(JobRunDiscovery, s.runQuery)
+	return s
+}
+
+// CreateQuery 保存查询并提交运行的任务
+func (s *DiscoveryService) CreateQuery(ctx context.Context, query model.DiscoveryQuery) (model.DiscoveryQuery, error) {
+	query.ID = 0
+	query.Query = s.g.BuildUserSearchQuery(query.Keyword, query.Location, query.Language, query.MinFollowers)
+	query.Status = model.DiscoveryStatusPending
+	if err := s.discovery.CreateQuery(ctx, &query); err != nil {
+		return model.DiscoveryQuery{}, err
+	}
+
+	if err := s.enqueue(ctx, &query); err != nil {
+		return model.DiscoveryQuery{}, err
+	}
+	return query, nil
+}
+
+// RunQuery 重新运行当前用户的一个已经保存的查询,等待或者运行中的查询不会重复运行
+func (s *DiscoveryService) RunQuery(ctx context.Context, userId int64, id int64) (model.DiscoveryQuery, error) {
+	query, err := s.GetQuery(ctx, userId, id)
+	if err != nil {
+		return model.DiscoveryQuery{}, err
+	}
+	started, err := s.discovery.StartQuery(ctx, id)
+	if err != nil {
+		return model.DiscoveryQuery{}, err
+	}
+	if !started {
+		return query, nil
+	}
+
+	query.Status = model.DiscoveryStatusPending
+	query.Error = ""
+	if err := s.enqueue(ctx, &query); err != nil {
+		return model.DiscoveryQuery{}, err
+	}
+	return query, nil
+}
+
+// enqueue 提交运行查询的任务,提交失败时把查询标记为失败,之后可以重新运行
+func (s *DiscoveryService) enqueue(ctx context.Context, query *model.DiscoveryQuery) error {
+	err := s.queue.Enqueue(ctx, JobRunDiscovery, discoveryJob{QueryID: query.ID})
+	if err == nil {
+		return nil
+	}
+	query.Status = model.DiscoveryStatusFailed
+	query.Error = err.Error()
+	if err := s.discovery.SaveQuery(ctx, query); err != nil {
+		log.Println("save discovery query failed:", err)
+	}
+	return err
+}
+
+func (s *DiscoveryService) GetQueries(ctx context.Context, userId int64) ([]model.DiscoveryQuery, error) {
+	return s.discovery.GetQueriesByCreator(ctx, userId)
+}
+
+// GetQuery 获取当前用户的查询,查询不存在或者属于其他用户时返回 ErrQueryNotFound
+func (s *DiscoveryService) GetQuery(ctx context.Context, userId int64, id int64) (model.DiscoveryQuery, error) {
+	query, err := s.discovery.GetQueryByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.DiscoveryQuery{}, ErrQueryNotFound
+	}
+	if err != nil {
+		return model.DiscoveryQuery{}, err
+	}
+	if query.CreatorID != userId {
+		return model.DiscoveryQuery{}, ErrQueryNotFound
+	}
+	return query, nil
+}
+
+func (s *DiscoveryService) GetResults(ctx context.Context, userId int64, id int64, page int, pageSize int) ([]model.DiscoveryUser, error) {
+	if _, err := s.GetQuery(ctx, userId, id); err != nil {
+		return nil, err
+	}
+	return s.discovery.GetResults(ctx, id, page, pageSize)
+}
+
+// runQuery 执行运行查询的任务,进程重启之后任务会被重新领取,查询不会一直停留在运行中
+func (s *DiscoveryService) runQuery(ctx context.Context, payload []byte) error {
+	var job discoveryJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return Permanent(err)
+	}
+	query, err := s.discovery.GetQueryByID(ctx, job.QueryID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+	return s.run(ctx, query)
+}
+
+// run 搜索匹配的用户,逐个导入并计算分数,之后异步推断国籍和领域
+// 失败时还会重试的查询保持等待状态,不再重试时标记为失败
+func (s *DiscoveryService) run(ctx context.Context, query model.DiscoveryQuery) error {
+	query.Status = model.DiscoveryStatusRunning
+	query.Error = ""
+	if err := s.discovery.SaveQuery(ctx, &query); err != nil {
+		return err
+	}
+
+	matched, total, err := s.g.SearchUsers(ctx, query.Query, query.Limit)
+	if err != nil {
+		log.Println("search users failed:", err)
+		return s.fail(ctx, query, err)
+	}
+	query.Total = total
+	query.Imported = 0
+
+	for _, v := range matched {
+		//超过任务的租约时停止,重试时重新运行
+		if err := ctx.Err(); err != nil {
+			return s.fail(ctx, query, err)
+		}
+		result := model.DiscoveryResult{QueryID: query.ID, UserID: v.ID, Login: v.LoginName, Status: model.DiscoveryResultImported}
+		u, err := s.importUser(ctx, v.LoginName)
+		if err != nil {
+			log.Println("import discovered user failed:", err)
+			result.Status = model.DiscoveryResultFailed
+		} else {
+			query.Imported++
+			if err := s.inferrer.InferUser(ctx, u.ID); err != nil {
+				log.Println("enqueue inference failed:", err)
+			}
+		}
+		if err := s.discovery.SaveResults(ctx, []model.DiscoveryResult{result}); err != nil {
+			log.Println("save discovery result failed:", err)
+		}
+	}
+
+	query.Status = model.DiscoveryStatusDone
+	return s.discovery.SaveQuery(ctx, &query)
+}
+
+// fail 记录运行失败的原因,返回原来的错误
+func (s *DiscoveryService) fail(ctx context.Context, query model.DiscoveryQuery, err error) error {
+	query.Status = model.DiscoveryStatusFailed
+	if willRetry(ctx, err) {
+		query.Status = model.DiscoveryStatusPending
+	}
+	query.Error = err.Error()
+	if err := s.discovery.SaveQuery(context.WithoutCancel(ctx), &query); err != nil {
+		log.Println("save discovery query failed:", err)
+	}
+	return err
+}
+
+func (s *DiscoveryService) importUser(ctx context.Context, login string) (model.User, error) {
+	u, err := s.g.GetUserByLogin(ctx, login)
+	if err != nil {
+		return model.User{}, err
+	}
+	u.Score = s.g.CalculateScore(ctx, u.ID, u.LoginName)
+	if err := s.user.CreateUsers(ctx, []model.User{u}); err != nil {
+		return model.User{}, err
+	}
+	return u, nil
+}
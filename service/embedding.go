@@ -0,0 +1,429 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/client"
+	llmv1 "github.com/GitEval/GitEval-Backend/client/gen"
+	"github.com/GitEval/GitEval-Backend/conf"
+	"github.com/GitEval/GitEval-Backend/model"
+	"github.com/GitEval/GitEval-Backend/pkg/sanitize"
+	"github.com/GitEval/GitEval-Backend/pkg/vector"
+	"gorm.io/gorm"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 用户画像的向量,用于查找相似的开发者和语义搜索
+// 画像由简介,领域,语言和README摘要组成,领域更新时重新计算
+
+// ErrNoProfile 用户还没有简介和领域,无法计算向量
+var ErrNoProfile = errors.New("user has no profile to embed yet")
+
+const (
+	// maxQueryTokens 语义搜索的查询最多保留的token数
+	maxQueryTokens = 256
+	// loadPageSize 启动时每次从数据库加载的向量数
+	loadPageSize = 1000
+)
+
+type EmbeddingDAOProxy interface {
+	SaveEmbedding(ctx context.Context, embedding *model.Embedding) error
+	GetEmbedding(ctx context.Context, userID int64) (model.Embedding, error)
+	GetFingerprints(ctx context.Context, userIDs []int64) (map[int64]string, error)
+	ListEmbeddings(ctx context.Context, afterUserID int64, limit int) ([]model.Embedding, error)
+	GetUsersMissingEmbedding(ctx context.Context, limit int) ([]model.User, error)
+}
+
+// VectorIndex 可替换的向量索引,默认是进程内的暴力搜索
+type VectorIndex interface {
+	Upsert(id int64, model string, vector []float32)
+	Delete(id int64)
+	Search(model string, query []float32, k int) []vector.Result
+	Len() int
+}
+
+// Profile 计算画像需要的信息,没有获取仓库时Repos为空
+type Profile struct {
+	User      model.User
+	Domains   []model.Domain
+	Interests []model.Interest
+	Repos     []*model.Repo
+}
+
+type EmbeddingService struct {
+	user       UserDAOProxy
+	domain     DomainDAOProxy
+	interest   InterestDAOProxy
+	embedding  EmbeddingDAOProxy
+	index      VectorIndex
+	checkpoint CheckpointDAOProxy
+	cfg        *conf.EmbeddingConfig
+	l          llmv1.LLMServiceClient
+}
+
+func NewEmbeddingService(user UserDAOProxy, domain DomainDAOProxy, interest InterestDAOProxy, embedding EmbeddingDAOProxy, index VectorIndex, checkpoint CheckpointDAOProxy, queue JobQueue, cfg *conf.EmbeddingConfig, l llmv1.LLMServiceClient) *EmbeddingService {
+	s := &EmbeddingService{
+		user:       user,
+		domain:     domain,
+		interest:   interest,
+		embedding:  embedding,
+		index:      index,
+		checkpoint: checkpoint,
+		cfg:        cfg,
+		l:          l,
+	}
+	queue.Register(JobEmbedUser, s.embedUser)
+	return s
+}
+
+// Start 先把存储的向量加载到索引中,开启补全时再按照配置的间隔定时补全
+func (s *EmbeddingService) Start(ctx context.Context) {
+	loaded, err := s.Load(ctx)
+	if err != nil {
+		log.Println("load embeddings failed:", err)
+	}
+	log.Printf("%d embeddings loaded into vector index\n", loaded)
+	if !s.cfg.Backfill {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(s.cfg.Interval) * time.Minute)
+	defer ticker.Stop()
+	for {
+		embedded, err := s.Backfill(ctx)
+		if err != nil {
+			log.Println("embedding backfill failed:", err)
+		}
+		log.Printf("embedding backfill finished, %d users embedded\n", embedded)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Load 分页加载所有存储的向量,返回加载的数量
+func (s *EmbeddingService) Load(ctx context.Context) (int, error) {
+	var (
+		loaded int
+		after  int64
+	)
+	for {
+		embeddings, err := s.embedding.ListEmbeddings(ctx, after, loadPageSize)
+		if err != nil {
+			return loaded, err
+		}
+		for _, e := range embeddings {
+			s.index.Upsert(e.UserID, e.Model, e.Vector)
+		}
+		loaded += len(embeddings)
+		if len(embeddings) < loadPageSize {
+			return loaded, nil
+		}
+		after = embeddings[len(embeddings)-1].UserID
+	}
+}
+
+// Backfill 为已经有领域但是没有向量的用户计算向量,不获取仓库,所以画像中没有README摘要
+func (s *EmbeddingService) Backfill(ctx context.Context) (int, error) {
+	embedded := 0
+	for embedded < s.cfg.Budget {
+		if ctx.Err() != nil {
+			return embedded, ctx.Err()
+		}
+		users, err := s.embedding.GetUsersMissingEmbedding(ctx, min(s.cfg.BatchSize, s.cfg.Budget-embedded))
+		if err != nil {
+			return embedded, err
+		}
+		if len(users) == 0 {
+			break
+		}
+		profiles, err := s.storedProfiles(ctx, users)
+		if err != nil {
+			return embedded, err
+		}
+		if err := s.EmbedProfiles(ctx, profiles); err != nil {
+			return embedded, err
+		}
+		embedded += len(users)
+	}
+	return embedded, nil
+}
+
+// EmbedProfiles 计算画像的向量并写入索引,画像没有变化的用户会跳过
+func (s *EmbeddingService) EmbedProfiles(ctx context.Context, profiles []Profile) error {
+	ids := make([]int64, 0, len(profiles))
+	for _, p := range profiles {
+		ids = append(ids, p.User.ID)
+	}
+	fingerprints, err := s.embedding.GetFingerprints(ctx, ids)
+	if err != nil {
+		//获取不到指纹时全部重新计算
+		log.Println("get embedding fingerprints failed:", err)
+	}
+	embeddingModel, err := s.embeddingModel(ctx)
+	if err != nil {
+		return err
+	}
+
+	var pending []*model.Embedding
+	var texts []string
+	for _, p := range profiles {
+		text := s.profileText(p)
+		if text == "" {
+			continue
+		}
+		//指纹包含模型名,更换模型后所有画像都会重新计算
+		fingerprint := textHash(embeddingModel + "\n" + text)
+		if fingerprints[p.User.ID] == fingerprint {
+			continue
+		}
+		pending = append(pending, &model.Embedding{UserID: p.User.ID, Fingerprint: fingerprint})
+		texts = append(texts, text)
+	}
+
+	for start := 0; start < len(pending); start += s.cfg.BatchSize {
+		end := min(start+s.cfg.BatchSize, len(pending))
+		callCtx := ctx
+		if end-start == 1 {
+			callCtx = client.WithUserID(ctx, pending[start].UserID)
+		}
+		res, err := s.l.Embed(callCtx, &llmv1.EmbedRequest{Texts: texts[start:end]})
+		if err != nil {
+			return err
+		}
+		if len(res.Embeddings) != end-start {
+			return fmt.Errorf("embed: expect %d embeddings, got %d", end-start, len(res.Embeddings))
+		}
+		for i, e := range pending[start:end] {
+			e.Model, e.Vector, e.UpdatedAt = res.Model, res.Embeddings[i].GetValues(), time.Now()
+			if err := s.embedding.SaveEmbedding(ctx, e); err != nil {
+				return err
+			}
+			s.index.Upsert(e.UserID, e.Model, e.Vector)
+		}
+	}
+	return nil
+}
+
+// SimilarTo 返回和用户最相似的limit个用户,用户还没有向量时使用存储的画像计算
+func (s *EmbeddingService) SimilarTo(ctx context.Context, requester, userId int64, limit int) ([]model.SimilarUser, error) {
+	e, err := s.embedding.GetEmbedding(ctx, userId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		e, err = s.embedStored(client.WithUserID(ctx, requester), userId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	//结果中包含用户自己
+	results := s.index.Search(e.Model, e.Vector, limit+1)
+	filtered := make([]vector.Result, 0, len(results))
+	for _, r := range results {
+		if r.ID != userId {
+			filtered = append(filtered, r)
+		}
+	}
+	if len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+	return s.similarUsers(ctx, filtered)
+}
+
+// SemanticSearch 按照自由文本的描述搜索画像最接近的用户
+func (s *EmbeddingService) SemanticSearch(ctx context.Context, requester int64, query string, limit int) ([]model.SimilarUser, error) {
+	query = sanitize.Truncate(sanitize.Text(query), maxQueryTokens)
+	if query == "" {
+		return nil, errors.New("query is empty after sanitizing")
+	}
+	res, err := s.l.Embed(client.WithUserID(ctx, requester), &llmv1.EmbedRequest{Texts: []string{query}})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Embeddings) != 1 {
+		return nil, fmt.Errorf("embed: expect 1 embedding, got %d", len(res.Embeddings))
+	}
+	return s.similarUsers(ctx, s.index.Search(res.Model, res.Embeddings[0].GetValues(), limit))
+}
+
+// embedUser 执行重新计算向量的任务,仓库和README摘要使用检查点中上一次获取的仓库
+func (s *EmbeddingService) embedUser(ctx context.Context, payload []byte) error {
+	job, err := decodeUserJob(payload)
+	if err != nil {
+		return err
+	}
+	ctx = client.WithUserID(ctx, job.UserID)
+	u, err := s.user.GetUserByID(ctx, job.UserID)
+	if err != nil {
+		return err
+	}
+	profiles, err := s.storedProfiles(ctx, []model.User{u})
+	if err != nil {
+		return err
+	}
+	checkpoint, err := s.checkpoint.GetCheckpoint(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	for i := range checkpoint.Repos {
+		profiles[0].Repos = append(profiles[0].Repos, &checkpoint.Repos[i])
+	}
+	return s.EmbedProfiles(ctx, profiles)
+}
+
+// embedStored 使用数据库中的信息计算用户的向量
+func (s *EmbeddingService) embedStored(ctx context.Context, userId int64) (model.Embedding, error) {
+	u, err := s.user.GetUserByID(ctx, userId)
+	if err != nil {
+		return model.Embedding{}, err
+	}
+	profiles, err := s.storedProfiles(ctx, []model.User{u})
+	if err != nil {
+		return model.Embedding{}, err
+	}
+	if err := s.EmbedProfiles(ctx, profiles); err != nil {
+		return model.Embedding{}, err
+	}
+	e, err := s.embedding.GetEmbedding(ctx, userId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Embedding{}, ErrNoProfile
+	}
+	return e, err
+}
+
+// storedProfiles 批量获取用户存储的领域和兴趣
+func (s *EmbeddingService) storedProfiles(ctx context.Context, users []model.User) ([]Profile, error) {
+	ids := make([]int64, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	domains, err := s.domain.GetDomainsByUserIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	interests, err := s.interest.GetInterestsByUserIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[int64]int, len(users))
+	profiles := make([]Profile, len(users))
+	for i, u := range users {
+		index[u.ID] = i
+		profiles[i].User = u
+	}
+	for _, d := range domains {
+		p := &profiles[index[d.UserID]]
+		p.Domains = append(p.Domains, d)
+	}
+	for _, v := range interests {
+		p := &profiles[index[v.UserID]]
+		p.Interests = append(p.Interests, v)
+	}
+	return profiles, nil
+}
+
+// similarUsers 按照搜索结果的顺序返回用户,已经删除的用户会被跳过
+func (s *EmbeddingService) similarUsers(ctx context.Context, results []vector.Result) ([]model.SimilarUser, error) {
+	ids := make([]int64, 0, len(results))
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+	users, err := s.user.GetUsersByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]model.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+
+	resp := make([]model.SimilarUser, 0, len(results))
+	for _, r := range results {
+		u, ok := byID[r.ID]
+		if !ok {
+			continue
+		}
+		resp = append(resp, model.SimilarUser{User: u, Similarity: r.Score})
+	}
+	return resp, nil
+}
+
+// profileText 画像的文本,各部分排序之后相同的画像得到相同的指纹
+// 没有简介和领域的用户返回空字符串
+func (s *EmbeddingService) profileText(p Profile) string {
+	var b strings.Builder
+	if bio := sanitize.Text(p.User.Bio); bio != "" {
+		b.WriteString("Bio: " + bio + "\n")
+	}
+	if len(p.Domains) > 0 {
+		domains := domainNames(p.Domains)
+		sort.Strings(domains)
+		b.WriteString("Domains: " + strings.Join(domains, ", ") + "\n")
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+
+	var languages []string
+	for _, v := range p.Interests {
+		if v.Kind == model.InterestKindLanguage {
+			languages = append(languages, v.Name)
+		}
+	}
+	for _, r := range p.Repos {
+		if r.Language != "" {
+			languages = append(languages, r.Language)
+		}
+	}
+	languages = dedupe(languages)
+	sort.Strings(languages)
+	if len(languages) > 0 {
+		b.WriteString("Languages: " + strings.Join(languages, ", ") + "\n")
+	}
+
+	//提交最多的几个仓库的README摘要
+	repos := make([]*model.Repo, 0, len(p.Repos))
+	for _, r := range p.Repos {
+		if r.Readme != "" {
+			repos = append(repos, r)
+		}
+	}
+	sort.SliceStable(repos, func(i, j int) bool {
+		if repos[i].Commit != repos[j].Commit {
+			return repos[i].Commit > repos[j].Commit
+		}
+		return repos[i].Name < repos[j].Name
+	})
+	if len(repos) > s.cfg.MaxRepos {
+		repos = repos[:s.cfg.MaxRepos]
+	}
+	for _, r := range repos {
+		if summary := sanitize.Readme(r.Readme, s.cfg.ReadmeTokens); summary != "" {
+			b.WriteString("Project " + r.Name + ": " + summary + "\n")
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// embeddingModel 不带文本请求一次,获取当前计算向量使用的模型
+func (s *EmbeddingService) embeddingModel(ctx context.Context) (string, error) {
+	res, err := s.l.Embed(ctx, &llmv1.EmbedRequest{})
+	if err != nil {
+		return "", err
+	}
+	return res.Model, nil
+}
+
+func textHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/model"
+)
+
+// Forge 与平台无关的代码托管平台,github,gitlab,gitea 各自实现
+// 所有的 id 都是系统内的用户ID,由 model.ForgeUserID 得到
+type Forge interface {
+	Name() string
+	Enabled() bool
+	GetLoginUrl() string
+	// Authorize 使用 code 完成登录,平台需要自己保存用户的客户端
+	Authorize(ctx context.Context, code string) (model.User, error)
+	// Authorized 用户自己的客户端是否还在,只保存在内存中,过期或者进程重启之后需要重新登录
+	Authorized(id int64) bool
+	GetFollowing(ctx context.Context, id int64) []model.User
+	GetFollowers(ctx context.Context, id int64) []model.User
+	CalculateScore(ctx context.Context, id int64, name string) float64
+	GetAllRepositories(ctx context.Context, loginName string, userId int64) []*model.Repo
+	GetStarredRepositories(ctx context.Context, loginName string, userId int64) []*model.StarredRepo
+	GetAllUserEvents(ctx context.Context, username string, userId int64) ([]model.UserEvent, error)
+}
+
+// IncrementalForge 支持增量同步的平台,不支持的平台每次同步都全部重新获取
+type IncrementalForge interface {
+	Forge
+	// ListFollow 获取用户的following(following为true)或者followers,只包含ID和登录名
+	ListFollow(ctx context.Context, id int64, following bool) ([]model.User, error)
+	// GetUser 获取任意用户的详细信息,id是发起请求的用户
+	GetUser(ctx context.Context, id int64, login string) (model.User, error)
+	// ListRepositories 获取和 GetAllRepositories 相同的仓库,不包括readme和提交数,但是包括最近推送的时间
+	ListRepositories(ctx context.Context, loginName string, userId int64) ([]*model.Repo, error)
+	// FillRepository 获取仓库的readme和用户的提交数
+	FillRepository(ctx context.Context, loginName string, userId int64, repo *model.Repo)
+	// GetUserEventsSince 获取比cursor新的事件,按照仓库分类统计,同时返回新的游标
+	GetUserEventsSince(ctx context.Context, username string, userId int64, cursor model.EventCursor) ([]model.UserEvent, model.EventCursor, error)
+}
+
+// PublicEventsForge 可以不使用用户自己的客户端获取任意用户近期公开事件的平台
+type PublicEventsForge interface {
+	Forge
+	// GetPublicUserEvents 获取用户最近一页的公开事件,按照仓库分类统计,只包含仓库名称
+	GetPublicUserEvents(ctx context.Context, username string) ([]model.UserEvent, error)
+}
+
+// ErrLoginExpired 用户自己的客户端已经不在了,需要用户重新登录
+var ErrLoginExpired = errors.New("login expired, please login again")
+
+// 用于依赖注入区分不同的平台
+type GithubForge interface {
+	Forge
+}
+type GitLabForge interface {
+	Forge
+}
+type GiteaForge interface {
+	Forge
+}
+
+// ForgeRegistry 按照名称获取代码托管平台
+type ForgeRegistry struct {
+	forges map[string]Forge
+}
+
+func NewForgeRegistry(github GithubForge, gitlab GitLabForge, gitea GiteaForge) *ForgeRegistry {
+	r := &ForgeRegistry{forges: make(map[string]Forge)}
+	for _, f := range []Forge{github, gitlab, gitea} {
+		//没有配置的平台不会注册
+		if f.Enabled() {
+			r.forges[f.Name()] = f
+		}
+	}
+	return r
+}
+
+// GetForge 获取平台,名称为空时默认是github
+func (r *ForgeRegistry) GetForge(name string) (Forge, error) {
+	if name == "" {
+		name = model.ForgeGitHub
+	}
+	f, ok := r.forges[name]
+	if !ok {
+		return nil, fmt.Errorf("forge %s is not supported", name)
+	}
+	return f, nil
+}
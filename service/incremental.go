@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"github.com/GitEval/GitEval-Backend/model"
+	"log"
+	"time"
+)
+
+// 增量同步,每个用户记录一个检查点,重新登录时只获取有变化的数据
+// 关注的用户最近同步过时直接使用存储的信息,仓库最近推送的时间没有变化时不再获取readme和提交数,事件只处理检查点之后的部分
+
+// eventWindow 检查点中保留的事件的时间范围,和github事件接口能获取到的范围相同,评价使用的是近期的活动而不是累计的活动
+const eventWindow = 90 * 24 * time.Hour
+
+type CheckpointDAOProxy interface {
+	GetCheckpoint(ctx context.Context, userID int64) (model.SyncCheckpoint, error)
+	SaveCheckpointRepos(ctx context.Context, userID int64, repos []model.Repo) error
+	SaveCheckpointEvents(ctx context.Context, userID int64, from, to model.EventCursor, events []model.UserEvent) (bool, error)
+}
+
+// followUsers 获取用户的following或者followers,fetched表示对应的用户是否是重新拉取的
+// 距离上次同步不超过 staleAfter 的用户直接使用存储的信息,包括分数
+// 列表获取失败时返回错误,避免存储一个空的关注关系
+func (s *UserService) followUsers(ctx context.Context, forge Forge, userId int64, following bool) (users []model.User, fetched []bool, err error) {
+	f, ok := forge.(IncrementalForge)
+	if !ok {
+		if following {
+			users = forge.GetFollowing(ctx, userId)
+		} else {
+			users = forge.GetFollowers(ctx, userId)
+		}
+		fetched = make([]bool, len(users))
+		for i := range fetched {
+			fetched[i] = true
+		}
+		return users, fetched, nil
+	}
+
+	list, err := f.ListFollow(ctx, userId, following)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]int64, 0, len(list))
+	for _, v := range list {
+		ids = append(ids, v.ID)
+	}
+	stored, err := s.user.GetUsersByIds(ctx, ids)
+	if err != nil {
+		//读取失败时全部重新拉取
+		log.Println("get stored users failed:", err)
+	}
+	storedMap := make(map[int64]model.User, len(stored))
+	for _, v := range stored {
+		storedMap[v.ID] = v
+	}
+
+	freshAfter := time.Now().Add(-time.Duration(s.refresh.StaleAfter) * time.Hour)
+	for _, v := range list {
+		if u, ok := storedMap[v.ID]; ok && u.LastSyncedAt != nil && u.LastSyncedAt.After(freshAfter) {
+			users = append(users, u)
+			fetched = append(fetched, false)
+			continue
+		}
+		u, err := f.GetUser(ctx, userId, v.LoginName)
+		if err != nil {
+			log.Println("get user details failed:", err)
+			continue
+		}
+		users = append(users, u)
+		fetched = append(fetched, true)
+	}
+	return users, fetched, nil
+}
+
+// getRepositories 获取用户的仓库,最近推送的时间没有变化的仓库使用检查点中的readme和提交数
+func (s *UserService) getRepositories(ctx context.Context, u model.User) ([]*model.Repo, error) {
+	forge, err := s.forges.GetForge(u.Forge)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := forge.(IncrementalForge)
+	if !ok {
+		return forge.GetAllRepositories(ctx, u.LoginName, u.ID), nil
+	}
+
+	repos, err := f.ListRepositories(ctx, u.LoginName, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint, err := s.checkpoint.GetCheckpoint(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	previous := make(map[string]model.Repo, len(checkpoint.Repos))
+	for _, v := range checkpoint.Repos {
+		previous[v.Name] = v
+	}
+
+	snapshot := make([]model.Repo, 0, len(repos))
+	for _, repo := range repos {
+		if p, ok := previous[repo.Name]; ok && !repo.PushedAt.IsZero() && p.PushedAt.Equal(repo.PushedAt) {
+			repo.Readme, repo.Commit = p.Readme, p.Commit
+		} else {
+			f.FillRepository(ctx, u.LoginName, u.ID, repo)
+		}
+		snapshot = append(snapshot, *repo)
+	}
+	if err := s.checkpoint.SaveCheckpointRepos(ctx, u.ID, snapshot); err != nil {
+		log.Println("save checkpoint repos failed:", err)
+	}
+	return repos, nil
+}
+
+// getUserEvents 获取用户按照仓库统计的事件
+// 支持增量同步的平台只处理检查点之后的事件,按照仓库和日期累计到检查点中,返回的是最近 eventWindow 内的统计结果
+func (s *UserService) getUserEvents(ctx context.Context, forge Forge, u model.User) ([]model.UserEvent, error) {
+	f, ok := forge.(IncrementalForge)
+	if !ok {
+		return forge.GetAllUserEvents(ctx, u.LoginName, u.ID)
+	}
+
+	checkpoint, err := s.checkpoint.GetCheckpoint(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	events, cursor, err := f.GetUserEventsSince(ctx, u.LoginName, u.ID, checkpoint.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if len(events) == 0 {
+		return recentUserEvents(checkpoint.Events, now), nil
+	}
+
+	merged := mergeUserEvents(checkpoint.Events, events, now)
+	saved, err := s.checkpoint.SaveCheckpointEvents(ctx, u.ID, checkpoint.Cursor, cursor, merged)
+	if err != nil {
+		log.Println("save checkpoint events failed:", err)
+		return recentUserEvents(merged, now), nil
+	}
+	if !saved {
+		//其他同步已经移动了游标,使用它累计的结果,避免重复累计
+		checkpoint, err = s.checkpoint.GetCheckpoint(ctx, u.ID)
+		if err != nil {
+			return nil, err
+		}
+		return recentUserEvents(checkpoint.Events, now), nil
+	}
+	return recentUserEvents(merged, now), nil
+}
+
+// recentUserEvents 检查点中最近 eventWindow 内的事件,按照仓库统计
+func recentUserEvents(events []model.UserEvent, now time.Time) []model.UserEvent {
+	return model.SumUserEventsByRepo(trimUserEvents(events, now))
+}
+
+// trimUserEvents 去掉 eventWindow 之前的事件
+// 没有日期的是之前只按照仓库累计的结果,无法区分时间,同样去掉
+func trimUserEvents(events []model.UserEvent, now time.Time) []model.UserEvent {
+	since := now.Add(-eventWindow).UTC().Format(time.DateOnly)
+	resp := make([]model.UserEvent, 0, len(events))
+	for _, v := range events {
+		if v.Day != "" && v.Day >= since {
+			resp = append(resp, v)
+		}
+	}
+	return resp
+}
+
+// mergeUserEvents 把新的事件按照仓库和日期累计到之前的结果中,新的仓库信息更完整时替换,同时去掉过期的事件
+func mergeUserEvents(previous, events []model.UserEvent, now time.Time) []model.UserEvent {
+	resp := trimUserEvents(previous, now)
+	index := make(map[[2]string]int, len(resp))
+	for i, v := range resp {
+		index[[2]string{v.Repo.Name, v.Day}] = i
+	}
+	for _, v := range trimUserEvents(events, now) {
+		key := [2]string{v.Repo.Name, v.Day}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(resp)
+			resp = append(resp, v)
+			continue
+		}
+		resp[i].PushCount += v.PushCount
+		resp[i].IssuesCount += v.IssuesCount
+		resp[i].PullRequestCount += v.PullRequestCount
+		//只有用户自己的仓库有完整的信息
+		if v.Repo != (model.RepoInfo{Name: v.Repo.Name}) {
+			resp[i].Repo = v.Repo
+		}
+	}
+	return resp
+}
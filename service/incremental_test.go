@@ -0,0 +1,150 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GitEval/GitEval-Backend/model"
+)
+
+func TestTrimUserEvents(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-eventWindow).Format(time.DateOnly)
+	before := now.Add(-eventWindow - 24*time.Hour).Format(time.DateOnly)
+
+	tests := []struct {
+		name   string
+		events []model.UserEvent
+		want   []model.UserEvent
+	}{
+		{
+			name:   "empty",
+			events: nil,
+			want:   []model.UserEvent{},
+		},
+		{
+			name: "keeps events inside the window",
+			events: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "a"}, Day: since, PushCount: 1},
+				{Repo: model.RepoInfo{Name: "b"}, Day: "2024-06-30", PushCount: 2},
+			},
+			want: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "a"}, Day: since, PushCount: 1},
+				{Repo: model.RepoInfo{Name: "b"}, Day: "2024-06-30", PushCount: 2},
+			},
+		},
+		{
+			name: "drops expired and undated events",
+			events: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "a"}, Day: before, PushCount: 1},
+				{Repo: model.RepoInfo{Name: "b"}, PushCount: 2},
+				{Repo: model.RepoInfo{Name: "c"}, Day: "2024-06-01", PushCount: 3},
+			},
+			want: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "c"}, Day: "2024-06-01", PushCount: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trimUserEvents(tt.events, now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("trimUserEvents() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeUserEvents(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	full := model.RepoInfo{Name: "a", Description: "mine", StargazersCount: 5}
+
+	tests := []struct {
+		name     string
+		previous []model.UserEvent
+		events   []model.UserEvent
+		want     []model.UserEvent
+	}{
+		{
+			name: "no previous events",
+			events: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "a"}, Day: "2024-06-29", PushCount: 1},
+			},
+			want: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "a"}, Day: "2024-06-29", PushCount: 1},
+			},
+		},
+		{
+			name: "sums the same repo and day",
+			previous: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "a"}, Day: "2024-06-29", PushCount: 1, IssuesCount: 1},
+			},
+			events: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "a"}, Day: "2024-06-29", PushCount: 2, PullRequestCount: 3},
+			},
+			want: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "a"}, Day: "2024-06-29", PushCount: 3, IssuesCount: 1, PullRequestCount: 3},
+			},
+		},
+		{
+			name: "different days stay apart",
+			previous: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "a"}, Day: "2024-06-28", PushCount: 1},
+			},
+			events: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "a"}, Day: "2024-06-29", PushCount: 2},
+			},
+			want: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "a"}, Day: "2024-06-28", PushCount: 1},
+				{Repo: model.RepoInfo{Name: "a"}, Day: "2024-06-29", PushCount: 2},
+			},
+		},
+		{
+			name: "fuller repo info replaces the bare one",
+			previous: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "a"}, Day: "2024-06-29", PushCount: 1},
+			},
+			events: []model.UserEvent{
+				{Repo: full, Day: "2024-06-29", PushCount: 1},
+			},
+			want: []model.UserEvent{
+				{Repo: full, Day: "2024-06-29", PushCount: 2},
+			},
+		},
+		{
+			name: "bare repo info keeps the fuller one",
+			previous: []model.UserEvent{
+				{Repo: full, Day: "2024-06-29", PushCount: 1},
+			},
+			events: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "a"}, Day: "2024-06-29", PushCount: 1},
+			},
+			want: []model.UserEvent{
+				{Repo: full, Day: "2024-06-29", PushCount: 2},
+			},
+		},
+		{
+			name: "expired events are dropped from both sides",
+			previous: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "old"}, Day: "2024-01-01", PushCount: 1},
+				{Repo: model.RepoInfo{Name: "legacy"}, PushCount: 1},
+			},
+			events: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "old"}, Day: "2024-01-02", PushCount: 1},
+				{Repo: model.RepoInfo{Name: "a"}, Day: "2024-06-29", PushCount: 1},
+			},
+			want: []model.UserEvent{
+				{Repo: model.RepoInfo{Name: "a"}, Day: "2024-06-29", PushCount: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeUserEvents(tt.previous, tt.events, now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeUserEvents() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"github.com/GitEval/GitEval-Backend/conf"
+	"github.com/GitEval/GitEval-Backend/model"
+	"log"
+	"time"
+)
+
+// 后台批量推断关注关系中存储的用户,让整个关系图都可以被搜索到
+
+type BatchInferrer interface {
+	InferBatch(ctx context.Context, users []model.User) error
+}
+
+type InferenceGithubProxy interface {
+	GetRateRemaining(ctx context.Context) (int, error)
+}
+
+type InferenceService struct {
+	user     UserDAOProxy
+	inferrer BatchInferrer
+	g        InferenceGithubProxy
+	cfg      *conf.BatchInferenceConfig
+}
+
+func NewInferenceService(user UserDAOProxy, inferrer BatchInferrer, g InferenceGithubProxy, cfg *conf.BatchInferenceConfig) *InferenceService {
+	return &InferenceService{
+		user:     user,
+		inferrer: inferrer,
+		g:        g,
+		cfg:      cfg,
+	}
+}
+
+// Start 按照配置的间隔定时运行,没有开启时直接返回
+func (s *InferenceService) Start(ctx context.Context) {
+	if !s.cfg.Enable {
+		return
+	}
+	ticker := time.NewTicker(time.Duration(s.cfg.Interval) * time.Minute)
+	defer ticker.Stop()
+	for {
+		inferred, err := s.Run(ctx)
+		if err != nil {
+			log.Println("batch inference failed:", err)
+		}
+		log.Printf("batch inference finished, %d users inferred\n", inferred)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run 每批推断BatchSize个用户,直到没有缺少推断的用户,或者用完每次运行的预算,返回推断的用户数
+func (s *InferenceService) Run(ctx context.Context) (int, error) {
+	inferred := 0
+	for inferred < s.cfg.Budget {
+		if ctx.Err() != nil {
+			return inferred, ctx.Err()
+		}
+		// 推断领域需要获取用户的仓库,同样需要给登录用户留出github的额度
+		if !s.hasQuota(ctx) {
+			log.Println("batch inference stopped: github rate limit is almost exhausted")
+			break
+		}
+
+		users, err := s.user.GetUsersMissingInference(ctx, min(s.cfg.BatchSize, s.cfg.Budget-inferred))
+		if err != nil {
+			return inferred, err
+		}
+		if len(users) == 0 {
+			break
+		}
+		if err := s.inferrer.InferBatch(ctx, users); err != nil {
+			return inferred, err
+		}
+		inferred += len(users)
+	}
+	return inferred, nil
+}
+
+func (s *InferenceService) hasQuota(ctx context.Context) bool {
+	remaining, err := s.g.GetRateRemaining(ctx)
+	if err != nil {
+		log.Println("get github rate limit failed:", err)
+		return false
+	}
+	return remaining > s.cfg.MinRemaining
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/conf"
+	"log"
+	"sync"
+	"time"
+)
+
+// 同一个用户的同一种同步或者推断同时只能有一个在执行
+// 进程内用一个集合去重,多个实例之间通过redis的分布式锁去重
+
+// 需要加锁的工作,不同种类的工作之间可以并行
+const (
+	LockSync       = "sync"   //拉取关注关系,刷新资料和分数
+	LockDomain     = "domain" //推断领域
+	LockNation     = "nation" //推断国籍
+	LockEvaluation = "evaluation"
+)
+
+// ErrAlreadyRunning 同一个用户的同一种工作已经在执行
+var ErrAlreadyRunning = errors.New("already running for this user")
+
+type LockCacheProxy interface {
+	TryLock(ctx context.Context, key, token string, expire time.Duration) (bool, error)
+	Unlock(ctx context.Context, key, token string) error
+	RenewLock(ctx context.Context, key, token string, expire time.Duration) (bool, error)
+}
+
+// UserLocker 获取用户某种工作的锁,已经在执行时返回 ErrAlreadyRunning
+type UserLocker interface {
+	Lock(ctx context.Context, userId int64, kind string) (unlock func(), err error)
+}
+
+type LockService struct {
+	cache   LockCacheProxy
+	cfg     *conf.LockConfig
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+func NewLockService(cache LockCacheProxy, cfg *conf.LockConfig) *LockService {
+	return &LockService{
+		cache:   cache,
+		cfg:     cfg,
+		running: make(map[string]bool),
+	}
+}
+
+// Lock 先在进程内去重,再获取分布式锁,返回的unlock需要在工作结束之后调用
+// 持有期间定时延长锁的过期时间,过期时间只决定持有锁的实例退出之后多久释放
+// redis出错时只保证进程内不重复,避免redis故障时所有的同步都无法执行
+func (s *LockService) Lock(ctx context.Context, userId int64, kind string) (func(), error) {
+	key := fmt.Sprintf("user:%d:%s", userId, kind)
+	s.mu.Lock()
+	if s.running[key] {
+		s.mu.Unlock()
+		return nil, ErrAlreadyRunning
+	}
+	s.running[key] = true
+	s.mu.Unlock()
+	release := func() {
+		s.mu.Lock()
+		delete(s.running, key)
+		s.mu.Unlock()
+	}
+
+	token, expire := randomToken(), time.Duration(s.cfg.Expire)*time.Second
+	ok, err := s.cache.TryLock(ctx, key, token, expire)
+	if err != nil {
+		log.Println("try lock failed:", err)
+		return release, nil
+	}
+	if !ok {
+		release()
+		return nil, ErrAlreadyRunning
+	}
+	//工作被取消时也要续期和释放锁
+	ctx = context.WithoutCancel(ctx)
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		s.renew(ctx, key, token, expire, stop)
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-stopped
+			if err := s.cache.Unlock(ctx, key, token); err != nil {
+				log.Println("unlock failed:", err)
+			}
+			release()
+		})
+	}, nil
+}
+
+// renew 每过三分之一的过期时间延长一次锁,直到stop关闭或者锁已经丢失
+func (s *LockService) renew(ctx context.Context, key, token string, expire time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(expire / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		ok, err := s.cache.RenewLock(ctx, key, token, expire)
+		if err != nil {
+			log.Println("renew lock failed:", err)
+			continue
+		}
+		if !ok {
+			log.Printf("lock %s lost before unlock\n", key)
+			return
+		}
+	}
+}
+
+// randomToken 随机生成锁的持有者标识或者websocket的票据
+func randomToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
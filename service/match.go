@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/client"
+	llmv1 "github.com/GitEval/GitEval-Backend/client/gen"
+	"github.com/GitEval/GitEval-Backend/model"
+	"github.com/GitEval/GitEval-Backend/pkg/sanitize"
+	"log"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 根据职位描述从存储的用户中匹配候选人
+
+const (
+	// matchPoolSize 参与排序的候选人的最大数量
+	matchPoolSize = 500
+	// maxKnownDomains 提取要求时提供给llm的已有领域数
+	maxKnownDomains = 100
+	// maxJobTokens 职位描述最多保留的token数
+	maxJobTokens = 2000
+)
+
+// 重合度中各部分的权重,没有提取到的部分不参与计算
+const (
+	domainWeight   = 0.5
+	languageWeight = 0.3
+	skillWeight    = 0.2
+	// scoreWeight 排序时评分所占的权重
+	scoreWeight = 0.2
+)
+
+type MatchService struct {
+	user     UserDAOProxy
+	domain   DomainDAOProxy
+	interest InterestDAOProxy
+	l        llmv1.LLMServiceClient
+}
+
+func NewMatchService(user UserDAOProxy, domain DomainDAOProxy, interest InterestDAOProxy, l llmv1.LLMServiceClient) *MatchService {
+	return &MatchService{
+		user:     user,
+		domain:   domain,
+		interest: interest,
+		l:        l,
+	}
+}
+
+// MatchJob 从职位描述中提取要求,按照和用户的领域,语言以及技术栈的重合度结合评分排序,返回前limit个候选人
+func (s *MatchService) MatchJob(ctx context.Context, userId int64, description string, nation *string, minScore float64, limit int) (model.JobRequirements, []model.Candidate, error) {
+	//已有的领域作为提示,提取的领域才能和用户的领域对应上
+	known, err := s.domain.GetDomainNames(ctx, maxKnownDomains)
+	if err != nil {
+		log.Println("get domain names failed:", err)
+	}
+	res, err := s.l.ExtractRequirements(client.WithUserID(ctx, userId), &llmv1.ExtractRequirementsRequest{
+		JobDescription: sanitize.Truncate(strings.TrimSpace(description), maxJobTokens),
+		KnownDomains:   known,
+	})
+	if err != nil {
+		return model.JobRequirements{}, nil, err
+	}
+	req := model.JobRequirements{
+		Domains:   dedupe(res.Domains),
+		Languages: dedupe(res.Languages),
+		Skills:    dedupe(res.Skills),
+	}
+
+	//数据库中按照同样的规则去掉分隔符比较,领域允许一个包含另一个
+	var names []string
+	for _, v := range append(append([]string{}, req.Languages...), req.Skills...) {
+		names = append(names, normalizeSkill(v))
+	}
+	domains := make([]string, 0, len(req.Domains))
+	for _, v := range req.Domains {
+		domains = append(domains, normalizeSkill(v))
+	}
+	users, err := s.user.GetMatchCandidates(ctx, domains, names, nation, minScore, matchPoolSize)
+	if err != nil {
+		return req, nil, err
+	}
+	if len(users) == 0 {
+		return req, []model.Candidate{}, nil
+	}
+
+	ids := make([]int64, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	userDomains, err := s.domain.GetDomainsByUserIds(ctx, ids)
+	if err != nil {
+		return req, nil, err
+	}
+	userInterests, err := s.interest.GetInterestsByUserIds(ctx, ids)
+	if err != nil {
+		return req, nil, err
+	}
+	domainsOf := make(map[int64][]model.Domain, len(users))
+	for _, d := range userDomains {
+		domainsOf[d.UserID] = append(domainsOf[d.UserID], d)
+	}
+	interestsOf := make(map[int64][]model.Interest, len(users))
+	for _, v := range userInterests {
+		interestsOf[v.UserID] = append(interestsOf[v.UserID], v)
+	}
+
+	//候选人先按照交集排序,评分最高的不一定在第一个
+	var maxScore float64
+	for _, u := range users {
+		maxScore = max(maxScore, u.Score)
+	}
+	candidates := make([]model.Candidate, 0, len(users))
+	for _, u := range users {
+		c := matchCandidate(req, u, domainsOf[u.ID], interestsOf[u.ID], maxScore)
+		if c.Match > 0 {
+			candidates = append(candidates, c)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Rank > candidates[j].Rank
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return req, candidates, nil
+}
+
+// matchCandidate 计算用户和要求的重合度,并给出匹配的原因
+func matchCandidate(req model.JobRequirements, u model.User, domains []model.Domain, interests []model.Interest, maxScore float64) model.Candidate {
+	var (
+		total, parts float64
+		reasons      []string
+	)
+
+	if len(req.Domains) > 0 {
+		var sum float64
+		for _, want := range req.Domains {
+			for _, d := range domains {
+				if domainMatches(want, d.Domain) {
+					sum += float64(d.Confidence)
+					reasons = append(reasons, fmt.Sprintf("works in %s (confidence %.2f)", d.Domain, d.Confidence))
+					break
+				}
+			}
+		}
+		total += domainWeight * sum / float64(len(req.Domains))
+		parts += domainWeight
+	}
+
+	if len(req.Languages) > 0 {
+		matched := matchInterests(req.Languages, interests, model.InterestKindLanguage)
+		if len(matched) > 0 {
+			reasons = append(reasons, "uses "+strings.Join(matched, ", "))
+		}
+		total += languageWeight * float64(len(matched)) / float64(len(req.Languages))
+		parts += languageWeight
+	}
+
+	if len(req.Skills) > 0 {
+		matched := matchInterests(req.Skills, interests, model.InterestKindTopic)
+		if len(matched) > 0 {
+			reasons = append(reasons, "tech stack includes "+strings.Join(matched, ", "))
+		}
+		total += skillWeight * float64(len(matched)) / float64(len(req.Skills))
+		parts += skillWeight
+	}
+
+	c := model.Candidate{User: u, Reasons: reasons}
+	if parts == 0 {
+		return c
+	}
+	c.Match = total / parts
+	c.Rank = (1-scoreWeight)*c.Match + scoreWeight*normalizeScore(u.Score, maxScore)
+	if c.Match > 0 {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("score %.1f", u.Score))
+	}
+	return c
+}
+
+// matchInterests 返回要求中在用户兴趣里出现的部分
+func matchInterests(wants []string, interests []model.Interest, kind string) []string {
+	var matched []string
+	for _, want := range wants {
+		key := normalizeSkill(want)
+		for _, v := range interests {
+			if v.Kind == kind && normalizeSkill(v.Name) == key {
+				matched = append(matched, want)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// domainMatches 领域是llm生成的,允许一个包含另一个,例如 Backend 和 Backend Development
+func domainMatches(want, have string) bool {
+	want, have = normalizeSkill(want), normalizeSkill(have)
+	if want == "" || have == "" {
+		return false
+	}
+	return strings.Contains(have, want) || strings.Contains(want, have)
+}
+
+// normalizeSkill 忽略大小写和分隔符,例如 Node.js 和 nodejs, machine learning 和 machine-learning
+func normalizeSkill(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '#' {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+func normalizeScore(score, maxScore float64) float64 {
+	if maxScore <= 0 {
+		return 0
+	}
+	return min(max(score/maxScore, 0), 1)
+}
+
+// dedupe 去掉空的和重复的项,保持原来的顺序
+func dedupe(items []string) []string {
+	seen := make(map[string]bool, len(items))
+	resp := make([]string, 0, len(items))
+	for _, v := range items {
+		v = strings.TrimSpace(v)
+		key := normalizeSkill(v)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		resp = append(resp, v)
+	}
+	return resp
+}
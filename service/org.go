@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"github.com/GitEval/GitEval-Backend/model"
+	"log"
+)
+
+// 有关组织的服务
+
+type OrganizationDAOProxy interface {
+	SaveOrganizations(ctx context.Context, orgs []model.Organization) error
+	GetOrganizationByLogin(ctx context.Context, login string) (model.Organization, error)
+	GetOrganizationsByUserID(ctx context.Context, userID int64) ([]model.Organization, error)
+	CreateMemberships(ctx context.Context, memberships []model.Membership) error
+	ReplaceMemberships(ctx context.Context, orgID int64, memberships []model.Membership) error
+	GetCountOfMembers(ctx context.Context, orgID int64) (int64, error)
+	GetMembersJoinMembership(ctx context.Context, orgID int64, page int, pageSize int) ([]model.User, error)
+	SaveOrgRepos(ctx context.Context, orgID int64, repos []model.OrgRepo) error
+	GetOrgRepos(ctx context.Context, orgID int64) ([]model.OrgRepo, error)
+	GetOrgDomains(ctx context.Context, orgID int64) ([]model.OrgDomain, error)
+}
+
+type OrgGithubProxy interface {
+	GetOrganizations(ctx context.Context, userID int64) ([]model.Organization, error)
+	GetOrganization(ctx context.Context, login string, userID int64) (model.Organization, error)
+	GetOrganizationMembers(ctx context.Context, login string, userID int64) ([]model.User, error)
+	GetOrganizationRepos(ctx context.Context, org model.Organization, userID int64) []model.OrgRepo
+	CalculateScore(ctx context.Context, id int64, name string) float64
+}
+
+type OrgService struct {
+	org  OrganizationDAOProxy
+	user UserDAOProxy
+	tx   Transaction
+	g    OrgGithubProxy
+}
+
+func NewOrgService(org OrganizationDAOProxy, user UserDAOProxy, transaction Transaction, g OrgGithubProxy) *OrgService {
+	return &OrgService{
+		org:  org,
+		user: user,
+		tx:   transaction,
+		g:    g,
+	}
+}
+
+// SyncUserOrganizations 存储用户所属的组织以及用户和组织的关系
+func (s *OrgService) SyncUserOrganizations(ctx context.Context, u model.User) error {
+	orgs, err := s.g.GetOrganizations(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+
+	memberships := make([]model.Membership, 0, len(orgs))
+	for i := range orgs {
+		//组织的分数只计算组织自己的仓库
+		orgs[i].Score = s.g.CalculateScore(ctx, u.ID, orgs[i].Login)
+		memberships = append(memberships, model.Membership{OrgID: orgs[i].ID, UserID: u.ID})
+	}
+
+	err = s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.org.SaveOrganizations(ctx, orgs); err != nil {
+			return err
+		}
+		return s.org.CreateMemberships(ctx, memberships)
+	})
+	if err != nil {
+		log.Println("sync user organizations failed")
+		return err
+	}
+	return nil
+}
+
+// RefreshOrganization 重新拉取组织的信息,公开成员和仓库,成员会同时存入users表
+// 组织的成员关系替换为这次拉取到的成员,已经离开组织的用户会被移除
+func (s *OrgService) RefreshOrganization(ctx context.Context, login string, userId int64) (model.Organization, error) {
+	org, err := s.g.GetOrganization(ctx, login, userId)
+	if err != nil {
+		return model.Organization{}, err
+	}
+	org.Score = s.g.CalculateScore(ctx, userId, org.Login)
+
+	members, err := s.g.GetOrganizationMembers(ctx, org.Login, userId)
+	if err != nil {
+		return model.Organization{}, err
+	}
+	memberships := make([]model.Membership, 0, len(members))
+	for i := range members {
+		members[i].Score = s.g.CalculateScore(ctx, userId, members[i].LoginName)
+		memberships = append(memberships, model.Membership{OrgID: org.ID, UserID: members[i].ID})
+	}
+
+	repos := s.g.GetOrganizationRepos(ctx, org, userId)
+
+	err = s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.org.SaveOrganizations(ctx, []model.Organization{org}); err != nil {
+			return err
+		}
+		if err := s.user.CreateUsers(ctx, members); err != nil {
+			return err
+		}
+		if err := s.org.ReplaceMemberships(ctx, org.ID, memberships); err != nil {
+			return err
+		}
+		return s.org.SaveOrgRepos(ctx, org.ID, repos)
+	})
+	if err != nil {
+		log.Println("refresh organization failed")
+		return model.Organization{}, err
+	}
+	return org, nil
+}
+
+// GetUserOrganizations 获取用户所属的组织
+func (s *OrgService) GetUserOrganizations(ctx context.Context, userId int64) ([]model.Organization, error) {
+	return s.org.GetOrganizationsByUserID(ctx, userId)
+}
+
+// GetOrgProfile 获取组织的画像,包括成员数,组织的top仓库以及成员的领域分布
+func (s *OrgService) GetOrgProfile(ctx context.Context, login string) (model.OrgProfile, error) {
+	org, err := s.org.GetOrganizationByLogin(ctx, login)
+	if err != nil {
+		return model.OrgProfile{}, err
+	}
+	cnt, err := s.org.GetCountOfMembers(ctx, org.ID)
+	if err != nil {
+		return model.OrgProfile{}, err
+	}
+	repos, err := s.org.GetOrgRepos(ctx, org.ID)
+	if err != nil {
+		return model.OrgProfile{}, err
+	}
+	domains, err := s.org.GetOrgDomains(ctx, org.ID)
+	if err != nil {
+		return model.OrgProfile{}, err
+	}
+	return model.OrgProfile{
+		Org:         org,
+		MemberCount: cnt,
+		TopRepos:    repos,
+		Domains:     domains,
+	}, nil
+}
+
+// GetOrgMembers 按照分数从高到低获取组织的成员
+func (s *OrgService) GetOrgMembers(ctx context.Context, login string, page int, pageSize int) ([]model.User, error) {
+	org, err := s.org.GetOrganizationByLogin(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+	return s.org.GetMembersJoinMembership(ctx, org.ID, page, pageSize)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"github.com/GitEval/GitEval-Backend/conf"
+	"github.com/GitEval/GitEval-Backend/model"
+	"log"
+	"time"
+)
+
+// 无需登录,通过github登录名查询任意用户的服务
+
+type PublicGithubProxy interface {
+	GetUserByLogin(ctx context.Context, login string) (model.User, error)
+	CalculateScore(ctx context.Context, id int64, name string) float64
+}
+
+type PublicCacheProxy interface {
+	SetPublicUser(ctx context.Context, login string, userID int64, expire time.Duration) error
+	GetPublicUser(ctx context.Context, login string) (int64, bool, error)
+}
+
+type UserInferrer interface {
+	InferUser(ctx context.Context, userId int64) error
+}
+
+type PublicService struct {
+	user     UserDAOProxy
+	domain   DomainDAOProxy
+	interest InterestDAOProxy
+	g        PublicGithubProxy
+	cache    PublicCacheProxy
+	inferrer UserInferrer
+	cfg      *conf.PublicConfig
+}
+
+func NewPublicService(user UserDAOProxy, domain DomainDAOProxy, interest InterestDAOProxy, g PublicGithubProxy, cache PublicCacheProxy, inferrer UserInferrer, cfg *conf.PublicConfig) *PublicService {
+	return &PublicService{
+		user:     user,
+		domain:   domain,
+		interest: interest,
+		g:        g,
+		cache:    cache,
+		inferrer: inferrer,
+		cfg:      cfg,
+	}
+}
+
+// GetPublicUser 根据github登录名获取用户信息并计算分数
+// 缓存期内直接从数据库读取,否则重新从github拉取,infer为true时会异步推断国籍和领域
+// 命中缓存时只有还没有国籍或者领域的用户才会推断
+func (s *PublicService) GetPublicUser(ctx context.Context, login string, infer bool) (model.User, error) {
+	userID, exist, err := s.cache.GetPublicUser(ctx, login)
+	if err != nil {
+		log.Println("get public user cache failed:", err)
+	}
+	if exist {
+		user, err := s.user.GetUserByID(ctx, userID)
+		if err == nil {
+			if infer && (user.Nationality == "" || len(s.GetDomains(ctx, user.ID)) == 0) {
+				s.infer(ctx, user.ID)
+			}
+			return user, nil
+		}
+	}
+
+	u, err := s.g.GetUserByLogin(ctx, login)
+	if err != nil {
+		return model.User{}, err
+	}
+	u.Score = s.g.CalculateScore(ctx, u.ID, u.LoginName)
+
+	//已经存在的用户不会覆盖国籍和评价
+	if err := s.user.CreateUsers(ctx, []model.User{u}); err != nil {
+		return model.User{}, err
+	}
+	if err := s.cache.SetPublicUser(ctx, login, u.ID, time.Duration(s.cfg.CacheTimeout)*time.Minute); err != nil {
+		log.Println("set public user cache failed:", err)
+	}
+
+	if infer {
+		s.infer(ctx, u.ID)
+	}
+
+	return s.user.GetUserByID(ctx, u.ID)
+}
+
+// infer 提交推断的任务,失败时只记录日志
+func (s *PublicService) infer(ctx context.Context, userId int64) {
+	if err := s.inferrer.InferUser(ctx, userId); err != nil {
+		log.Println("enqueue inference failed:", err)
+	}
+}
+
+// GetDomains 返回用户已经存储的领域
+func (s *PublicService) GetDomains(ctx context.Context, userId int64) []model.Domain {
+	domains, err := s.domain.GetDomainById(ctx, userId)
+	if err != nil {
+		return nil
+	}
+	return domains
+}
+
+// GetInterests 返回用户已经存储的兴趣向量
+func (s *PublicService) GetInterests(ctx context.Context, userId int64) []model.Interest {
+	interests, err := s.interest.GetInterestsById(ctx, userId)
+	if err != nil {
+		return nil
+	}
+	return interests
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/conf"
+	"github.com/GitEval/GitEval-Backend/model"
+	"log"
+	"sync"
+	"time"
+)
+
+// 持久化的后台任务队列,替代登录之后直接启动的协程
+// 任务存储在mysql中,失败时按照指数退避重试,超过最大次数之后放入死信
+
+// 任务的类型
+const (
+	// JobInitUser 初始化用户的关注关系,同步组织
+	JobInitUser = "init_user"
+	// JobInferNation 推断用户的国籍
+	JobInferNation = "infer_nation"
+	// JobInferDomain 推断用户的领域
+	JobInferDomain = "infer_domain"
+	// JobRunDiscovery 运行人才发现的查询
+	JobRunDiscovery = "run_discovery"
+	// JobEmbedUser 重新计算用户画像的向量
+	JobEmbedUser = "embed_user"
+)
+
+// JobHandler 执行一种任务,返回错误时任务会被重试
+type JobHandler func(ctx context.Context, payload []byte) error
+
+// userJob 和用户相关的任务的参数,执行时再读取最新的用户信息
+type userJob struct {
+	UserID int64 `json:"user_id"`
+}
+
+// permanentError 重试也不会成功的错误,任务直接放入死信
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent 标记不需要重试的错误
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+type JobDAOProxy interface {
+	CreateJob(ctx context.Context, job *model.Job) error
+	ClaimJob(ctx context.Context, now time.Time, lease time.Duration) (model.Job, bool, error)
+	CompleteJob(ctx context.Context, id int64) error
+	RetryJob(ctx context.Context, id int64, runAt time.Time, lastError string) error
+	DeferJob(ctx context.Context, id int64, runAt time.Time) error
+	DeadJob(ctx context.Context, id int64, lastError string) error
+	DeleteDoneJobs(ctx context.Context, before time.Time) (int64, error)
+}
+
+// JobQueue 提交任务和注册任务的处理函数
+type JobQueue interface {
+	Enqueue(ctx context.Context, typ string, payload any) error
+	Register(typ string, handler JobHandler)
+}
+
+type QueueService struct {
+	job      JobDAOProxy
+	cfg      *conf.QueueConfig
+	mu       sync.RWMutex
+	handlers map[string]JobHandler
+}
+
+func NewQueueService(job JobDAOProxy, cfg *conf.QueueConfig) *QueueService {
+	return &QueueService{
+		job:      job,
+		cfg:      cfg,
+		handlers: make(map[string]JobHandler),
+	}
+}
+
+// Register 注册任务的处理函数,需要在Start之前完成
+func (s *QueueService) Register(typ string, handler JobHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[typ] = handler
+}
+
+// Enqueue 提交一个立即执行的任务,相同类型和参数的任务还没有完成或者放入死信时不再提交
+func (s *QueueService) Enqueue(ctx context.Context, typ string, payload any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	key := typ + ":" + string(b)
+	return s.job.CreateJob(ctx, &model.Job{
+		Type:        typ,
+		Payload:     string(b),
+		DedupKey:    &key,
+		Status:      model.JobPending,
+		MaxAttempts: s.cfg.MaxAttempts,
+		RunAt:       time.Now(),
+	})
+}
+
+// Start 启动Concurrency个协程领取并执行任务,同时定时清理已经完成的任务,直到ctx结束
+func (s *QueueService) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.sweep(ctx)
+	}()
+	for i := 0; i < s.cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *QueueService) work(ctx context.Context) {
+	poll := time.Duration(s.cfg.PollInterval) * time.Millisecond
+	for ctx.Err() == nil {
+		job, ok, err := s.job.ClaimJob(ctx, time.Now(), s.lease())
+		if err != nil || !ok {
+			select {
+			case <-ctx.Done():
+			case <-time.After(poll):
+			}
+			continue
+		}
+		s.run(ctx, job)
+	}
+}
+
+// sweep 每小时删除一次超过保留时间的已完成任务
+func (s *QueueService) sweep(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		n, err := s.job.DeleteDoneJobs(ctx, time.Now().Add(-time.Duration(s.cfg.Retention)*time.Hour))
+		if err != nil {
+			log.Println("delete done jobs failed:", err)
+		} else if n > 0 {
+			log.Printf("deleted %d done jobs\n", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run 执行一个任务并记录结果
+func (s *QueueService) run(ctx context.Context, job model.Job) {
+	err := s.handle(ctx, job)
+	//记录结果时不受任务超时的影响
+	ctx = context.WithoutCancel(ctx)
+	switch {
+	case err == nil:
+		err = s.job.CompleteJob(ctx, job.ID)
+	case errors.Is(err, ErrAlreadyRunning):
+		//正在执行的可能是其他种类的工作,比如定时刷新,等它结束之后再执行一次,不计入失败次数
+		log.Printf("job %d (%s) deferred: %v\n", job.ID, job.Type, err)
+		err = s.job.DeferJob(ctx, job.ID, time.Now().Add(s.backoff(1)))
+	case !retryable(job, err):
+		log.Printf("job %d (%s) is dead after %d attempts: %v\n", job.ID, job.Type, job.Attempts, err)
+		err = s.job.DeadJob(ctx, job.ID, err.Error())
+	default:
+		log.Printf("job %d (%s) failed, attempt %d: %v\n", job.ID, job.Type, job.Attempts, err)
+		err = s.job.RetryJob(ctx, job.ID, time.Now().Add(s.backoff(job.Attempts)), err.Error())
+	}
+	if err != nil {
+		//没有记录成功的任务会在租约到期之后被重新领取
+		log.Printf("save result of job %d failed: %v\n", job.ID, err)
+	}
+}
+
+func (s *QueueService) handle(ctx context.Context, job model.Job) (err error) {
+	s.mu.RLock()
+	handler, ok := s.handlers[job.Type]
+	s.mu.RUnlock()
+	if !ok {
+		return Permanent(fmt.Errorf("no handler for job type %s", job.Type))
+	}
+	//租约到期之前结束,避免同一个任务被重复执行
+	ctx, cancel := context.WithTimeout(ctx, s.lease())
+	defer cancel()
+	ctx = context.WithValue(ctx, jobKey{}, job)
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, []byte(job.Payload))
+}
+
+// backoff 第attempts次失败之后的等待时间
+func (s *QueueService) backoff(attempts int) time.Duration {
+	d := time.Duration(s.cfg.Backoff) * time.Second
+	maxBackoff := time.Duration(s.cfg.MaxBackoff) * time.Second
+	for i := 1; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+func (s *QueueService) lease() time.Duration {
+	return time.Duration(s.cfg.Lease) * time.Second
+}
+
+// jobKey 执行中的任务在ctx中的键
+type jobKey struct{}
+
+// retryable 任务失败之后是否还会重试,工作正在执行时总是会再执行一次
+func retryable(job model.Job, err error) bool {
+	if errors.Is(err, ErrAlreadyRunning) {
+		return true
+	}
+	return !errors.As(err, new(permanentError)) && job.Attempts < job.MaxAttempts
+}
+
+// willRetry 处理函数返回err之后任务是否还会重试,不在任务中执行时返回false
+func willRetry(ctx context.Context, err error) bool {
+	job, ok := ctx.Value(jobKey{}).(model.Job)
+	return ok && err != nil && retryable(job, err)
+}
+
+// decodeUserJob 解析用户任务的参数,参数错误时重试也没有意义
+func decodeUserJob(payload []byte) (userJob, error) {
+	var job userJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return job, Permanent(err)
+	}
+	return job, nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GitEval/GitEval-Backend/conf"
+	"github.com/GitEval/GitEval-Backend/model"
+)
+
+func TestQueueBackoff(t *testing.T) {
+	tests := []struct {
+		name       string
+		backoff    int
+		maxBackoff int
+		attempts   int
+		want       time.Duration
+	}{
+		{name: "first failure", backoff: 10, maxBackoff: 600, attempts: 1, want: 10 * time.Second},
+		{name: "doubles", backoff: 10, maxBackoff: 600, attempts: 2, want: 20 * time.Second},
+		{name: "doubles again", backoff: 10, maxBackoff: 600, attempts: 4, want: 80 * time.Second},
+		{name: "capped", backoff: 10, maxBackoff: 600, attempts: 7, want: 600 * time.Second},
+		{name: "does not overflow", backoff: 10, maxBackoff: 600, attempts: 1000, want: 600 * time.Second},
+		{name: "max below backoff", backoff: 10, maxBackoff: 5, attempts: 1, want: 5 * time.Second},
+		{name: "no attempts yet", backoff: 10, maxBackoff: 600, attempts: 0, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewQueueService(nil, &conf.QueueConfig{Backoff: tt.backoff, MaxBackoff: tt.maxBackoff})
+			if got := s.backoff(tt.attempts); got != tt.want {
+				t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	failed := errors.New("failed")
+	tests := []struct {
+		name string
+		job  model.Job
+		err  error
+		want bool
+	}{
+		{name: "attempts left", job: model.Job{Attempts: 1, MaxAttempts: 3}, err: failed, want: true},
+		{name: "last attempt", job: model.Job{Attempts: 3, MaxAttempts: 3}, err: failed, want: false},
+		{name: "permanent", job: model.Job{Attempts: 1, MaxAttempts: 3}, err: Permanent(failed), want: false},
+		{name: "wrapped permanent", job: model.Job{Attempts: 1, MaxAttempts: 3}, err: fmt.Errorf("decode: %w", Permanent(failed)), want: false},
+		{name: "already running on the last attempt", job: model.Job{Attempts: 3, MaxAttempts: 3}, err: ErrAlreadyRunning, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.job, tt.err); got != tt.want {
+				t.Errorf("retryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWillRetry(t *testing.T) {
+	inJob := context.WithValue(context.Background(), jobKey{}, model.Job{Attempts: 1, MaxAttempts: 3})
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{name: "in a job", ctx: inJob, err: errors.New("failed"), want: true},
+		{name: "no error", ctx: inJob, err: nil, want: false},
+		{name: "outside a job", ctx: context.Background(), err: errors.New("failed"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := willRetry(tt.ctx, tt.err); got != tt.want {
+				t.Errorf("willRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/GitEval/GitEval-Backend/conf"
+	"github.com/GitEval/GitEval-Backend/model"
+	"log"
+	"time"
+)
+
+// 定时刷新存储时间最久的用户的资料,评分和领域
+// 通过关注关系存入的用户只有在首次存储时拉取过,排行榜和搜索中最近出现的用户优先刷新
+
+// maxActiveUsers 每次运行最多读取的最近活跃的用户数
+const maxActiveUsers = 1000
+
+type RefreshGithubProxy interface {
+	GetUserByExternalID(ctx context.Context, id int64) (model.User, error)
+	CalculateScore(ctx context.Context, id int64, name string) float64
+	GetRateRemaining(ctx context.Context) (int, error)
+}
+
+// DomainRefresher 重新推断用户的领域
+type DomainRefresher interface {
+	RefreshDomain(ctx context.Context, userId int64) error
+}
+
+type RefreshCacheProxy interface {
+	GetActiveUsers(ctx context.Context, since time.Time, limit int) ([]int64, error)
+}
+
+// ActiveUserRecorder 记录出现在排行榜或者搜索结果中的用户
+type ActiveUserRecorder interface {
+	TouchUsers(ctx context.Context, ids []int64, at time.Time) error
+}
+
+type RefreshService struct {
+	user   UserDAOProxy
+	cache  RefreshCacheProxy
+	g      RefreshGithubProxy
+	domain DomainRefresher
+	cfg    *conf.RefreshConfig
+	locker UserLocker
+}
+
+func NewRefreshService(user UserDAOProxy, cache RefreshCacheProxy, g RefreshGithubProxy, domain DomainRefresher, cfg *conf.RefreshConfig, locker UserLocker) *RefreshService {
+	return &RefreshService{
+		user:   user,
+		cache:  cache,
+		g:      g,
+		domain: domain,
+		cfg:    cfg,
+		locker: locker,
+	}
+}
+
+// Start 按照配置的间隔定时运行,没有开启时直接返回
+func (s *RefreshService) Start(ctx context.Context) {
+	if !s.cfg.Enable {
+		return
+	}
+	ticker := time.NewTicker(time.Duration(s.cfg.Interval) * time.Minute)
+	defer ticker.Stop()
+	for {
+		refreshed, err := s.Run(ctx)
+		if err != nil {
+			log.Println("refresh users failed:", err)
+		}
+		log.Printf("refresh finished, %d users refreshed\n", refreshed)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run 先刷新最近活跃的过期用户,再刷新其他过期用户,直到没有过期的用户,或者用完每次运行的预算,返回刷新的用户数
+func (s *RefreshService) Run(ctx context.Context) (int, error) {
+	now := time.Now()
+	before := now.Add(-time.Duration(s.cfg.StaleAfter) * time.Hour)
+	active, err := s.cache.GetActiveUsers(ctx, now.Add(-time.Duration(s.cfg.ActiveWindow)*time.Hour), maxActiveUsers)
+	if err != nil {
+		//读取不到活跃用户时仍然按照过期时间刷新
+		log.Println("get active users failed:", err)
+		active = []int64{}
+	}
+
+	var (
+		refreshed int
+		budget    = s.cfg.Budget
+	)
+	//ids为nil时在所有用户中选取
+	for _, ids := range [][]int64{active, nil} {
+		for {
+			users, err := s.user.GetStaleUsers(ctx, ids, before, s.cfg.BatchSize)
+			if err != nil {
+				return refreshed, err
+			}
+			if len(users) == 0 {
+				break
+			}
+			synced := make([]int64, 0, len(users))
+			for _, u := range users {
+				if ctx.Err() != nil {
+					return refreshed, ctx.Err()
+				}
+				cost := refreshCost(u)
+				if cost > budget {
+					return refreshed, nil
+				}
+				if !s.hasQuota(ctx) {
+					log.Println("refresh stopped: github rate limit is almost exhausted")
+					return refreshed, nil
+				}
+				budget -= cost
+				err := s.refresh(ctx, u)
+				if errors.Is(err, ErrAlreadyRunning) {
+					//用户正在同步,同步完成时会更新同步时间
+					continue
+				}
+				if err != nil {
+					log.Printf("refresh user %s failed: %v\n", u.LoginName, err)
+					synced = append(synced, u.ID)
+					continue
+				}
+				refreshed++
+			}
+			//刷新失败的用户同样更新同步时间,避免每次都排在最前面
+			if err := s.user.MarkSynced(ctx, synced, time.Now()); err != nil {
+				return refreshed, err
+			}
+		}
+	}
+	return refreshed, nil
+}
+
+// refresh 重新拉取用户的资料和评分,再重新推断领域
+// 通过github的用户ID获取,用户改过登录名时会更新为新的登录名
+// 推断领域在这里直接执行,消耗的请求计入每次运行的预算,仓库没有变化时直接使用缓存的推断结果
+func (s *RefreshService) refresh(ctx context.Context, u model.User) error {
+	unlock, err := s.locker.Lock(ctx, u.ID, LockSync)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	fresh, err := s.g.GetUserByExternalID(ctx, u.ExternalID)
+	if err != nil {
+		return err
+	}
+	if fresh.ID != u.ID {
+		return fmt.Errorf("github user %d resolved to user %d", u.ExternalID, fresh.ID)
+	}
+	fresh.Score = s.g.CalculateScore(ctx, fresh.ID, fresh.LoginName)
+	if err := s.user.CreateUsers(ctx, []model.User{fresh}); err != nil {
+		return err
+	}
+	//资料和评分已经更新,领域推断失败时只记录日志,之后的刷新会再推断
+	if err := s.domain.RefreshDomain(ctx, u.ID); err != nil {
+		log.Printf("refresh domain of %s failed: %v\n", fresh.LoginName, err)
+	}
+	return nil
+}
+
+func (s *RefreshService) hasQuota(ctx context.Context) bool {
+	remaining, err := s.g.GetRateRemaining(ctx)
+	if err != nil {
+		log.Println("get github rate limit failed:", err)
+		return false
+	}
+	return remaining > s.cfg.MinRemaining
+}
+
+// refreshCost 估算刷新一个用户消耗的github请求数
+// 资料和评分各一次,推断领域时star最多3页,仓库列表一次,最多20个仓库的README和提交各一次
+func refreshCost(u model.User) int {
+	return 2 + 3 + 1 + 2*min(u.PublicRepos, 20)
+}
@@ -6,7 +6,7 @@ import (
 	"gorm.io/gorm"
 )
 
-var ProviderSet = wire.NewSet(NewAuthService, NewUserService)
+var ProviderSet = wire.NewSet(NewForgeRegistry, NewAuthService, NewUserService, NewOrgService, NewPublicService, NewCrawlerService, NewDiscoveryService, NewAdminService, NewInferenceService, NewMatchService, NewEmbeddingService, NewCompareService, NewQueueService, NewSyncService, NewRefreshService, NewLockService)
 
 // Transaction 优雅实现两个表的事务
 type Transaction interface {
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/GitEval/GitEval-Backend/model"
+	"log"
+	"time"
+)
+
+// 记录登录之后初始化用户的进度,状态存储在redis中,通过发布订阅推送给所有实例上的连接
+
+// syncStatusExpire 同步状态保留的时间
+const syncStatusExpire = 24 * time.Hour
+
+// ticketExpire websocket票据的有效时间
+const ticketExpire = 30 * time.Second
+
+var (
+	ErrNoSyncStatus  = errors.New("no sync status for this user")
+	ErrInvalidTicket = errors.New("ticket is invalid or already used")
+)
+
+type SyncCacheProxy interface {
+	SetSyncStep(ctx context.Context, userID int64, step string, value []byte, expire time.Duration) error
+	ResetSync(ctx context.Context, userID int64) error
+	GetSyncSteps(ctx context.Context, userID int64) (map[string]string, error)
+	SubscribeSync(ctx context.Context, userID int64) (<-chan struct{}, func() error, error)
+	SetTicket(ctx context.Context, ticket string, userID int64, expire time.Duration) error
+	TakeTicket(ctx context.Context, ticket string) (int64, bool, error)
+}
+
+// SyncTracker 更新同步的进度,记录失败只打印日志,不影响同步本身
+type SyncTracker interface {
+	BeginSync(ctx context.Context, userId int64, steps ...string)
+	SetSyncStep(ctx context.Context, userId int64, step, state string)
+	FinishSyncStep(ctx context.Context, userId int64, step string, err error)
+}
+
+type SyncService struct {
+	cache SyncCacheProxy
+}
+
+func NewSyncService(cache SyncCacheProxy) *SyncService {
+	return &SyncService{cache: cache}
+}
+
+// BeginSync 开始一次新的同步,清除上一次的状态,所有阶段都是 pending
+func (s *SyncService) BeginSync(ctx context.Context, userId int64, steps ...string) {
+	if err := s.cache.ResetSync(ctx, userId); err != nil {
+		log.Println("reset sync status failed:", err)
+	}
+	for _, step := range steps {
+		s.SetSyncStep(ctx, userId, step, model.SyncPending)
+	}
+}
+
+// SetSyncStep 更新一个阶段的状态
+func (s *SyncService) SetSyncStep(ctx context.Context, userId int64, step, state string) {
+	s.saveStep(ctx, userId, step, model.SyncStep{State: state})
+}
+
+// FinishSyncStep 一个阶段执行结束
+// 失败之后任务还会重试时回到 pending 并保留错误,不再重试时为 failed,工作已经在执行时回到 pending
+func (s *SyncService) FinishSyncStep(ctx context.Context, userId int64, step string, err error) {
+	switch {
+	case err == nil:
+		s.saveStep(ctx, userId, step, model.SyncStep{State: model.SyncDone})
+	case errors.Is(err, ErrAlreadyRunning):
+		//等待正在执行的工作结束之后再执行,不算作错误
+		s.saveStep(ctx, userId, step, model.SyncStep{State: model.SyncPending})
+	case willRetry(ctx, err):
+		s.saveStep(ctx, userId, step, model.SyncStep{State: model.SyncPending, Error: err.Error()})
+	default:
+		s.saveStep(ctx, userId, step, model.SyncStep{State: model.SyncFailed, Error: err.Error()})
+	}
+}
+
+func (s *SyncService) saveStep(ctx context.Context, userId int64, step string, v model.SyncStep) {
+	v.UpdatedAt = time.Now()
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Println("marshal sync step failed:", err)
+		return
+	}
+	//任务快要超时时也要记录下状态
+	if err := s.cache.SetSyncStep(context.WithoutCancel(ctx), userId, step, b, syncStatusExpire); err != nil {
+		log.Println("save sync status failed:", err)
+	}
+}
+
+// GetSyncStatus 获取用户同步的整体状态,没有记录时返回 ErrNoSyncStatus
+func (s *SyncService) GetSyncStatus(ctx context.Context, userId int64) (model.SyncStatus, error) {
+	values, err := s.cache.GetSyncSteps(ctx, userId)
+	if err != nil {
+		return model.SyncStatus{}, err
+	}
+	if len(values) == 0 {
+		return model.SyncStatus{}, ErrNoSyncStatus
+	}
+	status := model.SyncStatus{UserID: userId, Steps: make(map[string]model.SyncStep, len(values))}
+	//整体的错误是最近一次失败的阶段的错误
+	var errorAt time.Time
+	for step, value := range values {
+		var v model.SyncStep
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return model.SyncStatus{}, err
+		}
+		status.Steps[step] = v
+		if v.UpdatedAt.After(status.UpdatedAt) {
+			status.UpdatedAt = v.UpdatedAt
+		}
+		if v.Error != "" && v.UpdatedAt.After(errorAt) {
+			errorAt = v.UpdatedAt
+			status.Error = v.Error
+		}
+	}
+	status.State = syncState(status.Steps)
+	return status, nil
+}
+
+// WatchSyncStatus 先推送当前的状态,之后每次整体状态变化时推送,同步结束或者ctx结束时返回
+func (s *SyncService) WatchSyncStatus(ctx context.Context, userId int64, onChange func(status model.SyncStatus) error) error {
+	//先订阅再读取状态,中间的变化不会漏掉
+	changes, unsubscribe, err := s.cache.SubscribeSync(ctx, userId)
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+
+	var last model.SyncStatus
+	for {
+		status, err := s.GetSyncStatus(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if status.State != last.State || status.Error != last.Error {
+			if err := onChange(status); err != nil {
+				return err
+			}
+			last = status
+		}
+		if status.State == model.SyncDone || status.State == model.SyncFailed {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-changes:
+			if !ok {
+				return errors.New("sync status subscription closed")
+			}
+		}
+	}
+}
+
+// CreateTicket 生成建立websocket连接使用的一次性票据
+// 浏览器的websocket不能设置请求头,只能通过地址传递凭证,地址会被记录在日志中,所以不能直接传递jwt
+func (s *SyncService) CreateTicket(ctx context.Context, userId int64) (string, error) {
+	ticket := randomToken()
+	if err := s.cache.SetTicket(ctx, ticket, userId, ticketExpire); err != nil {
+		return "", err
+	}
+	return ticket, nil
+}
+
+// RedeemTicket 使用票据,返回票据所属的用户,票据只能使用一次
+func (s *SyncService) RedeemTicket(ctx context.Context, ticket string) (int64, error) {
+	if ticket == "" {
+		return 0, ErrInvalidTicket
+	}
+	userId, ok, err := s.cache.TakeTicket(ctx, ticket)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrInvalidTicket
+	}
+	return userId, nil
+}
+
+// syncState 由各个阶段得出整体状态,任何阶段失败时为失败,否则为第一个没有完成的阶段的状态
+// 没有记录的阶段视为已经完成,比如没有重新拉取关注关系,只重新推断的用户
+func syncState(steps map[string]model.SyncStep) string {
+	for _, v := range steps {
+		if v.State == model.SyncFailed {
+			return model.SyncFailed
+		}
+	}
+	for _, step := range []string{model.SyncStepGraph, model.SyncStepDomain, model.SyncStepNation} {
+		v, ok := steps[step]
+		if !ok || v.State == model.SyncDone {
+			continue
+		}
+		//推断的任务还在排队时也视为正在推断
+		switch step {
+		case model.SyncStepDomain:
+			return model.SyncInferringDomain
+		case model.SyncStepNation:
+			return model.SyncInferringNation
+		}
+		return v.State
+	}
+	return model.SyncDone
+}
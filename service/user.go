@@ -2,14 +2,24 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	_ "errors"
 	"fmt"
+	"github.com/GitEval/GitEval-Backend/client"
 	llmv1 "github.com/GitEval/GitEval-Backend/client/gen"
+	"github.com/GitEval/GitEval-Backend/conf"
 	"github.com/GitEval/GitEval-Backend/model"
-	"github.com/google/go-github/v50/github"
+	"github.com/GitEval/GitEval-Backend/pkg/fallback"
+	"github.com/GitEval/GitEval-Backend/pkg/sanitize"
+	"google.golang.org/protobuf/proto"
+	"io"
 	"log"
+	"math"
+	"slices"
 	"sort"
+	"strings"
+	"time"
 )
 
 const (
@@ -17,15 +27,27 @@ const (
 	Followers
 )
 
+// MaxInterests 每种兴趣(语言/topic)最多保留的数量
+const MaxInterests = 10
+
 // 有关user的服务
 
 type UserDAOProxy interface {
 	CreateUsers(ctx context.Context, user []model.User) error
 	GetUserByID(ctx context.Context, id int64) (model.User, error)
+	GetUsersByIds(ctx context.Context, ids []int64) ([]model.User, error)
+	GetScoreRank(ctx context.Context, score float64) (int64, error)
 	SaveUser(ctx context.Context, user model.User) error
+	SaveEvaluation(ctx context.Context, id int64, evaluation string) error
 	GetFollowingUsersJoinContact(ctx context.Context, id int64) ([]model.User, error)
 	GetFollowersUsersJoinContact(ctx context.Context, id int64) ([]model.User, error)
-	SearchUser(ctx context.Context, nation *string, domain string, page int, pageSize int) ([]model.User, error)
+	SearchUser(ctx context.Context, nation *string, domain string, minConfidence float64, page int, pageSize int) ([]model.User, error)
+	SaveNationality(ctx context.Context, id int64, nation string, confidence float32, source string) error
+	GetUsersMissingInference(ctx context.Context, limit int) ([]model.User, error)
+	MarkInferred(ctx context.Context, ids []int64, at time.Time) error
+	GetStaleUsers(ctx context.Context, ids []int64, before time.Time, limit int) ([]model.User, error)
+	MarkSynced(ctx context.Context, ids []int64, at time.Time) error
+	GetMatchCandidates(ctx context.Context, domains []string, names []string, nation *string, minScore float64, limit int) ([]model.User, error)
 }
 
 type ContactDAOProxy interface {
@@ -36,37 +58,91 @@ type ContactDAOProxy interface {
 
 type DomainDAOProxy interface {
 	Create(ctx context.Context, domain []model.Domain) error
-	GetDomainById(ctx context.Context, id int64) ([]string, error)
+	GetDomainById(ctx context.Context, id int64) ([]model.Domain, error)
+	Delete(ctx context.Context, id int64) error
+	GetDomainsByUserIds(ctx context.Context, ids []int64) ([]model.Domain, error)
+	GetDomainNames(ctx context.Context, limit int) ([]string, error)
+}
+
+type InterestDAOProxy interface {
+	Create(ctx context.Context, interests []model.Interest) error
+	GetInterestsById(ctx context.Context, id int64) ([]model.Interest, error)
 	Delete(ctx context.Context, id int64) error
+	GetInterestsByUserIds(ctx context.Context, ids []int64) ([]model.Interest, error)
+}
+
+// FallbackInferrer llm不可用时基于规则的推断
+type FallbackInferrer interface {
+	Nation(location string, followerLoc, followingLoc []string) (string, float32)
+	Domains(repos []*model.Repo, interests []model.Interest) []fallback.Domain
 }
 
-type GithubProxy interface {
-	GetFollowing(ctx context.Context, id int64) []model.User
-	GetFollowers(ctx context.Context, id int64) []model.User
-	CalculateScore(ctx context.Context, id int64, name string) float64
-	GetAllRepositories(ctx context.Context, loginName string, userId int64) []*model.Repo
-	GetClientFromMap(userID int64) (*github.Client, bool)
-	GetAllUserEvents(ctx context.Context, username string, client *github.Client) ([]model.UserEvent, error)
+// InferenceCacheProxy 按照请求的指纹缓存llm推断的结果
+type InferenceCacheProxy interface {
+	GetInference(ctx context.Context, key string) ([]byte, bool, error)
+	SetInference(ctx context.Context, key string, value []byte, expire time.Duration) error
+}
+
+// ProfileEmbedder 领域更新之后重新计算用户画像的向量
+type ProfileEmbedder interface {
+	EmbedProfiles(ctx context.Context, profiles []Profile) error
+}
+
+type EvaluationDAOProxy interface {
+	Create(ctx context.Context, evaluation *model.Evaluation) error
+	GetLatest(ctx context.Context, userID int64) (model.Evaluation, error)
+	GetHistory(ctx context.Context, userID int64, page, pageSize int) ([]model.Evaluation, error)
 }
 
 type UserService struct {
-	user    UserDAOProxy
-	contact ContactDAOProxy
-	domain  DomainDAOProxy
-	tx      Transaction
-	g       GithubProxy
-	l       llmv1.LLMServiceClient
+	user       UserDAOProxy
+	contact    ContactDAOProxy
+	domain     DomainDAOProxy
+	interest   InterestDAOProxy
+	evaluation EvaluationDAOProxy
+	tx         Transaction
+	forges     *ForgeRegistry
+	fallback   FallbackInferrer
+	inference  *conf.InferenceConfig
+	cache      InferenceCacheProxy
+	embedder   ProfileEmbedder
+	queue      JobQueue
+	tracker    SyncTracker
+	active     ActiveUserRecorder
+	checkpoint CheckpointDAOProxy
+	refresh    *conf.RefreshConfig
+	locker     UserLocker
+	l          llmv1.LLMServiceClient
+	//推断结果缓存的版本,包含后端,模型和prompt版本
+	cacheVersion string
 }
 
-func NewUserService(user UserDAOProxy, contact ContactDAOProxy, domain DomainDAOProxy, transaction Transaction, g GithubProxy, l llmv1.LLMServiceClient) *UserService {
-	return &UserService{
-		user:    user,
-		contact: contact,
-		domain:  domain,
-		tx:      transaction,
-		g:       g,
-		l:       l,
+func NewUserService(user UserDAOProxy, contact ContactDAOProxy, domain DomainDAOProxy, interest InterestDAOProxy, evaluation EvaluationDAOProxy, transaction Transaction, forges *ForgeRegistry, fallback FallbackInferrer, inference *conf.InferenceConfig, cache InferenceCacheProxy, embedder ProfileEmbedder, queue JobQueue, tracker SyncTracker, active ActiveUserRecorder, checkpoint CheckpointDAOProxy, refresh *conf.RefreshConfig, locker UserLocker, llm *conf.LLMConfig, l llmv1.LLMServiceClient) *UserService {
+	s := &UserService{
+		user:       user,
+		contact:    contact,
+		domain:     domain,
+		interest:   interest,
+		evaluation: evaluation,
+		tx:         transaction,
+		forges:     forges,
+		fallback:   fallback,
+		inference:  inference,
+		cache:      cache,
+		embedder:   embedder,
+		queue:      queue,
+		tracker:    tracker,
+		active:     active,
+		checkpoint: checkpoint,
+		refresh:    refresh,
+		locker:     locker,
+		l:          l,
+
+		cacheVersion: client.CacheVersion(llm) + ":" + inference.CacheVersion,
 	}
+	queue.Register(JobInferNation, s.inferNation)
+	queue.Register(JobInferDomain, s.inferDomain)
+	return s
 }
 
 // InitUser 存储user,同时搜索其following和followers,将他们也存入
@@ -74,30 +150,50 @@ func (s *UserService) InitUser(ctx context.Context, u model.User) (err error) {
 	var (
 		users = make([]model.User, 0)
 	)
+	//先注册结束的回调,获取不到锁时也要更新状态
+	defer func() { s.tracker.FinishSyncStep(ctx, u.ID, model.SyncStepGraph, err) }()
+	unlock, err := s.locker.Lock(ctx, u.ID, LockSync)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	forge, err := s.forges.GetForge(u.Forge)
+	if err != nil {
+		return err
+	}
+	//客户端只保存在内存中,重启之后重新执行的任务无法获取关注关系,重试也没有意义,用户重新登录时会提交新的任务
+	if !forge.Authorized(u.ID) {
+		return Permanent(ErrLoginExpired)
+	}
 
-	following := s.g.GetFollowing(ctx, u.ID)
-	followers := s.g.GetFollowers(ctx, u.ID)
-	var (
-		followersLoc = make([]string, len(followers))
-		followingLoc = make([]string, len(following))
-	)
-
-	// 获取followers和following的Location
-	// 顺便计算他们的分数
-	for i := range followers {
-		followersLoc = append(followersLoc, followers[i].Location)
-		followers[i].Score = s.g.CalculateScore(ctx, u.ID, followers[i].LoginName)
+	s.tracker.SetSyncStep(ctx, u.ID, model.SyncStepGraph, model.SyncFetchingGraph)
+	following, followingFetched, err := s.followUsers(ctx, forge, u.ID, true)
+	if err != nil {
+		return fmt.Errorf("get following: %w", err)
+	}
+	followers, followersFetched, err := s.followUsers(ctx, forge, u.ID, false)
+	if err != nil {
+		return fmt.Errorf("get followers: %w", err)
 	}
-	users = append(users, following...)
 
+	s.tracker.SetSyncStep(ctx, u.ID, model.SyncStepGraph, model.SyncScoring)
+	// 只计算和存储重新拉取的用户的分数,最近同步过的用户保留原来的分数
+	// 推断国籍时使用的Location在存储之后再读取
 	for i := range following {
-		followingLoc = append(followingLoc, following[i].Location)
-		following[i].Score = s.g.CalculateScore(ctx, u.ID, following[i].LoginName)
+		if followingFetched[i] {
+			following[i].Score = forge.CalculateScore(ctx, u.ID, following[i].LoginName)
+			users = append(users, following[i])
+		}
+	}
+	for i := range followers {
+		if followersFetched[i] {
+			followers[i].Score = forge.CalculateScore(ctx, u.ID, followers[i].LoginName)
+			users = append(users, followers[i])
+		}
 	}
-	users = append(users, followers...)
 
 	//将user二次存入,这个地方主要是为了能够保证每次用户上号这个评分都能更新
-	u.Score = s.g.CalculateScore(ctx, u.ID, u.LoginName)
+	u.Score = forge.CalculateScore(ctx, u.ID, u.LoginName)
 	users = append(users, u)
 
 	//得到关系
@@ -122,43 +218,312 @@ func (s *UserService) InitUser(ctx context.Context, u model.User) (err error) {
 		return err
 	}
 
-	// 测试通过,花费时间大概10s
-	go func() {
-		ctx1 := context.Background()
-		//得到用户的国籍,尝试存储这个用户的国籍
-		Nation := s.generateNationality(ctx1, u.Bio, u.Company, u.Location, followersLoc, followingLoc)
-		u.Nationality = Nation
-		err := s.user.SaveUser(ctx1, u)
-		if err != nil {
-			return
-		}
-	}()
-
-	// 测试通过,花费时间大概要到10s左右
-	go func() {
-		ctx2 := context.Background()
-		//获取这个用户的主要技术领域
-		userDomain := s.generateDomain(ctx2, u.LoginName, u.Bio, u.ID)
-		//将获取的结果转化成对应的model
-		domains := StringToDomains(userDomain, u.ID)
-		//先删除之前的记录,这个地方不够优雅
-		err = s.domain.Delete(ctx2, u.ID)
+	return s.InferUser(ctx, u.ID)
+
+}
+
+// InferUser 提交推断用户国籍和技术领域的任务,推断的结果会直接存储
+func (s *UserService) InferUser(ctx context.Context, userId int64) error {
+	s.tracker.SetSyncStep(ctx, userId, model.SyncStepDomain, model.SyncPending)
+	s.tracker.SetSyncStep(ctx, userId, model.SyncStepNation, model.SyncPending)
+	return errors.Join(
+		s.queue.Enqueue(ctx, JobInferNation, userJob{UserID: userId}),
+		s.queue.Enqueue(ctx, JobInferDomain, userJob{UserID: userId}),
+	)
+}
+
+// inferNation 推断并存储用户的国籍
+// llm失败时先存储规则推断的结果,再返回错误让任务重试,之后llm成功时会替换
+func (s *UserService) inferNation(ctx context.Context, payload []byte) (err error) {
+	job, err := decodeUserJob(payload)
+	if err != nil {
+		return err
+	}
+	ctx = client.WithUserID(ctx, job.UserID)
+	defer func() { s.tracker.FinishSyncStep(ctx, job.UserID, model.SyncStepNation, err) }()
+	unlock, err := s.locker.Lock(ctx, job.UserID, LockNation)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	s.tracker.SetSyncStep(ctx, job.UserID, model.SyncStepNation, model.SyncInferringNation)
+	u, err := s.user.GetUserByID(ctx, job.UserID)
+	if err != nil {
+		return err
+	}
+	followersLoc, followingLoc, err := s.contactLocations(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+
+	nation, confidence, _, err := s.generateNationality(ctx, u.Bio, u.Company, u.Location, followersLoc, followingLoc)
+	if err == nil {
+		return s.user.SaveNationality(ctx, u.ID, nation, confidence, model.SourceLLM)
+	}
+	log.Println("failed to get Nationality:", err)
+	//已经有llm的结果时不覆盖
+	if u.NationSource == model.SourceLLM {
+		return err
+	}
+	nation, confidence = s.fallback.Nation(u.Location, followersLoc, followingLoc)
+	if nation == "" {
+		return err
+	}
+	return errors.Join(err, s.user.SaveNationality(ctx, u.ID, nation, confidence, model.SourceFallback))
+}
+
+// inferDomain 更新用户的兴趣,推断并存储用户的领域,失败时的处理和国籍一样
+func (s *UserService) inferDomain(ctx context.Context, payload []byte) (err error) {
+	job, err := decodeUserJob(payload)
+	if err != nil {
+		return err
+	}
+	ctx = client.WithUserID(ctx, job.UserID)
+	defer func() { s.tracker.FinishSyncStep(ctx, job.UserID, model.SyncStepDomain, err) }()
+	unlock, err := s.locker.Lock(ctx, job.UserID, LockDomain)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	s.tracker.SetSyncStep(ctx, job.UserID, model.SyncStepDomain, model.SyncInferringDomain)
+	return s.updateDomain(ctx, job.UserID)
+}
+
+// RefreshDomain 重新推断并存储用户的领域,不更新同步的进度,用于定时刷新
+func (s *UserService) RefreshDomain(ctx context.Context, userId int64) error {
+	ctx = client.WithUserID(ctx, userId)
+	unlock, err := s.locker.Lock(ctx, userId, LockDomain)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return s.updateDomain(ctx, userId)
+}
+
+// updateDomain 更新用户的兴趣和领域,调用方需要持有领域的锁
+func (s *UserService) updateDomain(ctx context.Context, userId int64) error {
+	u, err := s.user.GetUserByID(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	//先更新用户的兴趣,作为推断领域时的弱信号
+	interests, err := s.refreshInterests(ctx, u)
+	if err != nil {
+		log.Println("refresh interests failed:", err)
+	}
+	repos, err := s.getRepositories(ctx, u)
+	if err != nil {
+		return err
+	}
+	//获取这个用户的主要技术领域
+	domains, _, inferErr := s.generateDomain(ctx, u, repos, interests)
+	if inferErr != nil {
+		log.Println("failed to get domain:", inferErr)
+		//已经有llm的结果时不覆盖
+		current, err := s.domain.GetDomainById(ctx, u.ID)
+		if err != nil || !allFallback(current) {
+			return errors.Join(inferErr, err)
+		}
+		domains = s.fallbackDomains(u.ID, repos, interests)
+	}
+	err = s.tx.InTx(ctx, func(ctx context.Context) error {
+		//先删除之前的记录
+		if err := s.domain.Delete(ctx, u.ID); err != nil {
+			return err
+		}
+		return s.domain.Create(ctx, domains)
+	})
+	if err != nil {
+		return err
+	}
+	s.embedProfiles(ctx, []Profile{{User: u, Domains: domains, Interests: interests, Repos: repos}})
+	return inferErr
+}
+
+// lockUsers 获取每个用户所有种类的锁,返回获取成功的用户和释放所有锁的函数
+func (s *UserService) lockUsers(ctx context.Context, users []model.User, kinds ...string) ([]model.User, func()) {
+	var (
+		locked  = make([]model.User, 0, len(users))
+		unlocks []func()
+	)
+	for _, u := range users {
+		acquired := make([]func(), 0, len(kinds))
+		for _, kind := range kinds {
+			unlock, err := s.locker.Lock(ctx, u.ID, kind)
+			if err != nil {
+				break
+			}
+			acquired = append(acquired, unlock)
+		}
+		if len(acquired) < len(kinds) {
+			for _, unlock := range acquired {
+				unlock()
+			}
+			continue
+		}
+		locked = append(locked, u)
+		unlocks = append(unlocks, acquired...)
+	}
+	return locked, func() {
+		for _, unlock := range unlocks {
+			unlock()
+		}
+	}
+}
+
+// domainJob 批量推断领域时一个用户的输入
+type domainJob struct {
+	user      model.User
+	repos     []*model.Repo
+	interests []model.Interest
+}
+
+// InferBatch 批量推断用户缺少的国籍和领域,用于后台推断没有登录过的用户
+// 单个用户推断失败时使用规则推断,llm整体不可用时返回错误,这批用户不会被标记,之后会重试
+func (s *UserService) InferBatch(ctx context.Context, users []model.User) error {
+	var (
+		areaUsers []model.User
+		areaReqs  []*llmv1.GetAreaRequest
+		locations [][2][]string
+		jobs      []domainJob
+		ids       = make([]int64, 0, len(users))
+	)
+	//正在被其他任务同步或者推断的用户跳过,也不标记,之后再推断
+	locked, unlock := s.lockUsers(ctx, users, LockNation, LockDomain)
+	defer unlock()
+	for _, u := range locked {
+		ids = append(ids, u.ID)
+		if u.Nationality == "" {
+			followersLoc, followingLoc, err := s.contactLocations(ctx, u.ID)
+			if err != nil {
+				return err
+			}
+			areaUsers = append(areaUsers, u)
+			areaReqs = append(areaReqs, areaRequest(u.Bio, u.Company, u.Location, followersLoc, followingLoc))
+			locations = append(locations, [2][]string{followersLoc, followingLoc})
+		}
+
+		current, err := s.domain.GetDomainById(ctx, u.ID)
 		if err != nil {
-			return
+			return err
 		}
-		//存储domain
-		if err = s.domain.Create(ctx2, domains); err != nil {
-			return
+		if len(current) > 0 {
+			continue
+		}
+		repos, err := s.getRepositories(ctx, u)
+		if err != nil || len(repos) == 0 {
+			continue
 		}
-	}()
+		//批量推断时不刷新兴趣,节省平台的请求额度
+		interests, _ := s.interest.GetInterestsById(ctx, u.ID)
+		jobs = append(jobs, domainJob{user: u, repos: repos, interests: interests})
+	}
 
-	return nil
+	areas, ok, err := batchInfer(ctx, s, "area", areaReqs, func(ctx context.Context, reqs []*llmv1.GetAreaRequest) ([]*llmv1.GetAreaResponse, []string, error) {
+		res, err := s.l.BatchGetArea(ctx, &llmv1.BatchGetAreaRequest{Requests: reqs})
+		return res.GetResponses(), res.GetErrors(), err
+	})
+	if err != nil {
+		return err
+	}
+	for i, u := range areaUsers {
+		nation, confidence, source := "", float32(0), model.SourceLLM
+		if ok[i] {
+			nation, confidence = s.nationFromResponse(areas[i])
+		} else {
+			nation, confidence = s.fallback.Nation(u.Location, locations[i][0], locations[i][1])
+			source = model.SourceFallback
+		}
+		if nation == "" {
+			continue
+		}
+		if err := s.user.SaveNationality(ctx, u.ID, nation, confidence, source); err != nil {
+			return err
+		}
+	}
+
+	domainReqs := make([]*llmv1.GetDomainRequest, 0, len(jobs))
+	for _, job := range jobs {
+		domainReqs = append(domainReqs, s.domainRequest(job.user, job.repos, job.interests))
+	}
+	domains, ok, err := batchInfer(ctx, s, "domain", domainReqs, func(ctx context.Context, reqs []*llmv1.GetDomainRequest) ([]*llmv1.GetDomainResponse, []string, error) {
+		res, err := s.l.BatchGetDomain(ctx, &llmv1.BatchGetDomainRequest{Requests: reqs})
+		return res.GetResponses(), res.GetErrors(), err
+	})
+	if err != nil {
+		return err
+	}
+	profiles := make([]Profile, 0, len(jobs))
+	for i, job := range jobs {
+		var result []model.Domain
+		if ok[i] {
+			result = s.domainsFromResponse(job.user.ID, domains[i])
+		} else {
+			result = s.fallbackDomains(job.user.ID, job.repos, job.interests)
+		}
+		err := s.tx.InTx(ctx, func(ctx context.Context) error {
+			if err := s.domain.Delete(ctx, job.user.ID); err != nil {
+				return err
+			}
+			return s.domain.Create(ctx, result)
+		})
+		if err != nil {
+			return err
+		}
+		profiles = append(profiles, Profile{User: job.user, Domains: result, Interests: job.interests, Repos: job.repos})
+	}
+	s.embedProfiles(ctx, profiles)
 
+	return s.user.MarkInferred(ctx, ids, time.Now())
+}
+
+// batchInfer 先查缓存,只把没有命中的请求批量发给llm,返回和请求一一对应的响应
+// ok[i]为false表示这个请求单独失败了
+func batchInfer[Req, Res proto.Message](ctx context.Context, s *UserService, kind string, reqs []Req, call func(ctx context.Context, reqs []Req) ([]Res, []string, error)) (resp []Res, ok []bool, err error) {
+	resp, ok = make([]Res, len(reqs)), make([]bool, len(reqs))
+	var (
+		keys   = make([]string, len(reqs))
+		misses []int
+	)
+	for i, req := range reqs {
+		keys[i] = s.inferenceKey(kind, req)
+		resp[i] = resp[i].ProtoReflect().Type().New().Interface().(Res)
+		if s.getCachedInference(ctx, keys[i], resp[i]) {
+			ok[i] = true
+		} else {
+			misses = append(misses, i)
+		}
+	}
+	if len(misses) == 0 {
+		return resp, ok, nil
+	}
+
+	missReqs := make([]Req, 0, len(misses))
+	for _, i := range misses {
+		missReqs = append(missReqs, reqs[i])
+	}
+	res, errs, err := call(ctx, missReqs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(res) != len(misses) {
+		return nil, nil, fmt.Errorf("batch %s: expect %d responses, got %d", kind, len(misses), len(res))
+	}
+	for j, i := range misses {
+		if j < len(errs) && errs[j] != "" {
+			log.Printf("batch %s failed for request %d: %s\n", kind, i, errs[j])
+			continue
+		}
+		resp[i], ok[i] = res[j], true
+		s.setCachedInference(ctx, keys[i], res[j])
+	}
+	return resp, ok, nil
 }
 
 // GetDomains 返回用户的领域（基于主要使用的语言）
 // 接受userId，返回用户的领域
-func (s *UserService) GetDomains(ctx context.Context, userId int64) []string {
+func (s *UserService) GetDomains(ctx context.Context, userId int64) []model.Domain {
 	domains, err := s.domain.GetDomainById(ctx, userId)
 	if err != nil {
 		return nil
@@ -166,6 +531,15 @@ func (s *UserService) GetDomains(ctx context.Context, userId int64) []string {
 	return domains
 }
 
+// GetInterests 返回用户已经存储的兴趣向量
+func (s *UserService) GetInterests(ctx context.Context, userId int64) []model.Interest {
+	interests, err := s.interest.GetInterestsById(ctx, userId)
+	if err != nil {
+		return nil
+	}
+	return interests
+}
+
 // GetUserById 从ID获取用户信息
 func (s *UserService) GetUserById(ctx context.Context, id int64) (model.User, error) {
 	return s.user.GetUserByID(ctx, id)
@@ -179,8 +553,23 @@ func (s *UserService) CreateUser(ctx context.Context, u model.User) error {
 	return nil
 }
 
-// GetLeaderboard 获取排行榜
+// GetLeaderboard 获取排行榜,排行榜中的用户会被记录为最近活跃的用户
 func (s *UserService) GetLeaderboard(ctx context.Context, userId int64) ([]model.Leaderboard, error) {
+	leaderboard, err := s.BuildLeaderboard(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	//排行榜中的用户会被优先刷新
+	ids := make([]int64, 0, len(leaderboard))
+	for _, v := range leaderboard {
+		ids = append(ids, v.UserID)
+	}
+	s.touchUsers(ctx, ids)
+	return leaderboard, nil
+}
+
+// BuildLeaderboard 由用户和关注关系中的用户组成的排行榜,按照分数从高到低排序,只读取数据库
+func (s *UserService) BuildLeaderboard(ctx context.Context, userId int64) ([]model.Leaderboard, error) {
 	var (
 		leaderboard = make([]model.Leaderboard, 0)
 		err         error
@@ -222,29 +611,208 @@ func (s *UserService) GetLeaderboard(ctx context.Context, userId int64) ([]model
 	return leaderboard, nil
 }
 
-func (s *UserService) GetEvaluation(ctx context.Context, userId int64) (string, error) {
+// GetEvaluation 获取用户的评价
+// 输入和最新的评价相同时直接返回最新的评价,输入发生变化或者refresh为true时重新生成
+func (s *UserService) GetEvaluation(ctx context.Context, userId int64, refresh bool) (model.Evaluation, error) {
+	req, latest, ok, err := s.evaluationRequest(ctx, userId, refresh)
+	if err != nil {
+		return model.Evaluation{}, err
+	}
+	if ok {
+		return latest, nil
+	}
+	unlock, err := s.locker.Lock(ctx, userId, LockEvaluation)
+	if err != nil {
+		return model.Evaluation{}, err
+	}
+	defer unlock()
+
+	fingerprint := requestHash(req)
+
+	res, err := s.l.GetEvaluation(client.WithUserID(ctx, userId), req)
+	if err != nil {
+		return model.Evaluation{}, err
+	}
+
+	return s.saveEvaluation(ctx, model.Evaluation{
+		UserID:        userId,
+		Evaluation:    res.Evaluation,
+		Fingerprint:   fingerprint,
+		ModelVersion:  res.ModelVersion,
+		PromptVersion: res.PromptVersion,
+		Scores:        toEvaluationScores(res.Scores),
+		Strengths:     res.Strengths,
+		Weaknesses:    res.Weaknesses,
+		Evidence:      toEvidenceLinks(res.Evidence),
+	})
+}
+
+// StreamEvaluation 流式生成评价,每生成一段就回调一次,结构化的结果在生成完毕后返回
+// ctx取消后会同时中断llm的生成,只有完整生成的评价才会被保存
+func (s *UserService) StreamEvaluation(ctx context.Context, userId int64, refresh bool, onDelta func(delta string) error) (model.Evaluation, error) {
+	req, latest, ok, err := s.evaluationRequest(ctx, userId, refresh)
+	if err != nil {
+		return model.Evaluation{}, err
+	}
+	if ok {
+		return latest, onDelta(latest.Evaluation)
+	}
+	unlock, err := s.locker.Lock(ctx, userId, LockEvaluation)
+	if err != nil {
+		return model.Evaluation{}, err
+	}
+	defer unlock()
+
+	fingerprint := requestHash(req)
+
+	//提前返回时(例如客户端断开)取消流,中断llm的生成并记录这次调用
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := s.l.StreamEvaluation(client.WithUserID(streamCtx, userId), req)
+	if err != nil {
+		return model.Evaluation{}, err
+	}
+
+	var (
+		sb       strings.Builder
+		evaluate = model.Evaluation{UserID: userId, Fingerprint: fingerprint}
+	)
+	for {
+		res, err := stream.Recv()
+		//没有收到结束的消息时评价不完整,不能保存
+		if err == io.EOF {
+			return model.Evaluation{}, errors.New("evaluation stream ended before done")
+		}
+		if err != nil {
+			return model.Evaluation{}, err
+		}
+		if res.Delta != "" {
+			sb.WriteString(res.Delta)
+			if err := onDelta(res.Delta); err != nil {
+				return model.Evaluation{}, err
+			}
+		}
+		if res.Done {
+			evaluate.ModelVersion = res.ModelVersion
+			evaluate.PromptVersion = res.PromptVersion
+			evaluate.Scores = toEvaluationScores(res.Scores)
+			evaluate.Strengths = res.Strengths
+			evaluate.Weaknesses = res.Weaknesses
+			evaluate.Evidence = toEvidenceLinks(res.Evidence)
+			break
+		}
+	}
+
+	evaluate.Evaluation = sb.String()
+	return s.saveEvaluation(ctx, evaluate)
+}
+
+// GetEvaluationHistory 获取用户的历史评价
+func (s *UserService) GetEvaluationHistory(ctx context.Context, userId int64, page, pageSize int) ([]model.Evaluation, error) {
+	return s.evaluation.GetHistory(ctx, userId, page, pageSize)
+}
+
+// evaluationRequest 构造生成评价的请求,和最新的评价的指纹相同时ok为true,直接使用存储的评价
+// 登录过期之后获取不到新的事件,这时只要有评价就使用存储的评价,refresh为true时总是重新生成
+func (s *UserService) evaluationRequest(ctx context.Context, userId int64, refresh bool) (req *llmv1.GetEvaluationRequest, latest model.Evaluation, ok bool, err error) {
+	found := false
+	if !refresh {
+		latest, err = s.evaluation.GetLatest(ctx, userId)
+		found = err == nil
+	}
+	if found && !s.authorized(ctx, userId) {
+		return nil, latest, true, nil
+	}
+	req, err = s.buildEvaluationRequest(ctx, userId)
+	if err != nil {
+		return nil, model.Evaluation{}, false, err
+	}
+	if found && latest.Fingerprint == requestHash(req) {
+		return nil, latest, true, nil
+	}
+	return req, model.Evaluation{}, false, nil
+}
+
+// authorized 用户自己的客户端是否还在,获取不到用户时当作已经过期
+func (s *UserService) authorized(ctx context.Context, userId int64) bool {
 	user, err := s.user.GetUserByID(ctx, userId)
 	if err != nil {
-		return "", err
+		return false
+	}
+	forge, err := s.forges.GetForge(user.Forge)
+	if err != nil {
+		return false
+	}
+	return forge.Authorized(user.ID)
+}
+
+// saveEvaluation 保存评价,同时更新用户表中最新的评价
+func (s *UserService) saveEvaluation(ctx context.Context, evaluation model.Evaluation) (model.Evaluation, error) {
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.evaluation.Create(ctx, &evaluation); err != nil {
+			return err
+		}
+		return s.user.SaveEvaluation(ctx, evaluation.UserID, evaluation.Evaluation)
+	})
+	return evaluation, err
+}
+
+func toEvaluationScores(scores *llmv1.DimensionScores) model.EvaluationScores {
+	return model.EvaluationScores{
+		CodeQuality:   scores.GetCodeQuality(),
+		Activity:      scores.GetActivity(),
+		Collaboration: scores.GetCollaboration(),
+		Influence:     scores.GetInfluence(),
+		Breadth:       scores.GetBreadth(),
+	}
+}
+
+func toEvidenceLinks(evidence []*llmv1.Evidence) []model.EvidenceLink {
+	links := make([]model.EvidenceLink, 0, len(evidence))
+	for _, e := range evidence {
+		links = append(links, model.EvidenceLink{
+			Repo:   e.GetRepo(),
+			URL:    e.GetUrl(),
+			Reason: e.GetReason(),
+		})
+	}
+	return links
+}
+
+// requestHash 计算llm请求的指纹,相同的输入得到相同的指纹
+func requestHash(req proto.Message) string {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// buildEvaluationRequest 收集生成评价需要的信息
+func (s *UserService) buildEvaluationRequest(ctx context.Context, userId int64) (*llmv1.GetEvaluationRequest, error) {
+	user, err := s.user.GetUserByID(ctx, userId)
+	if err != nil {
+		return nil, err
 	}
 	followers, err := s.user.GetFollowersUsersJoinContact(ctx, userId)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	following, err := s.user.GetFollowingUsersJoinContact(ctx, userId)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	client, ok := s.g.GetClientFromMap(userId)
-	if !ok {
-		return "", errors.New("login fail!")
+	forge, err := s.forges.GetForge(user.Forge)
+	if err != nil {
+		return nil, err
 	}
 
-	events, err := s.g.GetAllUserEvents(ctx, user.LoginName, client)
+	events, err := s.getUserEvents(ctx, forge, user)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var userEvents []*llmv1.UserEvent
@@ -252,7 +820,7 @@ func (s *UserService) GetEvaluation(ctx context.Context, userId int64) (string,
 		userEvents = append(userEvents, &llmv1.UserEvent{
 			Repo: &llmv1.RepoInfo{
 				Name:             event.Repo.Name,
-				Description:      event.Repo.Description,
+				Description:      sanitize.Text(event.Repo.Description),
 				StargazersCount:  int32(event.Repo.StargazersCount),
 				ForksCount:       int32(event.Repo.ForksCount),
 				CreatedAt:        event.Repo.CreatedAt,
@@ -264,105 +832,179 @@ func (s *UserService) GetEvaluation(ctx context.Context, userId int64) (string,
 		})
 	}
 
+	//事件是按照仓库从map中取出的,顺序不固定,排序之后指纹才稳定
+	sort.Slice(userEvents, func(i, j int) bool {
+		return userEvents[i].Repo.Name < userEvents[j].Repo.Name
+	})
+
 	//此处允许获取值为空而不报错,因为可能用户没有成功获取领域就直接开始做评价了
 	domains, _ := s.domain.GetDomainById(ctx, user.ID)
-	evaluation, err := s.l.GetEvaluation(ctx, &llmv1.GetEvaluationRequest{
-		Bio:               user.Bio,
+	return &llmv1.GetEvaluationRequest{
+		Bio:               sanitize.Text(user.Bio),
 		Followers:         int32(len(followers)),
 		Following:         int32(len(following)),
 		TotalPrivateRepos: int32(user.TotalPrivateRepos),
 		TotalPublicRepos:  int32(user.PublicRepos),
 		UserEvents:        userEvents,
-		Domains:           domains,
-	})
-	if err != nil {
-		return "", err
-	}
-
-	return evaluation.Evaluation, nil
+		Domains:           domainNames(domains),
+	}, nil
 }
 
-func (s *UserService) GetNationByUserId(ctx context.Context, userId int64) (string, error) {
+// GetNationByUserId 重新推断国籍,返回国籍和置信度,输入没有变化时使用缓存的结果
+func (s *UserService) GetNationByUserId(ctx context.Context, userId int64) (string, float32, bool, error) {
+	unlock, err := s.locker.Lock(ctx, userId, LockNation)
+	if err != nil {
+		return "", 0, false, err
+	}
+	defer unlock()
 	user, err := s.user.GetUserByID(ctx, userId)
 	if err != nil {
 		log.Println("get user failed")
-		return "", err
+		return "", 0, false, err
+	}
+	followersLoc, followingLoc, err := s.contactLocations(ctx, userId)
+	if err != nil {
+		return "", 0, false, err
 	}
+
+	nation, confidence, cached, err := s.generateNationality(client.WithUserID(ctx, userId), user.Bio, user.Company, user.Location, followersLoc, followingLoc)
+	if err != nil {
+		return "", 0, false, err
+	}
+	user.Nationality, user.NationConfidence, user.NationSource = nation, confidence, model.SourceLLM
+	err = s.user.SaveUser(ctx, user)
+	if err != nil {
+		return "", 0, false, err
+	}
+
+	return nation, confidence, cached, nil
+}
+
+// contactLocations 获取followers和following的Location
+func (s *UserService) contactLocations(ctx context.Context, userId int64) (followersLoc, followingLoc []string, err error) {
 	followers, err := s.user.GetFollowersUsersJoinContact(ctx, userId)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 	following, err := s.user.GetFollowingUsersJoinContact(ctx, userId)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 
-	var (
-		followersLoc = make([]string, len(followers))
-		followingLoc = make([]string, len(following))
-	)
-
-	// 获取followers和following的Loction
+	followersLoc = make([]string, 0, len(followers))
 	for _, v := range followers {
 		followersLoc = append(followersLoc, v.Location)
 	}
-
+	followingLoc = make([]string, 0, len(following))
 	for _, v := range following {
 		followingLoc = append(followingLoc, v.Location)
 	}
+	return followersLoc, followingLoc, nil
+}
 
-	Nation := s.generateNationality(ctx, user.Bio, user.Company, user.Location, followersLoc, followingLoc)
-	user.Nationality = Nation
-	err = s.user.SaveUser(ctx, user)
+// GetDomainByUserId 重新推断领域,输入没有变化时使用缓存的结果
+func (s *UserService) GetDomainByUserId(ctx context.Context, userId int64) ([]model.Domain, bool, error) {
+	unlock, err := s.locker.Lock(ctx, userId, LockDomain)
 	if err != nil {
-		return "", err
+		return nil, false, err
 	}
-
-	return Nation, nil
-}
-
-func (s *UserService) GetDomainByUserId(ctx context.Context, userId int64) ([]string, error) {
+	defer unlock()
 	user, err := s.user.GetUserByID(ctx, userId)
 	if err != nil {
 		log.Println("get user failed")
-		return nil, err
+		return nil, false, err
+	}
+
+	//兴趣获取失败不影响领域的推断
+	interests, _ := s.interest.GetInterestsById(ctx, user.ID)
+	repos, err := s.getRepositories(ctx, user)
+	if err != nil {
+		return nil, false, err
+	}
+	domains, cached, err := s.generateDomain(client.WithUserID(ctx, userId), user, repos, interests)
+	if err != nil {
+		return nil, false, err
+	}
+	err = s.tx.InTx(ctx, func(ctx context.Context) error {
+		//先删除之前的记录
+		if err := s.domain.Delete(ctx, user.ID); err != nil {
+			return err
+		}
+		//存储domain
+		return s.domain.Create(ctx, domains)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	//画像的向量通过任务队列更新,不影响请求的耗时,失败时按照队列的规则重试
+	if err := s.queue.Enqueue(ctx, JobEmbedUser, userJob{UserID: userId}); err != nil {
+		log.Println("enqueue embedding failed:", err)
 	}
+	return domains, cached, nil
+}
 
-	userDomain := s.generateDomain(ctx, user.LoginName, user.Bio, user.ID)
-	//将获取的结果转化成对应的model
-	domains := StringToDomains(userDomain, user.ID)
-	//先删除之前的记录
-	err = s.domain.Delete(ctx, user.ID)
+func (s *UserService) SearchUser(ctx context.Context, nation *string, domain string, minConfidence float64, page int, pageSize int) ([]model.User, error) {
+	users, err := s.user.SearchUser(ctx, nation, domain, minConfidence, page, pageSize)
 	if err != nil {
-		return []string{}, err
+		return nil, err
+	}
+	//搜索结果中的用户会被优先刷新
+	ids := make([]int64, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
 	}
+	s.touchUsers(ctx, ids)
+	return users, nil
+}
 
-	//存储domain
-	if err := s.domain.Create(ctx, domains); err != nil {
-		return []string{}, err
+// touchUsers 记录最近活跃的用户,失败时只打印日志
+func (s *UserService) touchUsers(ctx context.Context, ids []int64) {
+	if err := s.active.TouchUsers(ctx, ids, time.Now()); err != nil {
+		log.Println("touch users failed:", err)
 	}
+}
 
-	var resp []string
-	for _, domain := range domains {
-		resp = append(resp, domain.Domain)
+// embedProfiles 更新画像的向量,失败时只记录日志,之后由后台补全
+func (s *UserService) embedProfiles(ctx context.Context, profiles []Profile) {
+	if len(profiles) == 0 {
+		return
+	}
+	if err := s.embedder.EmbedProfiles(ctx, profiles); err != nil {
+		log.Println("embed profiles failed:", err)
 	}
-	return resp, nil
+}
 
+// fallbackDomains 基于规则推断领域
+func (s *UserService) fallbackDomains(userID int64, repos []*model.Repo, interests []model.Interest) []model.Domain {
+	domains := s.fallback.Domains(repos, interests)
+	resp := make([]model.Domain, 0, len(domains))
+	for _, d := range domains {
+		resp = append(resp, model.Domain{
+			UserID:     userID,
+			Domain:     d.Domain,
+			Confidence: d.Confidence,
+			Source:     model.SourceFallback,
+		})
+	}
+	return resp
 }
 
-func (s *UserService) SearchUser(ctx context.Context, nation *string, domain string, page int, pageSize int) ([]model.User, error) {
-	return s.user.SearchUser(ctx, nation, domain, page, pageSize)
+// allFallback 领域为空或者全部是规则推断的结果时才可以被规则推断覆盖
+func allFallback(domains []model.Domain) bool {
+	for _, d := range domains {
+		if d.Source != model.SourceFallback {
+			return false
+		}
+	}
+	return true
 }
 
-func StringToDomains(lang []string, id int64) []model.Domain {
-	var (
-		domainsModel = make([]model.Domain, len(lang))
-	)
-	for k, v := range lang {
-		domainsModel[k].UserID = id
-		domainsModel[k].Domain = v
+func domainNames(domains []model.Domain) []string {
+	names := make([]string, 0, len(domains))
+	for _, d := range domains {
+		names = append(names, d.Domain)
 	}
-	return domainsModel
+	return names
 }
 
 // 从users中得到相应的关系
@@ -413,55 +1055,221 @@ func removeTheSame(s []model.Leaderboard) []model.Leaderboard {
 	return result
 }
 
-// 生成国籍
-func (s *UserService) generateNationality(ctx context.Context, bio, company, location string, followerLoc, followingloc []string) string {
-	res, err := s.l.GetArea(ctx, &llmv1.GetAreaRequest{
-		Bio:            bio,
-		Company:        company,
-		Location:       location,
+// 生成国籍,置信度低于阈值时国籍为N/A,cached表示结果来自缓存
+func (s *UserService) generateNationality(ctx context.Context, bio, company, location string, followerLoc, followingloc []string) (nation string, confidence float32, cached bool, err error) {
+	req := areaRequest(bio, company, location, followerLoc, followingloc)
+	key := s.inferenceKey("area", req)
+	res := &llmv1.GetAreaResponse{}
+	cached = s.getCachedInference(ctx, key, res)
+	if !cached {
+		res, err = s.l.GetArea(ctx, req)
+		if err != nil {
+			return "", 0, false, err
+		}
+		s.setCachedInference(ctx, key, res)
+	}
+	nation, confidence = s.nationFromResponse(res)
+	return nation, confidence, cached, nil
+}
+
+// 生成领域,置信度低于阈值的领域不会返回,cached表示结果来自缓存
+func (s *UserService) generateDomain(ctx context.Context, u model.User, repos []*model.Repo, interests []model.Interest) ([]model.Domain, bool, error) {
+	if len(repos) == 0 {
+		return nil, false, nil
+	}
+
+	req := s.domainRequest(u, repos, interests)
+	key := s.inferenceKey("domain", req)
+	domains := &llmv1.GetDomainResponse{}
+	cached := s.getCachedInference(ctx, key, domains)
+	if !cached {
+		var err error
+		domains, err = s.l.GetDomain(ctx, req)
+		if err != nil {
+			return nil, false, err
+		}
+		s.setCachedInference(ctx, key, domains)
+	}
+	return s.domainsFromResponse(u.ID, domains), cached, nil
+}
+
+// areaRequest 构造推断国籍的请求
+func areaRequest(bio, company, location string, followerLoc, followingloc []string) *llmv1.GetAreaRequest {
+	//公司和地点都是用户自己填写的,和简介一样需要清洗
+	//关注关系的顺序不影响推断,排序之后相同的输入才能命中缓存
+	followerLoc, followingloc = sanitize.Texts(followerLoc), sanitize.Texts(followingloc)
+	slices.Sort(followerLoc)
+	slices.Sort(followingloc)
+	return &llmv1.GetAreaRequest{
+		Bio:            sanitize.Text(bio),
+		Company:        sanitize.Text(company),
+		Location:       sanitize.Text(location),
 		FollowerAreas:  followerLoc,
 		FollowingAreas: followingloc,
-	})
-	if err != nil {
-		log.Println(errors.New("failed to get Nationality"))
-		return ""
 	}
-	//添加置信度
-	nation := fmt.Sprintf("%s|(trust:%.2f)", res.Area, res.Confidence)
-	return nation
 }
 
-func (s *UserService) generateDomain(ctx context.Context, LoginName, bio string, userId int64) []string {
-	repos := s.g.GetAllRepositories(ctx, LoginName, userId)
-	if len(repos) == 0 {
-		return nil
+// nationFromResponse 阈值在使用时才生效,缓存的是llm原始的结果
+func (s *UserService) nationFromResponse(res *llmv1.GetAreaResponse) (string, float32) {
+	if float64(res.Confidence) < s.inference.NationThreshold {
+		return model.NationUnknown, res.Confidence
 	}
+	return res.Area, res.Confidence
+}
 
+// domainRequest 构造推断领域的请求
+func (s *UserService) domainRequest(u model.User, repos []*model.Repo, interests []model.Interest) *llmv1.GetDomainRequest {
 	// 使用 make 来预分配切片大小，提升性能
 	r := make([]*llmv1.Repo, 0, len(repos))
 	for _, v := range repos {
 		repo := &llmv1.Repo{
 			Name:     v.Name,
 			Language: v.Language,
-			Readme:   v.Readme,
+			Readme:   sanitize.Readme(v.Readme, s.inference.ReadmeTokens),
 			Commit:   v.Commit,
 		}
 		r = append(r, repo)
 	}
+	//平台返回仓库的顺序不固定,排序之后相同的输入才能命中缓存
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].Name < r[j].Name
+	})
+	return &llmv1.GetDomainRequest{
+		Repos:     r,
+		Bio:       sanitize.Text(u.Bio),
+		Interests: InterestsToStrings(interests),
+	}
+}
+
+// domainsFromResponse 置信度低于阈值的领域不会返回
+func (s *UserService) domainsFromResponse(userID int64, res *llmv1.GetDomainResponse) []model.Domain {
+	resp := make([]model.Domain, 0, len(res.Domains))
+	for _, domain := range res.Domains {
+		if float64(domain.Confidence) < s.inference.DomainThreshold {
+			continue
+		}
+		resp = append(resp, model.Domain{
+			UserID:     userID,
+			Domain:     domain.Domain,
+			Confidence: domain.Confidence,
+			Source:     model.SourceLLM,
+		})
+	}
+	return resp
+}
 
-	domains, err := s.l.GetDomain(ctx, &llmv1.GetDomainRequest{
-		Repos: r,
-		Bio:   bio,
+// inferenceKey 缓存的key包含缓存版本,更换后端,模型或者prompt之后不会读到之前的结果
+func (s *UserService) inferenceKey(kind string, req proto.Message) string {
+	return kind + ":" + textHash(s.cacheVersion+"\n"+requestHash(req))
+}
+
+// getCachedInference 读取缓存的推断结果,缓存不可用时当作没有命中
+func (s *UserService) getCachedInference(ctx context.Context, key string, res proto.Message) bool {
+	if s.inference.CacheTTL <= 0 {
+		return false
+	}
+	b, ok, err := s.cache.GetInference(ctx, key)
+	if err != nil {
+		log.Println("get cached inference failed:", err)
+		return false
+	}
+	return ok && proto.Unmarshal(b, res) == nil
+}
+
+// setCachedInference 缓存llm推断的结果,失败时只记录日志
+func (s *UserService) setCachedInference(ctx context.Context, key string, res proto.Message) {
+	if s.inference.CacheTTL <= 0 {
+		return
+	}
+	b, err := proto.Marshal(res)
+	if err != nil {
+		return
+	}
+	if err := s.cache.SetInference(ctx, key, b, time.Duration(s.inference.CacheTTL)*time.Hour); err != nil {
+		log.Println("cache inference failed:", err)
+	}
+}
+
+// refreshInterests 拉取用户star的仓库,重新聚合并存储用户的兴趣向量
+func (s *UserService) refreshInterests(ctx context.Context, u model.User) ([]model.Interest, error) {
+	forge, err := s.forges.GetForge(u.Forge)
+	if err != nil {
+		return nil, err
+	}
+	starred := forge.GetStarredRepositories(ctx, u.LoginName, u.ID)
+	if len(starred) == 0 {
+		return nil, nil
+	}
+
+	interests := aggregateInterests(starred, u.ID)
+	err = s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.interest.Delete(ctx, u.ID); err != nil {
+			return err
+		}
+		return s.interest.Create(ctx, interests)
 	})
 	if err != nil {
-		log.Println(errors.New("failed to get domain"))
-		return nil
+		return nil, err
+	}
+	return interests, nil
+}
+
+// aggregateInterests 将star的仓库聚合成语言和topic两部分的兴趣向量,每部分的权重之和为1
+// star数越多的仓库越大众,越不能体现个人兴趣,所以按照star数做对数衰减
+func aggregateInterests(repos []*model.StarredRepo, userId int64) []model.Interest {
+	var (
+		languages = make(map[string]float64)
+		topics    = make(map[string]float64)
+	)
+	for _, repo := range repos {
+		w := 1 / (1 + math.Log10(1+float64(repo.StargazersCount)))
+		if repo.Language != "" {
+			languages[repo.Language] += w
+		}
+		for _, topic := range repo.Topics {
+			topics[topic] += w
+		}
+	}
+
+	resp := make([]model.Interest, 0, 2*MaxInterests)
+	resp = append(resp, topInterests(languages, model.InterestKindLanguage, userId)...)
+	resp = append(resp, topInterests(topics, model.InterestKindTopic, userId)...)
+	return resp
+}
+
+// topInterests 取权重最大的 MaxInterests 个并归一化
+func topInterests(weights map[string]float64, kind string, userId int64) []model.Interest {
+	var (
+		interests = make([]model.Interest, 0, len(weights))
+		total     float64
+	)
+	for name, w := range weights {
+		interests = append(interests, model.Interest{UserID: userId, Kind: kind, Name: name, Weight: w})
 	}
+	sort.Slice(interests, func(i, j int) bool {
+		if interests[i].Weight == interests[j].Weight {
+			return interests[i].Name < interests[j].Name
+		}
+		return interests[i].Weight > interests[j].Weight
+	})
+	if len(interests) > MaxInterests {
+		interests = interests[:MaxInterests]
+	}
+
+	for _, v := range interests {
+		total += v.Weight
+	}
+	for k := range interests {
+		interests[k].Weight /= total
+	}
+	return interests
+}
 
-	// 添加置信度并格式化输出
-	resp := make([]string, 0, len(domains.Domains))
-	for _, domain := range domains.Domains {
-		resp = append(resp, fmt.Sprintf("%s|(trust:%.2f)", domain.Domain, domain.Confidence))
+// InterestsToStrings 转化成传给LLM的格式,例如 topic:kubernetes(0.12)
+func InterestsToStrings(interests []model.Interest) []string {
+	resp := make([]string, 0, len(interests))
+	for _, v := range interests {
+		resp = append(resp, fmt.Sprintf("%s:%s(%.2f)", v.Kind, v.Name, v.Weight))
 	}
 	return resp
 }
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/GitEval/GitEval-Backend/model"
+)
+
+func TestAggregateInterests(t *testing.T) {
+	tests := []struct {
+		name  string
+		repos []*model.StarredRepo
+		want  []model.Interest
+	}{
+		{
+			name:  "empty",
+			repos: nil,
+			want:  []model.Interest{},
+		},
+		{
+			name: "same stars split evenly",
+			repos: []*model.StarredRepo{
+				{Language: "Go", Topics: []string{"kubernetes"}},
+				{Language: "Rust", Topics: []string{"kubernetes", "wasm"}},
+			},
+			want: []model.Interest{
+				{UserID: 1, Kind: model.InterestKindLanguage, Name: "Go", Weight: 0.5},
+				{UserID: 1, Kind: model.InterestKindLanguage, Name: "Rust", Weight: 0.5},
+				{UserID: 1, Kind: model.InterestKindTopic, Name: "kubernetes", Weight: 2.0 / 3},
+				{UserID: 1, Kind: model.InterestKindTopic, Name: "wasm", Weight: 1.0 / 3},
+			},
+		},
+		{
+			name: "popular repos weigh less",
+			repos: []*model.StarredRepo{
+				{Language: "Go", StargazersCount: 9},
+				{Language: "C"},
+			},
+			// 权重分别为 1/2 和 1
+			want: []model.Interest{
+				{UserID: 1, Kind: model.InterestKindLanguage, Name: "C", Weight: 2.0 / 3},
+				{UserID: 1, Kind: model.InterestKindLanguage, Name: "Go", Weight: 1.0 / 3},
+			},
+		},
+		{
+			name: "repo without language only counts topics",
+			repos: []*model.StarredRepo{
+				{Topics: []string{"awesome"}},
+			},
+			want: []model.Interest{
+				{UserID: 1, Kind: model.InterestKindTopic, Name: "awesome", Weight: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInterests(t, aggregateInterests(tt.repos, 1), tt.want)
+		})
+	}
+}
+
+func TestTopInterests(t *testing.T) {
+	many := make(map[string]float64, MaxInterests+2)
+	for i := 0; i < MaxInterests+2; i++ {
+		many[fmt.Sprintf("t%02d", i)] = float64(i + 1)
+	}
+
+	tests := []struct {
+		name      string
+		weights   map[string]float64
+		wantNames []string
+	}{
+		{
+			name:      "sorted by weight",
+			weights:   map[string]float64{"a": 1, "b": 3, "c": 2},
+			wantNames: []string{"b", "c", "a"},
+		},
+		{
+			name:      "ties sorted by name",
+			weights:   map[string]float64{"b": 1, "a": 1},
+			wantNames: []string{"a", "b"},
+		},
+		{
+			name:      "keeps the top MaxInterests",
+			weights:   many,
+			wantNames: []string{"t11", "t10", "t09", "t08", "t07", "t06", "t05", "t04", "t03", "t02"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := topInterests(tt.weights, model.InterestKindTopic, 1)
+			if len(got) != len(tt.wantNames) {
+				t.Fatalf("got %d interests, want %d", len(got), len(tt.wantNames))
+			}
+			var total float64
+			for i, v := range got {
+				if v.Name != tt.wantNames[i] {
+					t.Errorf("interests[%d] = %q, want %q", i, v.Name, tt.wantNames[i])
+				}
+				total += v.Weight
+			}
+			if math.Abs(total-1) > 1e-9 {
+				t.Errorf("weights sum to %v, want 1", total)
+			}
+		})
+	}
+}
+
+func assertInterests(t *testing.T, got, want []model.Interest) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d interests, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.UserID != w.UserID || g.Kind != w.Kind || g.Name != w.Name || math.Abs(g.Weight-w.Weight) > 1e-9 {
+			t.Errorf("interests[%d] = %+v, want %+v", i, g, w)
+		}
+	}
+}
@@ -14,7 +14,11 @@ import (
 	"github.com/GitEval/GitEval-Backend/model"
 	"github.com/GitEval/GitEval-Backend/model/cache"
 	"github.com/GitEval/GitEval-Backend/pkg"
+	"github.com/GitEval/GitEval-Backend/pkg/fallback"
+	"github.com/GitEval/GitEval-Backend/pkg/gitea"
 	"github.com/GitEval/GitEval-Backend/pkg/github"
+	"github.com/GitEval/GitEval-Backend/pkg/gitlab"
+	"github.com/GitEval/GitEval-Backend/pkg/vector"
 	"github.com/GitEval/GitEval-Backend/service"
 	"github.com/google/wire"
 )
@@ -31,17 +35,74 @@ func WireApp(confPath string) (route.App, func()) {
 		pkg.ProviderSet,
 		middleware.ProviderSet,
 		wire.Bind(new(middleware.ParTokener), new(*middleware.JWTClient)),
+		wire.Bind(new(middleware.RateLimiter), new(*cache.RedisClient)),
 		wire.Bind(new(route.AuthControllerProxy), new(*controller.AuthController)),
 		wire.Bind(new(route.UserControllerProxy), new(*controller.UserController)),
+		wire.Bind(new(route.OrgControllerProxy), new(*controller.OrgController)),
+		wire.Bind(new(route.PublicControllerProxy), new(*controller.PublicController)),
+		wire.Bind(new(route.DiscoveryControllerProxy), new(*controller.DiscoveryController)),
+		wire.Bind(new(route.MatchControllerProxy), new(*controller.MatchController)),
+		wire.Bind(new(route.SimilarControllerProxy), new(*controller.SimilarController)),
+		wire.Bind(new(route.CompareControllerProxy), new(*controller.CompareController)),
+		wire.Bind(new(route.SyncControllerProxy), new(*controller.SyncController)),
+		wire.Bind(new(route.AdminControllerProxy), new(*controller.AdminController)),
+		wire.Bind(new(route.CrawlerWorker), new(*service.CrawlerService)),
+		wire.Bind(new(route.InferenceWorker), new(*service.InferenceService)),
+		wire.Bind(new(route.EmbeddingWorker), new(*service.EmbeddingService)),
+		wire.Bind(new(route.QueueWorker), new(*service.QueueService)),
+		wire.Bind(new(route.RefreshWorker), new(*service.RefreshService)),
 		wire.Bind(new(controller.UserServiceProxy), new(*service.UserService)),
 		wire.Bind(new(controller.GenerateJWTer), new(*middleware.JWTClient)),
 		wire.Bind(new(controller.AuthServiceProxy), new(*service.AuthService)),
-		wire.Bind(new(service.GitHubAPIProxy), new(*github.GitHubAPI)),
+		wire.Bind(new(controller.OrgServiceProxy), new(*service.OrgService)),
+		wire.Bind(new(controller.PublicServiceProxy), new(*service.PublicService)),
+		wire.Bind(new(controller.DiscoveryServiceProxy), new(*service.DiscoveryService)),
+		wire.Bind(new(controller.MatchServiceProxy), new(*service.MatchService)),
+		wire.Bind(new(controller.SimilarServiceProxy), new(*service.EmbeddingService)),
+		wire.Bind(new(controller.CompareServiceProxy), new(*service.CompareService)),
+		wire.Bind(new(controller.SyncServiceProxy), new(*service.SyncService)),
+		wire.Bind(new(controller.AdminServiceProxy), new(*service.AdminService)),
+		wire.Bind(new(client.AuditDAOProxy), new(*model.GormLLMAuditDAO)),
+		wire.Bind(new(service.GithubForge), new(*github.GitHubAPI)),
+		wire.Bind(new(service.GitLabForge), new(*gitlab.GitLabAPI)),
+		wire.Bind(new(service.GiteaForge), new(*gitea.GiteaAPI)),
 		wire.Bind(new(service.UserServiceProxy), new(*service.UserService)),
+		wire.Bind(new(service.OrgServiceProxy), new(*service.OrgService)),
+		wire.Bind(new(service.UserInferrer), new(*service.UserService)),
+		wire.Bind(new(service.BatchInferrer), new(*service.UserService)),
+		wire.Bind(new(service.DomainRefresher), new(*service.UserService)),
+		wire.Bind(new(service.ProfileEmbedder), new(*service.EmbeddingService)),
+		wire.Bind(new(service.CompareUserResolver), new(*service.PublicService)),
+		wire.Bind(new(service.LeaderboardProxy), new(*service.UserService)),
+		wire.Bind(new(service.JobQueue), new(*service.QueueService)),
+		wire.Bind(new(service.SyncTracker), new(*service.SyncService)),
+		wire.Bind(new(service.UserLocker), new(*service.LockService)),
+		wire.Bind(new(service.VectorIndex), new(vector.Index)),
+		wire.Bind(new(service.PublicCacheProxy), new(*cache.RedisClient)),
+		wire.Bind(new(service.InferenceCacheProxy), new(*cache.RedisClient)),
+		wire.Bind(new(service.SyncCacheProxy), new(*cache.RedisClient)),
+		wire.Bind(new(service.RefreshCacheProxy), new(*cache.RedisClient)),
+		wire.Bind(new(service.ActiveUserRecorder), new(*cache.RedisClient)),
+		wire.Bind(new(service.LockCacheProxy), new(*cache.RedisClient)),
 		wire.Bind(new(service.UserDAOProxy), new(*model.GormUserDAO)),
 		wire.Bind(new(service.ContactDAOProxy), new(*model.GormContactDAO)),
 		wire.Bind(new(service.DomainDAOProxy), new(*model.GormDomainDAO)),
-		wire.Bind(new(service.GithubProxy), new(*github.GitHubAPI)),
+		wire.Bind(new(service.InterestDAOProxy), new(*model.GormInterestDAO)),
+		wire.Bind(new(service.EvaluationDAOProxy), new(*model.GormEvaluationDAO)),
+		wire.Bind(new(service.FallbackInferrer), new(*fallback.Inferrer)),
+		wire.Bind(new(service.OrganizationDAOProxy), new(*model.GormOrganizationDAO)),
+		wire.Bind(new(service.DiscoveryDAOProxy), new(*model.GormDiscoveryDAO)),
+		wire.Bind(new(service.LLMAuditDAOProxy), new(*model.GormLLMAuditDAO)),
+		wire.Bind(new(service.EmbeddingDAOProxy), new(*model.GormEmbeddingDAO)),
+		wire.Bind(new(service.JobDAOProxy), new(*model.GormJobDAO)),
+		wire.Bind(new(service.DeadJobDAOProxy), new(*model.GormJobDAO)),
+		wire.Bind(new(service.CheckpointDAOProxy), new(*model.GormCheckpointDAO)),
+		wire.Bind(new(service.OrgGithubProxy), new(*github.GitHubAPI)),
+		wire.Bind(new(service.PublicGithubProxy), new(*github.GitHubAPI)),
+		wire.Bind(new(service.CrawlerGithubProxy), new(*github.GitHubAPI)),
+		wire.Bind(new(service.InferenceGithubProxy), new(*github.GitHubAPI)),
+		wire.Bind(new(service.DiscoveryGithubProxy), new(*github.GitHubAPI)),
+		wire.Bind(new(service.RefreshGithubProxy), new(*github.GitHubAPI)),
 		wire.Bind(new(service.Transaction), new(*model.Data)),
 	))
 }
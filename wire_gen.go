@@ -14,8 +14,12 @@ import (
 	"github.com/GitEval/GitEval-Backend/middleware"
 	"github.com/GitEval/GitEval-Backend/model"
 	"github.com/GitEval/GitEval-Backend/model/cache"
+	"github.com/GitEval/GitEval-Backend/pkg/fallback"
+	"github.com/GitEval/GitEval-Backend/pkg/gitea"
 	"github.com/GitEval/GitEval-Backend/pkg/github"
 	"github.com/GitEval/GitEval-Backend/pkg/github/expireMap"
+	"github.com/GitEval/GitEval-Backend/pkg/gitlab"
+	"github.com/GitEval/GitEval-Backend/pkg/vector"
 	"github.com/GitEval/GitEval-Backend/service"
 )
 
@@ -29,23 +33,69 @@ func WireApp(confPath string) (route.App, func()) {
 	gormUserDAO := model.NewGormUserDAO(data)
 	gormContactDAO := model.NewGormContactDAO(data)
 	gormDomainDAO := model.NewGormDomainDAO(data)
+	gormInterestDAO := model.NewGormInterestDAO(data)
+	gormEvaluationDAO := model.NewGormEvaluationDAO(data)
 	gitHubConfig := conf.NewGitHubConfig(vipperSetting)
 	expireMapExpireMap, cleanup := expireMap.NewExpireMap()
 	gitHubAPI := github.NewGitHubAPI(gitHubConfig, expireMapExpireMap)
-	llmConfig := conf.NewLLMConfig(vipperSetting)
-	llmServiceClient := client.NewLLMClient(llmConfig)
-	userService := service.NewUserService(gormUserDAO, gormContactDAO, gormDomainDAO, data, gitHubAPI, llmServiceClient)
-	authService := service.NewAuthService(userService, gitHubAPI, llmServiceClient)
-	jwtConfig := conf.NewJWTConfig(vipperSetting)
+	gitLabConfig := conf.NewGitLabConfig(vipperSetting)
+	gitLabAPI := gitlab.NewGitLabAPI(gitLabConfig, expireMapExpireMap)
+	giteaConfig := conf.NewGiteaConfig(vipperSetting)
+	giteaAPI := gitea.NewGiteaAPI(giteaConfig, expireMapExpireMap)
+	forgeRegistry := service.NewForgeRegistry(gitHubAPI, gitLabAPI, giteaAPI)
+	inferrer := fallback.NewInferrer()
+	inferenceConfig := conf.NewInferenceConfig(vipperSetting)
 	cacheConf := conf.NewCacheConfig(vipperSetting)
 	redisClient := cache.NewRedisClient(cacheConf)
+	gormEmbeddingDAO := model.NewGormEmbeddingDAO(data)
+	embeddingConfig := conf.NewEmbeddingConfig(vipperSetting)
+	index := vector.NewIndex(embeddingConfig)
+	gormCheckpointDAO := model.NewGormCheckpointDAO(data)
+	gormJobDAO := model.NewGormJobDAO(data)
+	queueConfig := conf.NewQueueConfig(vipperSetting)
+	queueService := service.NewQueueService(gormJobDAO, queueConfig)
+	llmConfig := conf.NewLLMConfig(vipperSetting)
+	llmClient := client.NewLLMClient(llmConfig)
+	gormLLMAuditDAO := model.NewGormLLMAuditDAO(data)
+	llmServiceClient := client.NewAuditClient(llmClient, gormLLMAuditDAO, llmConfig)
+	embeddingService := service.NewEmbeddingService(gormUserDAO, gormDomainDAO, gormInterestDAO, gormEmbeddingDAO, index, gormCheckpointDAO, queueService, embeddingConfig, llmServiceClient)
+	syncService := service.NewSyncService(redisClient)
+	refreshConfig := conf.NewRefreshConfig(vipperSetting)
+	lockConfig := conf.NewLockConfig(vipperSetting)
+	lockService := service.NewLockService(redisClient, lockConfig)
+	userService := service.NewUserService(gormUserDAO, gormContactDAO, gormDomainDAO, gormInterestDAO, gormEvaluationDAO, data, forgeRegistry, inferrer, inferenceConfig, redisClient, embeddingService, queueService, syncService, redisClient, gormCheckpointDAO, refreshConfig, lockService, llmConfig, llmServiceClient)
+	gormOrganizationDAO := model.NewGormOrganizationDAO(data)
+	orgService := service.NewOrgService(gormOrganizationDAO, gormUserDAO, data, gitHubAPI)
+	authService := service.NewAuthService(userService, orgService, forgeRegistry, queueService, syncService, llmServiceClient)
+	jwtConfig := conf.NewJWTConfig(vipperSetting)
 	jwtClient := middleware.NewJWTClient(jwtConfig, redisClient)
 	authController := controller.NewAuthController(authService, jwtClient)
 	userController := controller.NewUserController(userService)
-	middlewareMiddleware := middleware.NewMiddleware(jwtClient)
-	engine := route.NewRouter(authController, userController, middlewareMiddleware)
+	orgController := controller.NewOrgController(orgService)
+	publicConfig := conf.NewPublicConfig(vipperSetting)
+	publicService := service.NewPublicService(gormUserDAO, gormDomainDAO, gormInterestDAO, gitHubAPI, redisClient, userService, publicConfig)
+	publicController := controller.NewPublicController(publicService)
+	gormDiscoveryDAO := model.NewGormDiscoveryDAO(data)
+	discoveryService := service.NewDiscoveryService(gormDiscoveryDAO, gormUserDAO, gitHubAPI, userService, queueService)
+	discoveryController := controller.NewDiscoveryController(discoveryService)
+	matchService := service.NewMatchService(gormUserDAO, gormDomainDAO, gormInterestDAO, llmServiceClient)
+	matchController := controller.NewMatchController(matchService)
+	similarController := controller.NewSimilarController(embeddingService)
+	compareService := service.NewCompareService(gormUserDAO, gormDomainDAO, gormInterestDAO, gormEvaluationDAO, gormCheckpointDAO, forgeRegistry, publicService, userService, llmServiceClient)
+	compareController := controller.NewCompareController(compareService)
 	appConf := conf.NewAppConf(vipperSetting)
-	app := route.NewApp(engine, appConf)
+	syncController := controller.NewSyncController(syncService, appConf)
+	adminService := service.NewAdminService(gormLLMAuditDAO, gormJobDAO)
+	adminController := controller.NewAdminController(adminService)
+	adminConfig := conf.NewAdminConfig(vipperSetting)
+	middlewareMiddleware := middleware.NewMiddleware(jwtClient, redisClient, publicConfig, adminConfig)
+	engine := route.NewRouter(authController, userController, orgController, publicController, discoveryController, matchController, similarController, compareController, syncController, adminController, middlewareMiddleware, appConf)
+	crawlerConfig := conf.NewCrawlerConfig(vipperSetting)
+	crawlerService := service.NewCrawlerService(gormUserDAO, gormContactDAO, data, gitHubAPI, crawlerConfig)
+	batchInferenceConfig := conf.NewBatchInferenceConfig(vipperSetting)
+	inferenceService := service.NewInferenceService(gormUserDAO, userService, gitHubAPI, batchInferenceConfig)
+	refreshService := service.NewRefreshService(gormUserDAO, redisClient, gitHubAPI, userService, refreshConfig, lockService)
+	app := route.NewApp(engine, appConf, crawlerService, inferenceService, embeddingService, queueService, refreshService)
 	return app, func() {
 		cleanup()
 	}